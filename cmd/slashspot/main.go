@@ -1,6 +1,7 @@
 package main
 
 import (
+	"flag"
 	"log"
 
 	"github.com/jasonholmberg/slashspot/internal"
@@ -8,9 +9,16 @@ import (
 )
 
 func main() {
+	fakeNow := flag.String("fake-now", "", "debug: run as if it were this time, RFC3339 or YYYY-MM-DD in SPOT_TIMEZONE")
+	migrateUsers := flag.String("migrate-users", "", "migrate the spot store from user names to IDs using this JSON file of names to IDs, then exit")
+	flag.Parse()
 	err := godotenv.Load()
 	if err != nil {
 		log.Fatal("Error loading .env file", err)
 	}
-	internal.Run()
+	if *migrateUsers != "" {
+		internal.MigrateUsers(*migrateUsers)
+		return
+	}
+	internal.Run(*fakeNow)
 }
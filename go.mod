@@ -6,5 +6,6 @@ require (
 	github.com/joho/godotenv v1.3.0
 	github.com/nlopes/slack v0.6.0
 	github.com/stretchr/testify v1.2.2
+	go.etcd.io/bbolt v1.3.5
 	gotest.tools/v3 v3.0.0
 )
@@ -0,0 +1,63 @@
+package data
+
+import "encoding/json"
+
+// assignments - the collection holder assignments are kept in
+const assignments = "assignments"
+
+// Assignment - who holds a spot, set by an admin. An assignment takes the place of the holder in the catalog.
+type Assignment struct {
+	// ID - The spot identifier
+	ID string
+
+	// Holder - The user who holds the spot, empty when no one does
+	Holder string `json:",omitempty"`
+
+	// AssignedBy - The admin who made the assignment
+	AssignedBy string
+
+	// AssignedAt - When the assignment was made, RFC3339
+	AssignedAt string
+
+	// Location - The office or site the spot is at
+	Location string `json:",omitempty"`
+}
+
+// Key - the key for this assignment
+func (a Assignment) Key() string {
+	return LocationKey(a.Location, a.ID)
+}
+
+// GetAssignment - get the assignment of a spot
+func GetAssignment(r Reader, key string) (Assignment, error) {
+	var a Assignment
+	err := r.GetRecord(assignments, key, &a)
+	return a, err
+}
+
+// ListAssignments - list every assignment keyed by location and spot ID
+func ListAssignments(r Reader) (map[string]Assignment, error) {
+	records, err := r.ListRecords(assignments)
+	if err != nil {
+		return nil, err
+	}
+	as := make(map[string]Assignment, len(records))
+	for k, record := range records {
+		var a Assignment
+		if err := json.Unmarshal(record, &a); err != nil {
+			return nil, err
+		}
+		as[k] = a
+	}
+	return as, nil
+}
+
+// PutAssignment - add or replace an assignment
+func PutAssignment(tx Tx, a Assignment) error {
+	return tx.PutRecord(assignments, a.Key(), a)
+}
+
+// DeleteAssignment - delete an assignment
+func DeleteAssignment(tx Tx, key string) error {
+	return tx.DeleteRecord(assignments, key)
+}
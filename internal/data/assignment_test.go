@@ -0,0 +1,21 @@
+package data
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAssignments(t *testing.T) {
+	m := NewMemoryStore()
+	a := Assignment{ID: "B1", Holder: "slackuser", AssignedBy: "boss", AssignedAt: "2020-01-08T09:30:00Z"}
+	assert.Nil(t, PutAssignment(m, a))
+	got, err := GetAssignment(m, "B1")
+	assert.Nil(t, err)
+	assert.Equal(t, a, got)
+	as, _ := ListAssignments(m)
+	assert.Equal(t, map[string]Assignment{"B1": a}, as)
+	assert.Nil(t, DeleteAssignment(m, "B1"))
+	_, err = GetAssignment(m, "B1")
+	assert.Equal(t, ErrNotFound, err)
+}
@@ -0,0 +1,348 @@
+package data
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// recordsPrefix - the prefix of the buckets that hold record collections
+const recordsPrefix = "records/"
+
+var (
+	spotsBucket  = []byte("spots")
+	byIDBucket   = []byte("spots_by_id")
+	byDateBucket = []byte("spots_by_date")
+	byUserBucket = []byte("spots_by_user")
+
+	// indexSep - separates the indexed value from the spot key in index entries
+	indexSep = []byte{0}
+)
+
+// index - an index bucket and the spot value it indexes
+type index struct {
+	bucket []byte
+	value  func(s Spot) string
+}
+
+var indexes = []index{
+	{bucket: byIDBucket, value: func(s Spot) string { return s.ID }},
+	{bucket: byDateBucket, value: func(s Spot) string { return s.OpenDate }},
+	{bucket: byUserBucket, value: func(s Spot) string { return s.RegisteredBy }},
+}
+
+// BoltStore - a Store kept in an embedded, single file bolt database
+type BoltStore struct {
+	db *bolt.DB
+}
+
+type boltTx struct {
+	tx *bolt.Tx
+}
+
+// NewBoltStore - open the bolt store at path, the database is created if it does not exist
+func NewBoltStore(path string) (*BoltStore, error) {
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		return nil, err
+	}
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: 5 * time.Second})
+	if err != nil {
+		return nil, err
+	}
+	err = db.Update(func(tx *bolt.Tx) error {
+		for _, name := range [][]byte{spotsBucket, byIDBucket, byDateBucket, byUserBucket} {
+			if _, err := tx.CreateBucketIfNotExists(name); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return &BoltStore{db: db}, nil
+}
+
+// Get - get a spot
+func (b *BoltStore) Get(key string) (s Spot, err error) {
+	err = b.View(func(tx Reader) error {
+		s, err = tx.Get(key)
+		return err
+	})
+	return s, err
+}
+
+// List - list all spots
+func (b *BoltStore) List() (spots map[string]Spot, err error) {
+	err = b.View(func(tx Reader) error {
+		spots, err = tx.List()
+		return err
+	})
+	return spots, err
+}
+
+// ListByID - list the registrations of a spot
+func (b *BoltStore) ListByID(id string) (spots map[string]Spot, err error) {
+	err = b.View(func(tx Reader) error {
+		spots, err = tx.ListByID(id)
+		return err
+	})
+	return spots, err
+}
+
+// ListByDate - list spots open between two dates
+func (b *BoltStore) ListByDate(from string, to string) (spots map[string]Spot, err error) {
+	err = b.View(func(tx Reader) error {
+		spots, err = tx.ListByDate(from, to)
+		return err
+	})
+	return spots, err
+}
+
+// ListByUser - list spots registered by a user
+func (b *BoltStore) ListByUser(user string) (spots map[string]Spot, err error) {
+	err = b.View(func(tx Reader) error {
+		spots, err = tx.ListByUser(user)
+		return err
+	})
+	return spots, err
+}
+
+// GetRecord - get a record
+func (b *BoltStore) GetRecord(collection string, key string, v interface{}) error {
+	return b.View(func(tx Reader) error {
+		return tx.GetRecord(collection, key, v)
+	})
+}
+
+// ListRecords - list the records in a collection
+func (b *BoltStore) ListRecords(collection string) (records map[string][]byte, err error) {
+	err = b.View(func(tx Reader) error {
+		records, err = tx.ListRecords(collection)
+		return err
+	})
+	return records, err
+}
+
+// ListRecordRange - list the records in a collection with keys between two keys
+func (b *BoltStore) ListRecordRange(collection string, from string, to string) (records map[string][]byte, err error) {
+	err = b.View(func(tx Reader) error {
+		records, err = tx.ListRecordRange(collection, from, to)
+		return err
+	})
+	return records, err
+}
+
+// Put - add or replace a spot
+func (b *BoltStore) Put(s Spot) error {
+	return b.Update(func(tx Tx) error {
+		return tx.Put(s)
+	})
+}
+
+// Delete - delete a spot
+func (b *BoltStore) Delete(key string) error {
+	return b.Update(func(tx Tx) error {
+		return tx.Delete(key)
+	})
+}
+
+// PutRecord - add or replace a record
+func (b *BoltStore) PutRecord(collection string, key string, v interface{}) error {
+	return b.Update(func(tx Tx) error {
+		return tx.PutRecord(collection, key, v)
+	})
+}
+
+// DeleteRecord - delete a record
+func (b *BoltStore) DeleteRecord(collection string, key string) error {
+	return b.Update(func(tx Tx) error {
+		return tx.DeleteRecord(collection, key)
+	})
+}
+
+// Update - run fn in a read-write bolt transaction
+func (b *BoltStore) Update(fn func(tx Tx) error) error {
+	return b.db.Update(func(tx *bolt.Tx) error {
+		return fn(boltTx{tx})
+	})
+}
+
+// Close - close the database
+func (b *BoltStore) Close() error {
+	return b.db.Close()
+}
+
+// View - run fn in a read-only bolt transaction, many can run alongside each other and an update
+func (b *BoltStore) View(fn func(r Reader) error) error {
+	return b.db.View(func(tx *bolt.Tx) error {
+		return fn(boltTx{tx})
+	})
+}
+
+func (b boltTx) Get(key string) (Spot, error) {
+	var s Spot
+	v := b.tx.Bucket(spotsBucket).Get([]byte(key))
+	if v == nil {
+		return s, ErrNotFound
+	}
+	err := json.Unmarshal(v, &s)
+	return s, err
+}
+
+func (b boltTx) List() (map[string]Spot, error) {
+	spots := make(map[string]Spot)
+	err := b.tx.Bucket(spotsBucket).ForEach(func(k, v []byte) error {
+		var s Spot
+		if err := json.Unmarshal(v, &s); err != nil {
+			return err
+		}
+		spots[string(k)] = s
+		return nil
+	})
+	return spots, err
+}
+
+func (b boltTx) ListByID(id string) (map[string]Spot, error) {
+	return b.scan(byIDBucket, id, id)
+}
+
+func (b boltTx) ListByDate(from string, to string) (map[string]Spot, error) {
+	return b.scan(byDateBucket, from, to)
+}
+
+func (b boltTx) ListByUser(user string) (map[string]Spot, error) {
+	return b.scan(byUserBucket, user, user)
+}
+
+// scan - walk an index from one value to another, inclusive, and load the spots it points at
+func (b boltTx) scan(bucket []byte, from string, to string) (map[string]Spot, error) {
+	spots := make(map[string]Spot)
+	c := b.tx.Bucket(bucket).Cursor()
+	for k, _ := c.Seek([]byte(from)); k != nil; k, _ = c.Next() {
+		i := bytes.Index(k, indexSep)
+		if i < 0 {
+			continue
+		}
+		if to != "" && string(k[:i]) > to {
+			break
+		}
+		s, err := b.Get(string(k[i+1:]))
+		if err != nil {
+			return nil, err
+		}
+		spots[s.Key()] = s
+	}
+	return spots, nil
+}
+
+func (b boltTx) Put(s Spot) error {
+	if err := b.Delete(s.Key()); err != nil {
+		return err
+	}
+	v, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	if err := b.tx.Bucket(spotsBucket).Put([]byte(s.Key()), v); err != nil {
+		return err
+	}
+	for _, idx := range indexes {
+		if err := b.tx.Bucket(idx.bucket).Put(indexKey(idx.value(s), s.Key()), nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (b boltTx) Delete(key string) error {
+	s, err := b.Get(key)
+	if err == ErrNotFound {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	for _, idx := range indexes {
+		if err := b.tx.Bucket(idx.bucket).Delete(indexKey(idx.value(s), key)); err != nil {
+			return err
+		}
+	}
+	return b.tx.Bucket(spotsBucket).Delete([]byte(key))
+}
+
+func (b boltTx) GetRecord(collection string, key string, v interface{}) error {
+	bucket := b.tx.Bucket(recordsBucket(collection))
+	if bucket == nil {
+		return ErrNotFound
+	}
+	r := bucket.Get([]byte(key))
+	if r == nil {
+		return ErrNotFound
+	}
+	return json.Unmarshal(r, v)
+}
+
+func (b boltTx) ListRecords(collection string) (map[string][]byte, error) {
+	records := make(map[string][]byte)
+	bucket := b.tx.Bucket(recordsBucket(collection))
+	if bucket == nil {
+		return records, nil
+	}
+	err := bucket.ForEach(func(k, v []byte) error {
+		// bolt values are only valid for the life of the transaction
+		records[string(k)] = append([]byte(nil), v...)
+		return nil
+	})
+	return records, err
+}
+
+// ListRecordRange - walk the collection's keys in order from one key to another, inclusive
+func (b boltTx) ListRecordRange(collection string, from string, to string) (map[string][]byte, error) {
+	records := make(map[string][]byte)
+	bucket := b.tx.Bucket(recordsBucket(collection))
+	if bucket == nil {
+		return records, nil
+	}
+	c := bucket.Cursor()
+	for k, v := c.Seek([]byte(from)); k != nil; k, v = c.Next() {
+		if to != "" && string(k) > to {
+			break
+		}
+		records[string(k)] = append([]byte(nil), v...)
+	}
+	return records, nil
+}
+
+func (b boltTx) PutRecord(collection string, key string, v interface{}) error {
+	r, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	bucket, err := b.tx.CreateBucketIfNotExists(recordsBucket(collection))
+	if err != nil {
+		return err
+	}
+	return bucket.Put([]byte(key), r)
+}
+
+func (b boltTx) DeleteRecord(collection string, key string) error {
+	bucket := b.tx.Bucket(recordsBucket(collection))
+	if bucket == nil {
+		return nil
+	}
+	return bucket.Delete([]byte(key))
+}
+
+func recordsBucket(collection string) []byte {
+	return []byte(recordsPrefix + collection)
+}
+
+func indexKey(value string, key string) []byte {
+	return bytes.Join([][]byte{[]byte(value), []byte(key)}, indexSep)
+}
@@ -0,0 +1,133 @@
+package data
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func lookupSpots() []Spot {
+	return []Spot{
+		{ID: "B1", OpenDate: "2020-01-05", RegDate: "2020-01-05", RegisteredBy: "slackuser"},
+		{ID: "B1", OpenDate: "2020-01-06", RegDate: "2020-01-05", RegisteredBy: "slackuser"},
+		{ID: "B10", OpenDate: "2020-01-06", RegDate: "2020-01-05", RegisteredBy: "FredsMom"},
+		{ID: "B2", OpenDate: "2020-01-07", RegDate: "2020-01-05", RegisteredBy: "slackuser2"},
+	}
+}
+
+func newTestBoltStore(t *testing.T) (*BoltStore, func()) {
+	path, cleanup := testFilePath(t)
+	b, err := NewBoltStore(path)
+	if err != nil {
+		cleanup()
+		t.Fatal(err)
+	}
+	return b, func() {
+		b.Close()
+		cleanup()
+	}
+}
+
+// testLookups - exercises the indexed lookups of any store
+func testLookups(t *testing.T, store Store) {
+	for _, s := range lookupSpots() {
+		assert.Nil(t, store.Put(s))
+	}
+	tests := []struct {
+		name   string
+		lookup func() (map[string]Spot, error)
+		want   []string
+	}{
+		{
+			name:   "should list by id",
+			lookup: func() (map[string]Spot, error) { return store.ListByID("B1") },
+			want:   []string{"B1-2020-01-05", "B1-2020-01-06"},
+		},
+		{
+			name:   "should list by date",
+			lookup: func() (map[string]Spot, error) { return store.ListByDate("2020-01-06", "2020-01-06") },
+			want:   []string{"B1-2020-01-06", "B10-2020-01-06"},
+		},
+		{
+			name:   "should list up to a date",
+			lookup: func() (map[string]Spot, error) { return store.ListByDate("", "2020-01-06") },
+			want:   []string{"B1-2020-01-05", "B1-2020-01-06", "B10-2020-01-06"},
+		},
+		{
+			name:   "should list from a date",
+			lookup: func() (map[string]Spot, error) { return store.ListByDate("2020-01-07", "") },
+			want:   []string{"B2-2020-01-07"},
+		},
+		{
+			name:   "should list by user",
+			lookup: func() (map[string]Spot, error) { return store.ListByUser("slackuser") },
+			want:   []string{"B1-2020-01-05", "B1-2020-01-06"},
+		},
+		{
+			name:   "should list nothing",
+			lookup: func() (map[string]Spot, error) { return store.ListByUser("nobody") },
+			want:   []string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.lookup()
+			assert.Nil(t, err)
+			assert.Equal(t, len(tt.want), len(got))
+			for _, k := range tt.want {
+				assert.Contains(t, got, k)
+			}
+		})
+	}
+}
+
+func TestBoltStore_Lookups(t *testing.T) {
+	b, cleanup := newTestBoltStore(t)
+	defer cleanup()
+	testLookups(t, b)
+}
+
+func TestBoltStore_Put(t *testing.T) {
+	b, cleanup := newTestBoltStore(t)
+	defer cleanup()
+	s := testSpot()
+	assert.Nil(t, b.Put(s))
+	s.RegisteredBy = "someoneelse"
+	assert.Nil(t, b.Put(s), "should replace the spot")
+	got, err := b.Get(s.Key())
+	assert.Nil(t, err)
+	assert.Equal(t, s, got)
+	spots, _ := b.ListByUser("slackuser")
+	assert.Equal(t, 0, len(spots), "should drop the old index entry")
+}
+
+func TestBoltStore_Delete(t *testing.T) {
+	b, cleanup := newTestBoltStore(t)
+	defer cleanup()
+	assert.Nil(t, b.Put(testSpot()))
+	assert.Nil(t, b.Delete(testSpot().Key()))
+	assert.Nil(t, b.Delete(testSpot().Key()), "deleting a missing spot is not an error")
+	_, err := b.Get(testSpot().Key())
+	assert.Equal(t, ErrNotFound, err)
+	spots, _ := b.ListByID(testSpot().ID)
+	assert.Equal(t, 0, len(spots))
+}
+
+func TestBoltStore_Update(t *testing.T) {
+	b, cleanup := newTestBoltStore(t)
+	defer cleanup()
+	err := b.Update(func(tx Tx) error {
+		tx.Put(testSpot())
+		return errors.New("nope")
+	})
+	assert.NotNil(t, err)
+	spots, _ := b.List()
+	assert.Equal(t, 0, len(spots), "should roll back changes")
+}
+
+func TestBoltStore_Records(t *testing.T) {
+	b, cleanup := newTestBoltStore(t)
+	defer cleanup()
+	testRecords(t, b)
+}
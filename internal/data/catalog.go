@@ -0,0 +1,111 @@
+package data
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+const (
+	// catalog - the collection catalog spots are kept in
+	catalog = "catalog"
+
+	// GarageFilter - the prefix of a filter on the garage, like garage:north
+	GarageFilter = "garage:"
+
+	// LevelFilter - the prefix of a filter on the level, like level:2
+	LevelFilter = "level:"
+)
+
+// CatalogSpot - a known spot and what it is like
+type CatalogSpot struct {
+	// ID - The spot identifier
+	ID string
+
+	// Garage - The garage or lot the spot is in
+	Garage string `json:",omitempty"`
+
+	// Level - The level of the garage the spot is on
+	Level string `json:",omitempty"`
+
+	// Attributes - What else is special about the spot, like ev, compact or ada
+	Attributes []string `json:",omitempty"`
+
+	// Holder - The user the spot is assigned to, empty when no one holds it
+	Holder string `json:",omitempty"`
+
+	// Location - The office or site the spot is at
+	Location string `json:",omitempty"`
+}
+
+// Key - the key for this catalog spot
+func (c CatalogSpot) Key() string {
+	return LocationKey(c.Location, c.ID)
+}
+
+// Matches - returns true if the spot passes the filter. A filter is garage:<garage>, level:<level> or an attribute,
+// all compared without case.
+func (c CatalogSpot) Matches(filter string) bool {
+	filter = strings.ToLower(strings.TrimSpace(filter))
+	switch {
+	case strings.HasPrefix(filter, GarageFilter):
+		return strings.EqualFold(c.Garage, strings.TrimPrefix(filter, GarageFilter))
+	case strings.HasPrefix(filter, LevelFilter):
+		return strings.EqualFold(c.Level, strings.TrimPrefix(filter, LevelFilter))
+	}
+	for _, attr := range c.Attributes {
+		if strings.EqualFold(attr, filter) {
+			return true
+		}
+	}
+	return false
+}
+
+// LoadCatalog - read a catalog from a JSON list of spots
+func LoadCatalog(r io.Reader) ([]CatalogSpot, error) {
+	var spots []CatalogSpot
+	if err := json.NewDecoder(r).Decode(&spots); err != nil {
+		return nil, err
+	}
+	for _, c := range spots {
+		if strings.TrimSpace(c.ID) == "" {
+			return nil, fmt.Errorf("catalog spot %+v has no ID", c)
+		}
+	}
+	return spots, nil
+}
+
+// GetCatalogSpot - get a spot from the catalog
+func GetCatalogSpot(r Reader, key string) (CatalogSpot, error) {
+	var c CatalogSpot
+	err := r.GetRecord(catalog, key, &c)
+	return c, err
+}
+
+// ListCatalog - list every spot in the catalog keyed by location and spot ID
+func ListCatalog(r Reader) (map[string]CatalogSpot, error) {
+	records, err := r.ListRecords(catalog)
+	if err != nil {
+		return nil, err
+	}
+	spots := make(map[string]CatalogSpot, len(records))
+	for k, record := range records {
+		var c CatalogSpot
+		if err := json.Unmarshal(record, &c); err != nil {
+			return nil, err
+		}
+		spots[k] = c
+	}
+	return spots, nil
+}
+
+// PutCatalogSpot - add or replace a spot in the catalog
+func PutCatalogSpot(tx Tx, c CatalogSpot) error {
+	return tx.PutRecord(catalog, c.Key(), c)
+}
+
+// DeleteCatalogSpot - delete a spot from the catalog
+func DeleteCatalogSpot(tx Tx, key string) error {
+	return tx.DeleteRecord(catalog, key)
+}
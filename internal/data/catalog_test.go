@@ -0,0 +1,64 @@
+package data
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCatalogSpot_Matches(t *testing.T) {
+	c := CatalogSpot{ID: "B1", Garage: "North", Level: "2", Attributes: []string{"ev", "compact"}}
+	tests := []struct {
+		name   string
+		filter string
+		want   bool
+	}{
+		{name: "should match an attribute", filter: "ev", want: true},
+		{name: "should match an attribute without case", filter: "Compact", want: true},
+		{name: "should match the level", filter: "level:2", want: true},
+		{name: "should match the garage without case", filter: "garage:north", want: true},
+		{name: "should not match a missing attribute", filter: "ada", want: false},
+		{name: "should not match another level", filter: "level:3", want: false},
+		{name: "should not match another garage", filter: "garage:south", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := c.Matches(tt.filter); got != tt.want {
+				t.Errorf("CatalogSpot.Matches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadCatalog(t *testing.T) {
+	got, err := LoadCatalog(strings.NewReader(`[
+		{"ID": "B1", "Garage": "North", "Level": "2", "Attributes": ["ev"]},
+		{"ID": "B2", "Holder": "slackuser"}
+	]`))
+	assert.Nil(t, err)
+	assert.Equal(t, []CatalogSpot{
+		{ID: "B1", Garage: "North", Level: "2", Attributes: []string{"ev"}},
+		{ID: "B2", Holder: "slackuser"},
+	}, got)
+
+	_, err = LoadCatalog(strings.NewReader(`[{"Garage": "North"}]`))
+	assert.NotNil(t, err, "should not load a spot without an ID")
+
+	_, err = LoadCatalog(strings.NewReader(`{"ID": "B1"}`))
+	assert.NotNil(t, err, "should not load anything but a list")
+}
+
+func TestCatalog(t *testing.T) {
+	m := NewMemoryStore()
+	c := CatalogSpot{ID: "B1", Level: "2", Attributes: []string{"ev"}}
+	assert.Nil(t, PutCatalogSpot(m, c))
+	got, err := GetCatalogSpot(m, "B1")
+	assert.Nil(t, err)
+	assert.Equal(t, c, got)
+	spots, _ := ListCatalog(m)
+	assert.Equal(t, map[string]CatalogSpot{"B1": c}, spots)
+	assert.Nil(t, DeleteCatalogSpot(m, "B1"))
+	_, err = GetCatalogSpot(m, "B1")
+	assert.Equal(t, ErrNotFound, err)
+}
@@ -3,88 +3,91 @@ package data
 import (
 	"bytes"
 	"encoding/json"
-	"errors"
 	"io"
+	"io/ioutil"
 	"log"
 	"os"
 	"path/filepath"
-	"sync"
 )
 
-const (
-	add = "add"
-	drop = "drop"
-)
-
-var store map[string]Spot
-var lock sync.Mutex
+// fileVersion - the version of the data file layout. Files without a version are a plain map of spots.
+const fileVersion = 2
 
-// Open - open the spot store
-func Open() {
-	store = make(map[string]Spot)
-	Load()
+// FileStore - a Store kept in memory and rewritten to a JSON file on every change
+type FileStore struct {
+	*MemoryStore
+	path string
 }
 
-// IsOpen - data store is open
-func IsOpen() bool {
-	return store != nil
+// storeFile - the layout of the data file
+type storeFile struct {
+	Version int
+	Spots   map[string]Spot
+	Records map[string]map[string]json.RawMessage `json:",omitempty"`
 }
 
-func save() error {
-	// Save to disk
-	os.MkdirAll(os.Getenv("SPOT_DATA_DIR"), os.ModePerm)
-	f, err := os.Create(FilePath())
-	defer f.Close()
-	if err != nil {
-		log.Printf("Error saving spot store %v", err)
+// NewFileStore - open the file store at path, the file is created if it does not exist
+func NewFileStore(path string) (*FileStore, error) {
+	fs := &FileStore{
+		MemoryStore: NewMemoryStore(),
+		path:        path,
 	}
-	if store == nil {
-		store = make(map[string]Spot)
-		return nil
-	}
-	r, err := marshal(store)
-	if err != nil {
-		errMsg := "Error marshalling spot-store"
-		return errors.New(errMsg)
-	}
-	_, err = io.Copy(f, r)
-	return err
-}
-
-// Load - load the data file
-func Load() (map[string]Spot, error) {
-	// Load from disk
-	f, err := os.Open(FilePath())
-	defer f.Close()
+	data, err := fs.load()
 	if err != nil {
-		log.Print("No data file to load, creating one")
-		save()
+		return nil, err
 	}
-	unmarshal(f, &store)
-	return store, nil
+	fs.data = data
+	fs.commit = fs.save
+	return fs, nil
 }
 
-// Drop - drop the spot
-func Drop(s Spot) {
-	persist(s, drop)
+// Path - path to the data file
+func (fs *FileStore) Path() string {
+	return fs.path
 }
 
-// Add - add the spot
-func Add(s Spot) {
-	persist(s, add)
+func (fs *FileStore) load() (snapshot, error) {
+	data := newSnapshot()
+	f, err := os.Open(fs.path)
+	if os.IsNotExist(err) {
+		log.Print("No data file to load, creating one")
+		return data, fs.save(data)
+	}
+	if err != nil {
+		return data, err
+	}
+	defer f.Close()
+	if err := unmarshal(f, &data); err != nil && err != io.EOF {
+		return data, err
+	}
+	return data, nil
 }
 
-// Persist - applys changes to the map and saves
-func persist(s Spot, op string) {
-	lock.Lock()
-	defer lock.Unlock()
-	defer save()
-	if op == add {
-		store[s.Key()] = s
+// save - write the data to a temp file and move it over the data file so a failed write never leaves a partial store
+func (fs *FileStore) save(data snapshot) error {
+	dir := filepath.Dir(fs.path)
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return err
+	}
+	r, err := marshal(data)
+	if err != nil {
+		log.Printf("Error marshalling spot store %v", err)
+		return err
 	}
-	if op == drop {
-		delete(store, s.Key())
+	f, err := ioutil.TempFile(dir, filepath.Base(fs.path)+".tmp")
+	if err != nil {
+		log.Printf("Error saving spot store %v", err)
+		return err
 	}
+	defer os.Remove(f.Name())
+	if _, err = io.Copy(f, r); err != nil {
+		f.Close()
+		return err
+	}
+	if err = f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), fs.path)
 }
 
 // FilePath - path to data file
@@ -92,14 +95,53 @@ func FilePath() string {
 	return filepath.Join(os.Getenv("SPOT_DATA_DIR"), os.Getenv("SPOT_DATA_FILE"))
 }
 
-func marshal(spots map[string]Spot) (io.Reader, error) {
-	b, err := json.MarshalIndent(spots, "", "\t")
+func marshal(data snapshot) (io.Reader, error) {
+	file := storeFile{
+		Version: fileVersion,
+		Spots:   data.spots,
+		Records: make(map[string]map[string]json.RawMessage, len(data.records)),
+	}
+	for collection, records := range data.records {
+		file.Records[collection] = make(map[string]json.RawMessage, len(records))
+		for k, r := range records {
+			file.Records[collection][k] = r
+		}
+	}
+	b, err := json.MarshalIndent(file, "", "\t")
 	if err != nil {
 		return nil, err
 	}
 	return bytes.NewReader(b), nil
 }
 
-func unmarshal(r io.Reader, data *map[string]Spot) error {
-	return json.NewDecoder(r).Decode(data)
+// unmarshal - read a data file, older files that are only a map of spots are read as well
+func unmarshal(r io.Reader, data *snapshot) error {
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	if len(bytes.TrimSpace(b)) == 0 {
+		return io.EOF
+	}
+	var version struct{ Version int }
+	if err := json.Unmarshal(b, &version); err != nil {
+		return err
+	}
+	if version.Version == 0 {
+		return json.Unmarshal(b, &data.spots)
+	}
+	var file storeFile
+	if err := json.Unmarshal(b, &file); err != nil {
+		return err
+	}
+	if file.Spots != nil {
+		data.spots = file.Spots
+	}
+	for collection, records := range file.Records {
+		data.records[collection] = make(map[string][]byte, len(records))
+		for k, r := range records {
+			data.records[collection][k] = r
+		}
+	}
+	return nil
 }
@@ -1,19 +1,16 @@
 package data
 
 import (
+	"errors"
+	"io/ioutil"
 	"os"
+	"path/filepath"
 	"reflect"
 	"testing"
 
-	"github.com/joho/godotenv"
 	"github.com/stretchr/testify/assert"
 )
 
-func init() {
-	godotenv.Load("../../config/test.env")
-	os.MkdirAll(os.Getenv("SPOT_DATA_DIR"), os.ModePerm)
-}
-
 var (
 	dataStr = `{
 		"B1-2020-01-05": {
@@ -43,103 +40,56 @@ var (
 	}`
 )
 
-func setupTestStore() {
-	f, err := os.Create(FilePath())
-	defer f.Close()
-	if err != nil {
-		panic(err)
-	}
-	_, err = f.WriteString(dataStr)
+// testFilePath - a data file path in a fresh temp dir, call the returned func to clean up
+func testFilePath(t *testing.T) (string, func()) {
+	dir, err := ioutil.TempDir("", "spot-data")
 	if err != nil {
-		panic(err)
+		t.Fatal(err)
 	}
+	return filepath.Join(dir, "spot.store"), func() { os.RemoveAll(dir) }
 }
 
-func cleanup() {
-	os.Remove(FilePath())
-	store = nil
+func setupTestStore(t *testing.T, path string) {
+	if err := ioutil.WriteFile(path, []byte(dataStr), 0644); err != nil {
+		t.Fatal(err)
+	}
 }
 
-func TestOpen(t *testing.T) {
-	defer cleanup()
+func TestNewFileStore(t *testing.T) {
 	tests := []struct {
 		name    string
 		preload bool
+		want    int
 	}{
 		{
 			name:    "should open the store",
 			preload: false,
+			want:    0,
 		},
 		{
 			name:    "should open an existing store",
 			preload: true,
+			want:    4,
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
+			path, cleanup := testFilePath(t)
+			defer cleanup()
 			if tt.preload {
-				setupTestStore()
-			}
-			Open()
-			assert.NotNil(t, store, "should not be nil")
-			assert.True(t, IsOpen(), "should be open")
-			if tt.preload {
-				assert.Equal(t, 4, len(store), "should have 4 spots")
-			}
-		})
-	}
-}
-
-func TestIsOpen(t *testing.T) {
-	defer cleanup()
-	tests := []struct {
-		name string
-		want bool
-	}{
-		{
-			name: "should be open",
-			want: true,
-		},
-		{
-			name: "should not be open",
-			want: false,
-		},
-	}
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			if tt.want {
-				Open()
+				setupTestStore(t, path)
 			}
-			if got := IsOpen(); got != tt.want {
-				t.Errorf("IsOpen() = %v, want %v", got, tt.want)
-			}
-			store = nil
+			fs, err := NewFileStore(path)
+			assert.Nil(t, err)
+			_, err = os.Stat(path)
+			assert.Nil(t, err, "should create the data file")
+			spots, _ := fs.List()
+			assert.Equal(t, tt.want, len(spots))
 		})
 	}
 }
 
-func TestSave(t *testing.T) {
-	defer cleanup()
-	tests := []struct {
-		name    string
-		wantErr bool
-	}{
-		{
-			name:    "should save",
-			wantErr: false,
-		},
-	}
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			if err := save(); (err != nil) != tt.wantErr {
-				t.Errorf("Save() error = %v, wantErr %v", err, tt.wantErr)
-			}
-		})
-	}
-}
-
-func TestLoad(t *testing.T) {
-	defer cleanup()
+func TestFileStore_load(t *testing.T) {
 	tests := []struct {
 		name    string
 		want    map[string]Spot
@@ -177,65 +127,133 @@ func TestLoad(t *testing.T) {
 		},
 	}
 	for _, tt := range tests {
-		cleanup()
-		setupTestStore()
-		Open()
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := Load()
+			path, cleanup := testFilePath(t)
+			defer cleanup()
+			setupTestStore(t, path)
+			fs := &FileStore{path: path}
+			data, err := fs.load()
 			if (err != nil) != tt.wantErr {
-				t.Errorf("Load() error = %v, wantErr %v", err, tt.wantErr)
+				t.Errorf("FileStore.load() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
-			if !reflect.DeepEqual(got, tt.want) {
-				t.Errorf("Load() = %v, want %v", got, tt.want)
+			if got := data.spots; !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("FileStore.load() = %v, want %v", got, tt.want)
 			}
 		})
 	}
 }
 
-func Test_persist(t *testing.T) {
-	defer cleanup()
-	type args struct {
-		s  Spot
-		op string
+func TestFileStore_Update(t *testing.T) {
+	spot := Spot{
+		ID:           "B3",
+		OpenDate:     "2020-01-06",
+		RegDate:      "2020-01-05",
+		RegisteredBy: "FredsMom",
 	}
 	tests := []struct {
-		name string
-		args args
-		want int
+		name    string
+		fn      func(tx Tx) error
+		want    int
+		wantErr bool
 	}{
 		{
-			name: "should persist",
-			args: args{
-				s: Spot{
-					ID:           "B3",
-					OpenDate:     "2020-01-06",
-					RegDate:      "2020-01-05",
-					RegisteredBy: "FredsMom",
-				},
-				op: add,
+			name: "should persist an add",
+			fn: func(tx Tx) error {
+				return tx.Put(spot)
 			},
 			want: 1,
 		},
 		{
-			name: "should persist",
-			args: args{
-				s: Spot{
-					ID:           "B3",
-					OpenDate:     "2020-01-06",
-					RegDate:      "2020-01-05",
-					RegisteredBy: "FredsMom",
-				},
-				op: drop,
+			name: "should not persist a failed update",
+			fn: func(tx Tx) error {
+				tx.Put(spot)
+				return errors.New("nope")
 			},
-			want: 0,
+			want:    0,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path, cleanup := testFilePath(t)
+			defer cleanup()
+			fs, _ := NewFileStore(path)
+			if err := fs.Update(tt.fn); (err != nil) != tt.wantErr {
+				t.Errorf("FileStore.Update() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			reopened, err := NewFileStore(path)
+			assert.Nil(t, err)
+			spots, _ := reopened.List()
+			assert.Equal(t, tt.want, len(spots))
+		})
+	}
+}
+
+func TestOpen(t *testing.T) {
+	tests := []struct {
+		name    string
+		driver  string
+		want    Store
+		wantErr bool
+	}{
+		{
+			name:   "should open a file store by default",
+			driver: "",
+			want:   &FileStore{},
+		},
+		{
+			name:   "should open a bolt store",
+			driver: BoltDriver,
+			want:   &BoltStore{},
+		},
+		{
+			name:    "should not open an unknown driver",
+			driver:  "floppy",
+			wantErr: true,
 		},
 	}
 	for _, tt := range tests {
-		Open()
 		t.Run(tt.name, func(t *testing.T) {
-			persist(tt.args.s, tt.args.op)
-			assert.True(t, len(store) == tt.want)
+			path, cleanup := testFilePath(t)
+			defer cleanup()
+			os.Setenv("SPOT_DATA_DIR", filepath.Dir(path))
+			os.Setenv("SPOT_DATA_FILE", filepath.Base(path))
+			os.Setenv("SPOT_DATA_DRIVER", tt.driver)
+			defer os.Unsetenv("SPOT_DATA_DRIVER")
+			got, err := Open()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Open() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err == nil {
+				defer got.Close()
+				assert.IsType(t, tt.want, got)
+			}
 		})
 	}
 }
+
+func TestFileStore_Records(t *testing.T) {
+	path, cleanup := testFilePath(t)
+	defer cleanup()
+	fs, _ := NewFileStore(path)
+	testRecords(t, fs)
+	reopened, err := NewFileStore(path)
+	assert.Nil(t, err)
+	var got testRecord
+	assert.Nil(t, reopened.GetRecord("things", "a", &got), "should persist records")
+	assert.Equal(t, testRecord{Name: "A", Count: 2}, got)
+}
+
+func TestFileStore_Upgrade(t *testing.T) {
+	path, cleanup := testFilePath(t)
+	defer cleanup()
+	setupTestStore(t, path)
+	fs, _ := NewFileStore(path)
+	assert.Nil(t, fs.PutRecord("things", "a", testRecord{Name: "A"}))
+	reopened, err := NewFileStore(path)
+	assert.Nil(t, err)
+	spots, _ := reopened.List()
+	assert.Equal(t, 4, len(spots), "should keep the spots of an older data file")
+}
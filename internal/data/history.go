@@ -0,0 +1,141 @@
+package data
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+const (
+	// history - the collection changes to spots are kept in
+	history = "history"
+
+	// counters - the collection counters are kept in
+	counters = "counters"
+
+	// HistoryRegistered - a spot was registered for a date
+	HistoryRegistered = "registered"
+
+	// HistoryClaimed - a spot was claimed for a date, or part of it was
+	HistoryClaimed = "claimed"
+
+	// HistoryReleased - a claimed spot was given back
+	HistoryReleased = "released"
+
+	// HistoryDropped - the registration of a spot was dropped
+	HistoryDropped = "dropped"
+)
+
+// Change - something that happened to a spot on a date. Changes are only ever added, never changed or deleted, so
+// they are kept after the spots they are about are cleaned up.
+type Change struct {
+	// Seq - The place of the change in the history, later changes have higher numbers
+	Seq int
+
+	// Kind - What happened, registered, claimed, released or dropped
+	Kind string
+
+	// ID - The spot identifier
+	ID string
+
+	// Location - The office or site the spot is at
+	Location string `json:",omitempty"`
+
+	// Date - The date the spot was open
+	Date string
+
+	// Holder - The user who registered the spot
+	Holder string
+
+	// User - The user who claimed or released the spot, empty for registrations and drops
+	User string `json:",omitempty"`
+
+	// Window - The part of the day claimed or released, empty for the whole day
+	Window string `json:",omitempty"`
+
+	// By - The user who made the change when it was not the holder or the user, like an admin
+	By string `json:",omitempty"`
+
+	// At - When the change was made, RFC3339
+	At string
+}
+
+// Key - the key for this change, keys sort by the date the spot was open and then in the order the changes were made
+// so the changes of a few dates are read without reading the whole history
+func (c Change) Key() string {
+	return fmt.Sprintf("%s/%012d", c.Date, c.Seq)
+}
+
+// AppendChange - add a change to the end of the history, the change is given the next place in it
+func AppendChange(tx Tx, c Change) (Change, error) {
+	var seq int
+	if err := tx.GetRecord(counters, history, &seq); err != nil && err != ErrNotFound {
+		return Change{}, err
+	}
+	c.Seq = seq + 1
+	if err := tx.PutRecord(counters, history, c.Seq); err != nil {
+		return Change{}, err
+	}
+	return c, tx.PutRecord(history, c.Key(), c)
+}
+
+// ListHistory - the changes to spots open from one date to another, inclusive, in the order they were made. An empty
+// from or to is unbounded.
+func ListHistory(r Reader, from string, to string) ([]Change, error) {
+	if to != "" {
+		// after every key of the date
+		to += "/~"
+	}
+	records, err := r.ListRecordRange(history, from, to)
+	if err != nil {
+		return nil, err
+	}
+	changes := make([]Change, 0, len(records))
+	for _, record := range records {
+		var c Change
+		if err := json.Unmarshal(record, &c); err != nil {
+			return nil, err
+		}
+		changes = append(changes, c)
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Seq < changes[j].Seq
+	})
+	return changes, nil
+}
+
+// PruneHistory - delete the changes to spots open before the date, returns how many were deleted
+func PruneHistory(tx Tx, before string) (int, error) {
+	records, err := tx.ListRecordRange(history, "", before)
+	if err != nil {
+		return 0, err
+	}
+	for k := range records {
+		if err := tx.DeleteRecord(history, k); err != nil {
+			return 0, err
+		}
+	}
+	return len(records), nil
+}
+
+// rekeyHistory - move the changes kept from before they were keyed by date to their date's keys, returns how many
+// were moved. Those keys are only the place of the change padded with zeros, so they sort before every date.
+func rekeyHistory(tx Tx) (int, error) {
+	records, err := tx.ListRecordRange(history, "", "0~")
+	if err != nil {
+		return 0, err
+	}
+	for k, record := range records {
+		var c Change
+		if err := json.Unmarshal(record, &c); err != nil {
+			return 0, err
+		}
+		if err := tx.DeleteRecord(history, k); err != nil {
+			return 0, err
+		}
+		if err := tx.PutRecord(history, c.Key(), c); err != nil {
+			return 0, err
+		}
+	}
+	return len(records), nil
+}
@@ -0,0 +1,91 @@
+package data
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHistory(t *testing.T) {
+	m := NewMemoryStore()
+	changes := []Change{
+		{Kind: HistoryRegistered, ID: "B1", Date: "2020-01-08", Holder: "U1"},
+		{Kind: HistoryClaimed, ID: "B1", Date: "2020-01-08", Holder: "U1", User: "U2"},
+		{Kind: HistoryRegistered, ID: "B1", Date: "2020-01-09", Holder: "U1"},
+		{Kind: HistoryReleased, ID: "B1", Date: "2020-01-08", Holder: "U1", User: "U2"},
+	}
+	for i, c := range changes {
+		err := m.Update(func(tx Tx) error {
+			var err error
+			changes[i], err = AppendChange(tx, c)
+			return err
+		})
+		assert.Nil(t, err)
+		assert.Equal(t, i+1, changes[i].Seq, "should give each change the next place")
+	}
+
+	got, err := ListHistory(m, "", "")
+	assert.Nil(t, err)
+	assert.Equal(t, changes, got, "should list every change in order")
+
+	got, _ = ListHistory(m, "2020-01-08", "2020-01-08")
+	assert.Equal(t, []Change{changes[0], changes[1], changes[3]}, got, "should list the changes of the dates")
+}
+
+func TestHistory_Keys(t *testing.T) {
+	c := Change{Seq: 12, Date: "2020-01-08"}
+	assert.Equal(t, "2020-01-08/000000000012", c.Key())
+	assert.True(t, Change{Seq: 13, Date: "2020-01-07"}.Key() < c.Key(), "should sort by date first")
+	assert.True(t, c.Key() < Change{Seq: 13, Date: "2020-01-08"}.Key(), "should sort by place within a date")
+}
+
+func TestPruneHistory(t *testing.T) {
+	m := NewMemoryStore()
+	m.Update(func(tx Tx) error {
+		for _, date := range []string{"2020-01-06", "2020-01-07", "2020-01-08"} {
+			AppendChange(tx, Change{Kind: HistoryRegistered, ID: "B1", Date: date})
+		}
+		return nil
+	})
+	var pruned int
+	assert.Nil(t, m.Update(func(tx Tx) error {
+		var err error
+		pruned, err = PruneHistory(tx, "2020-01-08")
+		return err
+	}))
+	assert.Equal(t, 2, pruned)
+	got, _ := ListHistory(m, "", "")
+	assert.Equal(t, 1, len(got))
+	assert.Equal(t, "2020-01-08", got[0].Date, "should keep the changes of the date")
+}
+
+func TestRekeyHistory(t *testing.T) {
+	m := NewMemoryStore()
+	old := []Change{
+		{Seq: 1, Kind: HistoryRegistered, ID: "B1", Date: "2020-01-09"},
+		{Seq: 2, Kind: HistoryRegistered, ID: "B2", Date: "2020-01-08"},
+	}
+	for _, c := range old {
+		m.PutRecord(history, fmt.Sprintf("%012d", c.Seq), c)
+	}
+	var moved int
+	assert.Nil(t, m.Update(func(tx Tx) error {
+		var err error
+		moved, err = rekeyHistory(tx)
+		return err
+	}))
+	assert.Equal(t, 2, moved)
+	records, _ := m.ListRecords(history)
+	assert.Equal(t, 2, len(records))
+	assert.Contains(t, records, old[0].Key())
+	got, _ := ListHistory(m, "2020-01-08", "2020-01-08")
+	assert.Equal(t, []Change{old[1]}, got, "should find moved changes by date")
+
+	assert.Nil(t, m.Update(func(tx Tx) error {
+		var err error
+		moved, err = rekeyHistory(tx)
+		return err
+	}))
+	assert.Equal(t, 0, moved, "should only move changes once")
+}
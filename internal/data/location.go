@@ -0,0 +1,13 @@
+package data
+
+// LocationSep - separates the location of a record from the rest of its key
+const LocationSep = "/"
+
+// LocationKey - the key of a record at the location. Spot IDs are only unique within a location, so keys at a
+// location start with it. Keys at no location are left as they are, so stores from before locations keep working.
+func LocationKey(location string, key string) string {
+	if location == "" {
+		return key
+	}
+	return location + LocationSep + key
+}
@@ -0,0 +1,33 @@
+package data
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLocationKey(t *testing.T) {
+	tests := []struct {
+		name     string
+		location string
+		key      string
+		want     string
+	}{
+		{
+			name: "should leave keys at no location as they are",
+			key:  "12-2020-01-08",
+			want: "12-2020-01-08",
+		},
+		{
+			name:     "should start keys at a location with it",
+			location: "downtown",
+			key:      "12-2020-01-08",
+			want:     "downtown/12-2020-01-08",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, LocationKey(tt.location, tt.key))
+		})
+	}
+}
@@ -0,0 +1,147 @@
+package data
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+const (
+	// entries - the collection lottery entries are kept in
+	entries = "entries"
+
+	// draws - the collection lottery draws are kept in
+	draws = "draws"
+)
+
+type (
+	// Entry - a user taking part in the lottery for the spots of a date
+	Entry struct {
+		// Date - The date of the draw
+		Date string
+
+		// User - The user who entered
+		User string
+
+		// EnteredAt - When the user entered, RFC3339
+		EnteredAt string
+
+		// Location - The office or site the spots drawn are at
+		Location string `json:",omitempty"`
+	}
+
+	// Draw - the outcome of the lottery for a date, kept so a draw can be checked later
+	Draw struct {
+		// Date - The date the spots were drawn for
+		Date string
+
+		// Seed - The seed of the random numbers the draw used, the same entrants, spots, weights and seed draw the same winners
+		Seed int64
+
+		// Weighted - Whether users who won recently were less likely to win
+		Weighted bool
+
+		// DrawnAt - When the draw was made, RFC3339
+		DrawnAt string
+
+		// Entrants - The users who entered, ordered by ID
+		Entrants []string
+
+		// Wins - The spots won, in the order they were drawn
+		Wins []Win `json:",omitempty"`
+
+		// Location - The office or site the spots drawn are at
+		Location string `json:",omitempty"`
+	}
+
+	// Win - a spot won in a draw
+	Win struct {
+		// Spot - The spot identifier
+		Spot string
+
+		// User - The user who won it
+		User string
+	}
+)
+
+// Key - the key for this entry
+func (e Entry) Key() string {
+	return LocationKey(e.Location, fmt.Sprintf("%s-%s", e.Date, e.User))
+}
+
+// Key - the key for this draw
+func (d Draw) Key() string {
+	return LocationKey(d.Location, d.Date)
+}
+
+// Winner - returns true if the user won a spot in the draw
+func (d Draw) Winner(user string) bool {
+	for _, w := range d.Wins {
+		if w.User == user {
+			return true
+		}
+	}
+	return false
+}
+
+// GetEntry - get a user's entry in the lottery of a date
+func GetEntry(r Reader, key string) (Entry, error) {
+	var e Entry
+	err := r.GetRecord(entries, key, &e)
+	return e, err
+}
+
+// ListEntries - list every lottery entry keyed by location, date and user
+func ListEntries(r Reader) (map[string]Entry, error) {
+	records, err := r.ListRecords(entries)
+	if err != nil {
+		return nil, err
+	}
+	es := make(map[string]Entry, len(records))
+	for k, record := range records {
+		var e Entry
+		if err := json.Unmarshal(record, &e); err != nil {
+			return nil, err
+		}
+		es[k] = e
+	}
+	return es, nil
+}
+
+// PutEntry - add or replace a lottery entry
+func PutEntry(tx Tx, e Entry) error {
+	return tx.PutRecord(entries, e.Key(), e)
+}
+
+// DeleteEntry - delete a lottery entry
+func DeleteEntry(tx Tx, key string) error {
+	return tx.DeleteRecord(entries, key)
+}
+
+// GetDraw - get the draw of a date
+func GetDraw(r Reader, key string) (Draw, error) {
+	var d Draw
+	err := r.GetRecord(draws, key, &d)
+	return d, err
+}
+
+// ListDraws - list every draw keyed by location and date
+func ListDraws(r Reader) (map[string]Draw, error) {
+	records, err := r.ListRecords(draws)
+	if err != nil {
+		return nil, err
+	}
+	ds := make(map[string]Draw, len(records))
+	for k, record := range records {
+		var d Draw
+		if err := json.Unmarshal(record, &d); err != nil {
+			return nil, err
+		}
+		ds[k] = d
+	}
+	return ds, nil
+}
+
+// PutDraw - add or replace a draw
+func PutDraw(tx Tx, d Draw) error {
+	return tx.PutRecord(draws, d.Key(), d)
+}
@@ -0,0 +1,34 @@
+package data
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEntries(t *testing.T) {
+	m := NewMemoryStore()
+	e := Entry{Date: "2020-01-08", User: "U1", EnteredAt: "2020-01-07T09:30:00Z"}
+	assert.Nil(t, PutEntry(m, e))
+	got, err := GetEntry(m, "2020-01-08-U1")
+	assert.Nil(t, err)
+	assert.Equal(t, e, got)
+	es, _ := ListEntries(m)
+	assert.Equal(t, map[string]Entry{"2020-01-08-U1": e}, es)
+	assert.Nil(t, DeleteEntry(m, e.Key()))
+	_, err = GetEntry(m, e.Key())
+	assert.Equal(t, ErrNotFound, err)
+}
+
+func TestDraws(t *testing.T) {
+	m := NewMemoryStore()
+	d := Draw{Date: "2020-01-08", Seed: 42, DrawnAt: "2020-01-08T08:00:00Z", Entrants: []string{"U1", "U2"}, Wins: []Win{{Spot: "B1", User: "U2"}}}
+	assert.Nil(t, PutDraw(m, d))
+	got, err := GetDraw(m, "2020-01-08")
+	assert.Nil(t, err)
+	assert.Equal(t, d, got)
+	ds, _ := ListDraws(m)
+	assert.Equal(t, map[string]Draw{"2020-01-08": d}, ds)
+	assert.True(t, d.Winner("U2"))
+	assert.False(t, d.Winner("U1"))
+}
@@ -0,0 +1,280 @@
+package data
+
+import (
+	"encoding/json"
+	"sync"
+)
+
+// MemoryStore - a Store that only lives in memory, useful for testing
+type MemoryStore struct {
+	lock sync.Mutex
+	data snapshot
+
+	// commit - called with the new data before a transaction is applied, used by stores that persist the memory store
+	commit func(data snapshot) error
+}
+
+// snapshot - everything kept in a memory store
+type snapshot struct {
+	spots   map[string]Spot
+	records map[string]map[string][]byte
+}
+
+type memoryTx struct {
+	snapshot
+
+	// changed - set once the transaction puts or deletes anything
+	changed *bool
+}
+
+// NewMemoryStore - create an empty memory store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		data: newSnapshot(),
+	}
+}
+
+// Get - get a spot
+func (m *MemoryStore) Get(key string) (Spot, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	return memoryTx{snapshot: m.data}.Get(key)
+}
+
+// List - list all spots
+func (m *MemoryStore) List() (map[string]Spot, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	return memoryTx{snapshot: m.data}.List()
+}
+
+// ListByID - list the registrations of a spot
+func (m *MemoryStore) ListByID(id string) (map[string]Spot, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	return memoryTx{snapshot: m.data}.ListByID(id)
+}
+
+// ListByDate - list spots open between two dates
+func (m *MemoryStore) ListByDate(from string, to string) (map[string]Spot, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	return memoryTx{snapshot: m.data}.ListByDate(from, to)
+}
+
+// ListByUser - list spots registered by a user
+func (m *MemoryStore) ListByUser(user string) (map[string]Spot, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	return memoryTx{snapshot: m.data}.ListByUser(user)
+}
+
+// GetRecord - get a record
+func (m *MemoryStore) GetRecord(collection string, key string, v interface{}) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	return memoryTx{snapshot: m.data}.GetRecord(collection, key, v)
+}
+
+// ListRecords - list the records in a collection
+func (m *MemoryStore) ListRecords(collection string) (map[string][]byte, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	return memoryTx{snapshot: m.data}.ListRecords(collection)
+}
+
+// ListRecordRange - list the records in a collection with keys between two keys
+func (m *MemoryStore) ListRecordRange(collection string, from string, to string) (map[string][]byte, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	return memoryTx{snapshot: m.data}.ListRecordRange(collection, from, to)
+}
+
+// Put - add or replace a spot
+func (m *MemoryStore) Put(s Spot) error {
+	return m.Update(func(tx Tx) error {
+		return tx.Put(s)
+	})
+}
+
+// Delete - delete a spot
+func (m *MemoryStore) Delete(key string) error {
+	return m.Update(func(tx Tx) error {
+		return tx.Delete(key)
+	})
+}
+
+// PutRecord - add or replace a record
+func (m *MemoryStore) PutRecord(collection string, key string, v interface{}) error {
+	return m.Update(func(tx Tx) error {
+		return tx.PutRecord(collection, key, v)
+	})
+}
+
+// DeleteRecord - delete a record
+func (m *MemoryStore) DeleteRecord(collection string, key string) error {
+	return m.Update(func(tx Tx) error {
+		return tx.DeleteRecord(collection, key)
+	})
+}
+
+// Update - run fn against a copy of the data and swap it in if fn succeeds and changed anything
+func (m *MemoryStore) Update(fn func(tx Tx) error) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	tx := memoryTx{snapshot: m.data.copy(), changed: new(bool)}
+	if err := fn(tx); err != nil {
+		return err
+	}
+	if !*tx.changed {
+		return nil
+	}
+	if m.commit != nil {
+		if err := m.commit(tx.snapshot); err != nil {
+			return err
+		}
+	}
+	m.data = tx.snapshot
+	return nil
+}
+
+// View - run fn against the data, nothing is copied
+func (m *MemoryStore) View(fn func(r Reader) error) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	return fn(memoryTx{snapshot: m.data})
+}
+
+// Close - nothing to close
+func (m *MemoryStore) Close() error {
+	return nil
+}
+
+func (tx memoryTx) Get(key string) (Spot, error) {
+	s, ok := tx.spots[key]
+	if !ok {
+		return Spot{}, ErrNotFound
+	}
+	return s, nil
+}
+
+func (tx memoryTx) List() (map[string]Spot, error) {
+	return copySpots(tx.spots), nil
+}
+
+func (tx memoryTx) ListByID(id string) (map[string]Spot, error) {
+	return tx.filter(func(s Spot) bool {
+		return s.ID == id
+	}), nil
+}
+
+func (tx memoryTx) ListByDate(from string, to string) (map[string]Spot, error) {
+	return tx.filter(func(s Spot) bool {
+		return (from == "" || s.OpenDate >= from) && (to == "" || s.OpenDate <= to)
+	}), nil
+}
+
+func (tx memoryTx) ListByUser(user string) (map[string]Spot, error) {
+	return tx.filter(func(s Spot) bool {
+		return s.RegisteredBy == user
+	}), nil
+}
+
+// filter - memory stores have no indexes, so lookups scan every spot
+func (tx memoryTx) filter(match func(s Spot) bool) map[string]Spot {
+	out := make(map[string]Spot)
+	for k, s := range tx.spots {
+		if match(s) {
+			out[k] = s
+		}
+	}
+	return out
+}
+
+func (tx memoryTx) GetRecord(collection string, key string, v interface{}) error {
+	r, ok := tx.records[collection][key]
+	if !ok {
+		return ErrNotFound
+	}
+	return json.Unmarshal(r, v)
+}
+
+func (tx memoryTx) ListRecords(collection string) (map[string][]byte, error) {
+	out := make(map[string][]byte, len(tx.records[collection]))
+	for k, r := range tx.records[collection] {
+		out[k] = r
+	}
+	return out, nil
+}
+
+// ListRecordRange - memory stores have no order, so the keys of every record in the collection are compared
+func (tx memoryTx) ListRecordRange(collection string, from string, to string) (map[string][]byte, error) {
+	out := make(map[string][]byte)
+	for k, r := range tx.records[collection] {
+		if (from == "" || k >= from) && (to == "" || k <= to) {
+			out[k] = r
+		}
+	}
+	return out, nil
+}
+
+func (tx memoryTx) Put(s Spot) error {
+	*tx.changed = true
+	tx.spots[s.Key()] = s
+	return nil
+}
+
+func (tx memoryTx) Delete(key string) error {
+	*tx.changed = true
+	delete(tx.spots, key)
+	return nil
+}
+
+func (tx memoryTx) PutRecord(collection string, key string, v interface{}) error {
+	r, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	if tx.records[collection] == nil {
+		tx.records[collection] = make(map[string][]byte)
+	}
+	tx.records[collection][key] = r
+	*tx.changed = true
+	return nil
+}
+
+func (tx memoryTx) DeleteRecord(collection string, key string) error {
+	*tx.changed = true
+	delete(tx.records[collection], key)
+	return nil
+}
+
+func newSnapshot() snapshot {
+	return snapshot{
+		spots:   make(map[string]Spot),
+		records: make(map[string]map[string][]byte),
+	}
+}
+
+// copy - copy the maps of the snapshot, records are never modified in place so their bytes are shared
+func (s snapshot) copy() snapshot {
+	out := snapshot{
+		spots:   copySpots(s.spots),
+		records: make(map[string]map[string][]byte, len(s.records)),
+	}
+	for collection, records := range s.records {
+		out.records[collection] = make(map[string][]byte, len(records))
+		for k, r := range records {
+			out.records[collection][k] = r
+		}
+	}
+	return out
+}
+
+func copySpots(in map[string]Spot) map[string]Spot {
+	out := make(map[string]Spot, len(in))
+	for k, v := range in {
+		out[k] = v
+	}
+	return out
+}
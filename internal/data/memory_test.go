@@ -0,0 +1,189 @@
+package data
+
+import (
+	"errors"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testSpot() Spot {
+	return Spot{
+		ID:           "B1",
+		OpenDate:     "2020-01-05",
+		RegDate:      "2020-01-05",
+		RegisteredBy: "slackuser",
+	}
+}
+
+func TestMemoryStore_Get(t *testing.T) {
+	tests := []struct {
+		name    string
+		key     string
+		want    Spot
+		wantErr error
+	}{
+		{
+			name: "should get a spot",
+			key:  "B1-2020-01-05",
+			want: testSpot(),
+		},
+		{
+			name:    "should not find a spot",
+			key:     "B2-2020-01-05",
+			want:    Spot{},
+			wantErr: ErrNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewMemoryStore()
+			m.Put(testSpot())
+			got, err := m.Get(tt.key)
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestMemoryStore_Delete(t *testing.T) {
+	m := NewMemoryStore()
+	m.Put(testSpot())
+	assert.Nil(t, m.Delete(testSpot().Key()))
+	assert.Nil(t, m.Delete(testSpot().Key()), "deleting a missing spot is not an error")
+	spots, _ := m.List()
+	assert.Equal(t, 0, len(spots))
+}
+
+func TestMemoryStore_List(t *testing.T) {
+	m := NewMemoryStore()
+	m.Put(testSpot())
+	spots, _ := m.List()
+	delete(spots, testSpot().Key())
+	spots, _ = m.List()
+	assert.Equal(t, 1, len(spots), "changes to a listing should not change the store")
+}
+
+func TestMemoryStore_Update(t *testing.T) {
+	tests := []struct {
+		name    string
+		fn      func(tx Tx) error
+		want    int
+		wantErr bool
+	}{
+		{
+			name: "should apply changes",
+			fn: func(tx Tx) error {
+				return tx.Put(testSpot())
+			},
+			want: 1,
+		},
+		{
+			name: "should roll back changes",
+			fn: func(tx Tx) error {
+				tx.Put(testSpot())
+				return errors.New("nope")
+			},
+			want:    0,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewMemoryStore()
+			if err := m.Update(tt.fn); (err != nil) != tt.wantErr {
+				t.Errorf("MemoryStore.Update() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			spots, _ := m.List()
+			assert.Equal(t, tt.want, len(spots))
+		})
+	}
+}
+
+func TestMemoryStore_Commit(t *testing.T) {
+	m := NewMemoryStore()
+	commits := 0
+	m.commit = func(data snapshot) error {
+		commits++
+		return nil
+	}
+	m.Update(func(tx Tx) error {
+		_, err := tx.List()
+		return err
+	})
+	assert.Equal(t, 0, commits, "should not commit a transaction that changed nothing")
+	m.Put(testSpot())
+	assert.Equal(t, 1, commits)
+
+	var got Spot
+	err := m.View(func(r Reader) error {
+		var err error
+		got, err = r.Get(testSpot().Key())
+		return err
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, testSpot(), got)
+	assert.Equal(t, 1, commits, "should not commit a view")
+}
+
+func TestMemoryStore_Lookups(t *testing.T) {
+	testLookups(t, NewMemoryStore())
+}
+
+type testRecord struct {
+	Name  string
+	Count int
+}
+
+// testRecords - exercises the records of any store
+func testRecords(t *testing.T, store Store) {
+	var got testRecord
+	assert.Equal(t, ErrNotFound, store.GetRecord("things", "a", &got))
+	assert.Nil(t, store.PutRecord("things", "a", testRecord{Name: "A", Count: 1}))
+	assert.Nil(t, store.PutRecord("things", "a", testRecord{Name: "A", Count: 2}))
+	assert.Nil(t, store.PutRecord("things", "b", testRecord{Name: "B"}))
+	assert.Nil(t, store.PutRecord("others", "a", testRecord{Name: "other"}))
+	assert.Nil(t, store.GetRecord("things", "a", &got))
+	assert.Equal(t, testRecord{Name: "A", Count: 2}, got)
+	records, err := store.ListRecords("things")
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(records))
+	assert.Nil(t, store.DeleteRecord("things", "b"))
+	assert.Nil(t, store.DeleteRecord("nothings", "b"), "deleting a missing record is not an error")
+	records, _ = store.ListRecords("things")
+	assert.Equal(t, 1, len(records))
+	err = store.Update(func(tx Tx) error {
+		tx.PutRecord("things", "c", testRecord{Name: "C"})
+		return errors.New("nope")
+	})
+	assert.NotNil(t, err)
+	assert.Equal(t, ErrNotFound, store.GetRecord("things", "c", &got), "should roll back records")
+
+	for _, k := range []string{"b", "c", "d"} {
+		store.PutRecord("things", k, testRecord{Name: k})
+	}
+	records, err = store.ListRecordRange("things", "b", "c")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"b", "c"}, recordKeys(records), "should list the keys in the range")
+	records, _ = store.ListRecordRange("things", "", "b")
+	assert.Equal(t, []string{"a", "b"}, recordKeys(records), "should list from the first key")
+	records, _ = store.ListRecordRange("things", "c", "")
+	assert.Equal(t, []string{"c", "d"}, recordKeys(records), "should list to the last key")
+	records, _ = store.ListRecordRange("nothings", "", "")
+	assert.Empty(t, records)
+}
+
+// recordKeys - the keys of the records in order
+func recordKeys(records map[string][]byte) []string {
+	var keys []string
+	for k := range records {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func TestMemoryStore_Records(t *testing.T) {
+	testRecords(t, NewMemoryStore())
+}
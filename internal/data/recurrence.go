@@ -0,0 +1,88 @@
+package data
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/jasonholmberg/slashspot/internal/util"
+)
+
+// recurrences - the collection recurrences are kept in
+const recurrences = "recurrences"
+
+// Recurrence - a rule that registers a spot on the same days every week
+type Recurrence struct {
+	// ID - The spot identifier, a spot has at most one recurrence
+	ID string
+
+	// Weekdays - The days of the week the spot is open
+	Weekdays []time.Weekday
+
+	// Until - The last date the spot is open, empty when the rule never ends
+	Until string `json:",omitempty"`
+
+	// RegDate - The reg date of the recurrence
+	RegDate string
+
+	// RegisteredBy - The user who registered the recurrence
+	RegisteredBy string
+
+	// Expanded - The last date the rule was expanded into a registration
+	Expanded string `json:",omitempty"`
+
+	// Location - The office or site the spot is at
+	Location string `json:",omitempty"`
+}
+
+// Key - the key for this recurrence
+func (r Recurrence) Key() string {
+	return LocationKey(r.Location, r.ID)
+}
+
+// OpensOn - returns true if the rule opens the spot on the date, date is in the spot date format
+func (r Recurrence) OpensOn(date string) bool {
+	d, err := time.Parse(util.SpotDateFormat, date)
+	if err != nil || date < r.RegDate || (r.Until != "" && date > r.Until) {
+		return false
+	}
+	for _, w := range r.Weekdays {
+		if w == d.Weekday() {
+			return true
+		}
+	}
+	return false
+}
+
+// GetRecurrence - get the recurrence of a spot
+func GetRecurrence(r Reader, key string) (Recurrence, error) {
+	var rec Recurrence
+	err := r.GetRecord(recurrences, key, &rec)
+	return rec, err
+}
+
+// ListRecurrences - list every recurrence
+func ListRecurrences(r Reader) ([]Recurrence, error) {
+	records, err := r.ListRecords(recurrences)
+	if err != nil {
+		return nil, err
+	}
+	var recs []Recurrence
+	for _, record := range records {
+		var rec Recurrence
+		if err := json.Unmarshal(record, &rec); err != nil {
+			return nil, err
+		}
+		recs = append(recs, rec)
+	}
+	return recs, nil
+}
+
+// PutRecurrence - add or replace a recurrence
+func PutRecurrence(tx Tx, rec Recurrence) error {
+	return tx.PutRecord(recurrences, rec.Key(), rec)
+}
+
+// DeleteRecurrence - delete a recurrence
+func DeleteRecurrence(tx Tx, key string) error {
+	return tx.DeleteRecord(recurrences, key)
+}
@@ -0,0 +1,52 @@
+package data
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecurrence_OpensOn(t *testing.T) {
+	rec := Recurrence{
+		ID:           "B1",
+		Weekdays:     []time.Weekday{time.Monday, time.Friday},
+		Until:        "2020-01-31",
+		RegDate:      "2020-01-05",
+		RegisteredBy: "slackuser",
+	}
+	tests := []struct {
+		name string
+		date string
+		want bool
+	}{
+		{name: "should open on a monday", date: "2020-01-06", want: true},
+		{name: "should open on a friday", date: "2020-01-10", want: true},
+		{name: "should open on the last day", date: "2020-01-31", want: true},
+		{name: "should not open on a tuesday", date: "2020-01-07", want: false},
+		{name: "should not open before it was registered", date: "2019-12-30", want: false},
+		{name: "should not open after it ends", date: "2020-02-03", want: false},
+		{name: "should not open on a bad date", date: "monday", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := rec.OpensOn(tt.date); got != tt.want {
+				t.Errorf("Recurrence.OpensOn() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRecurrences(t *testing.T) {
+	m := NewMemoryStore()
+	rec := Recurrence{ID: "B1", Weekdays: []time.Weekday{time.Friday}, RegDate: "2020-01-05", RegisteredBy: "slackuser"}
+	assert.Nil(t, PutRecurrence(m, rec))
+	got, err := GetRecurrence(m, "B1")
+	assert.Nil(t, err)
+	assert.Equal(t, rec, got)
+	recs, _ := ListRecurrences(m)
+	assert.Equal(t, []Recurrence{rec}, recs)
+	assert.Nil(t, DeleteRecurrence(m, "B1"))
+	_, err = GetRecurrence(m, "B1")
+	assert.Equal(t, ErrNotFound, err)
+}
@@ -0,0 +1,111 @@
+package data
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+)
+
+const (
+	// JSONDriver - keep spots in a JSON file, the default
+	JSONDriver = "json"
+
+	// BoltDriver - keep spots in an embedded bolt database
+	BoltDriver = "bolt"
+)
+
+// ErrNotFound - returned when a spot or record does not exist in the store
+var ErrNotFound = errors.New("not found")
+
+type (
+	// Reader - read access to the spots in a store. Other kinds of data are kept as records in named collections.
+	Reader interface {
+		// Get - get the spot for the given key, returns ErrNotFound if there is no such spot
+		Get(key string) (Spot, error)
+
+		// List - list all spots keyed by Spot.Key()
+		List() (map[string]Spot, error)
+
+		// ListByID - list all registrations of the spot id
+		ListByID(id string) (map[string]Spot, error)
+
+		// ListByDate - list all spots open from one date to another, inclusive. An empty from or to is unbounded.
+		ListByDate(from string, to string) (map[string]Spot, error)
+
+		// ListByUser - list all spots registered by the user
+		ListByUser(user string) (map[string]Spot, error)
+
+		// GetRecord - decode the record with the key in a collection into v, returns ErrNotFound if there is no such record
+		GetRecord(collection string, key string, v interface{}) error
+
+		// ListRecords - the JSON of every record in a collection keyed by record key
+		ListRecords(collection string) (map[string][]byte, error)
+
+		// ListRecordRange - the JSON of the records in a collection with keys from one to another, inclusive, keyed by
+		// record key. An empty from or to is unbounded.
+		ListRecordRange(collection string, from string, to string) (map[string][]byte, error)
+	}
+
+	// Tx - a set of reads and writes applied to a store as a unit
+	Tx interface {
+		Reader
+
+		// Put - add or replace the spot
+		Put(s Spot) error
+
+		// Delete - delete the spot with the given key, deleting a missing spot is not an error
+		Delete(key string) error
+
+		// PutRecord - add or replace the record with the key in a collection, v is stored as JSON
+		PutRecord(collection string, key string, v interface{}) error
+
+		// DeleteRecord - delete the record with the key in a collection, deleting a missing record is not an error
+		DeleteRecord(collection string, key string) error
+	}
+
+	// Store - a spot store. Put and Delete on a store are each applied in their own transaction.
+	Store interface {
+		Tx
+
+		// Update - run fn in a transaction. If fn returns an error none of its changes are applied.
+		Update(fn func(tx Tx) error) error
+
+		// View - run fn in a read-only transaction, it sees the spots and records as they were when it started
+		View(fn func(r Reader) error) error
+
+		// Close - release any resources held by the store
+		Close() error
+	}
+)
+
+// Open - open the spot store configured in the environment. SPOT_DATA_DRIVER selects the kind of store.
+// The history kept by older versions is moved to its new keys.
+func Open() (Store, error) {
+	var store Store
+	var err error
+	switch driver := strings.ToLower(os.Getenv("SPOT_DATA_DRIVER")); driver {
+	case "", JSONDriver:
+		store, err = NewFileStore(FilePath())
+	case BoltDriver:
+		store, err = NewBoltStore(FilePath())
+	default:
+		return nil, fmt.Errorf("unknown data driver %q", driver)
+	}
+	if err != nil {
+		return nil, err
+	}
+	err = store.Update(func(tx Tx) error {
+		moved, err := rekeyHistory(tx)
+		if moved > 0 {
+			log.Printf("Moved %d history changes to keys by date", moved)
+		}
+		return err
+	})
+	if err != nil {
+		store.Close()
+		return nil, err
+	}
+	return store, nil
+}
@@ -1,6 +1,9 @@
 package data
 
-import "fmt"
+import (
+	"fmt"
+	"time"
+)
 
 type (
 	// Spot - a simple spot type
@@ -16,16 +19,51 @@ type (
 
 		// RegisteredBy - The user who registered the spot
 		RegisteredBy string
+
+		// ClaimedBy - The user who claimed the spot, empty while the spot is open
+		ClaimedBy string `json:",omitempty"`
+
+		// ClaimedAt - When the spot was claimed, RFC3339
+		ClaimedAt string `json:",omitempty"`
+
+		// HeldFor - The user at the front of the waitlist the spot is held for, only they can claim it while it is held
+		HeldFor string `json:",omitempty"`
+
+		// HeldUntil - When the hold ends, RFC3339
+		HeldUntil string `json:",omitempty"`
+
+		// Window - The part of the open date the spot is available, the whole day when empty
+		Window Window
+
+		// Shares - The claims of parts of the day, users share a spot by claiming windows that don't overlap
+		Shares []Share `json:",omitempty"`
+
+		// Location - The office or site the spot is at, spot IDs are only unique within a location
+		Location string `json:",omitempty"`
 	}
 
 )
 
 // Key - the key for this spot
 func (s Spot) Key() string {
-	return fmt.Sprintf("%v-%s", s.ID, s.OpenDate)
+	return LocationKey(s.Location, fmt.Sprintf("%v-%s", s.ID, s.OpenDate))
 }
 
 // IsZeroValue - returns true if all elements of the struct are their zero-value. This is primarily used to make testing easier.
 func (s Spot) IsZeroValue() bool {
-	return s.ID == "" && s.OpenDate == "" && s.RegDate == "" && s.RegisteredBy == ""
+	return s.ID == "" && s.OpenDate == "" && s.RegDate == "" && s.RegisteredBy == "" && s.ClaimedBy == "" && s.ClaimedAt == "" && s.HeldFor == "" && s.HeldUntil == "" && s.Window.IsWhole() && len(s.Shares) == 0 && s.Location == ""
+}
+
+// IsClaimed - returns true if someone has claimed the spot
+func (s Spot) IsClaimed() bool {
+	return s.ClaimedBy != ""
+}
+
+// IsHeld - returns true if the spot is held for someone at the time
+func (s Spot) IsHeld(now time.Time) bool {
+	if s.HeldFor == "" {
+		return false
+	}
+	until, err := time.Parse(time.RFC3339, s.HeldUntil)
+	return err == nil && now.Before(until)
 }
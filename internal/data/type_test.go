@@ -2,6 +2,7 @@ package data
 
 import (
 	"testing"
+	"time"
 )
 
 func TestSpot_Key(t *testing.T) {
@@ -10,6 +11,7 @@ func TestSpot_Key(t *testing.T) {
 		OpenDate     string
 		RegDate      string
 		RegisteredBy string
+		Location     string
 	}
 	tests := []struct {
 		name   string
@@ -26,6 +28,15 @@ func TestSpot_Key(t *testing.T) {
 			},
 			want: "T1-2020-01-01",
 		},
+		{
+			name: "should start the key with the location",
+			fields: fields{
+				ID:       "12",
+				OpenDate: "2020-01-01",
+				Location: "downtown",
+			},
+			want: "downtown/12-2020-01-01",
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -34,6 +45,7 @@ func TestSpot_Key(t *testing.T) {
 				OpenDate:     tt.fields.OpenDate,
 				RegDate:      tt.fields.RegDate,
 				RegisteredBy: tt.fields.RegisteredBy,
+				Location:     tt.fields.Location,
 			}
 			if got := spot.Key(); got != tt.want {
 				t.Errorf("Spot.Key() = %v, want %v", got, tt.want)
@@ -48,6 +60,7 @@ func TestSpot_IsZeroValue(t *testing.T) {
 		OpenDate     string
 		RegDate      string
 		RegisteredBy string
+		ClaimedBy    string
 	}
 	tests := []struct {
 		name   string
@@ -103,6 +116,12 @@ func TestSpot_IsZeroValue(t *testing.T) {
 				RegisteredBy: "X",
 			},
 			want: false,
+		},		{
+			name: "should not be zeroed",
+			fields: fields{
+				ClaimedBy: "X",
+			},
+			want: false,
 		},
 	}
 	for _, tt := range tests {
@@ -112,6 +131,7 @@ func TestSpot_IsZeroValue(t *testing.T) {
 				OpenDate:     tt.fields.OpenDate,
 				RegDate:      tt.fields.RegDate,
 				RegisteredBy: tt.fields.RegisteredBy,
+				ClaimedBy:    tt.fields.ClaimedBy,
 			}
 			if got := s.IsZeroValue(); got != tt.want {
 				t.Errorf("Spot.IsZeroValue() = %v, want %v", got, tt.want)
@@ -119,3 +139,49 @@ func TestSpot_IsZeroValue(t *testing.T) {
 		})
 	}
 }
+
+func TestSpot_IsClaimed(t *testing.T) {
+	tests := []struct {
+		name string
+		spot Spot
+		want bool
+	}{
+		{
+			name: "should be claimed",
+			spot: Spot{ID: "T1", ClaimedBy: "SuperFuzz", ClaimedAt: "2020-01-01T08:00:00Z"},
+			want: true,
+		},
+		{
+			name: "should not be claimed",
+			spot: Spot{ID: "T1"},
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.spot.IsClaimed(); got != tt.want {
+				t.Errorf("Spot.IsClaimed() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSpot_IsHeld(t *testing.T) {
+	now := time.Date(2020, 1, 8, 9, 30, 0, 0, time.UTC)
+	tests := []struct {
+		name string
+		spot Spot
+		want bool
+	}{
+		{name: "should not be held without a holder", spot: Spot{ID: "B1"}, want: false},
+		{name: "should be held until the hold ends", spot: Spot{ID: "B1", HeldFor: "U1", HeldUntil: "2020-01-08T09:45:00Z"}, want: true},
+		{name: "should not be held after the hold ends", spot: Spot{ID: "B1", HeldFor: "U1", HeldUntil: "2020-01-08T09:15:00Z"}, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.spot.IsHeld(now); got != tt.want {
+				t.Errorf("Spot.IsHeld() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,129 @@
+package data
+
+import "encoding/json"
+
+// users - the collection users are kept in
+const users = "users"
+
+// User - a slack user spot has seen. Spots and other records refer to users by ID, the name is only for display.
+type User struct {
+	// ID - The slack user ID
+	ID string
+
+	// Name - The slack user name when the user was last seen
+	Name string
+
+	// TeamID - The slack workspace the user was last seen in
+	TeamID string `json:",omitempty"`
+
+	// Location - The office or site the user finds, claims and registers spots at unless they say otherwise
+	Location string `json:",omitempty"`
+}
+
+// Key - the key for this user
+func (u User) Key() string {
+	return u.ID
+}
+
+// GetUser - get a user
+func GetUser(r Reader, key string) (User, error) {
+	var u User
+	err := r.GetRecord(users, key, &u)
+	return u, err
+}
+
+// ListUsers - list every user keyed by ID
+func ListUsers(r Reader) (map[string]User, error) {
+	records, err := r.ListRecords(users)
+	if err != nil {
+		return nil, err
+	}
+	us := make(map[string]User, len(records))
+	for k, record := range records {
+		var u User
+		if err := json.Unmarshal(record, &u); err != nil {
+			return nil, err
+		}
+		us[k] = u
+	}
+	return us, nil
+}
+
+// PutUser - add or replace a user
+func PutUser(tx Tx, u User) error {
+	return tx.PutRecord(users, u.Key(), u)
+}
+
+// MigrateUserNames - replace user names with user IDs everywhere a user is kept, ids maps each name to its ID. Stores
+// from before users were kept by ID need this once. Returns how many spots and records were changed.
+func MigrateUserNames(s Store, ids map[string]string) (int, error) {
+	var changed int
+	err := s.Update(func(tx Tx) error {
+		changed = 0
+		rename := func(user *string) bool {
+			id, ok := ids[*user]
+			if ok {
+				*user = id
+			}
+			return ok
+		}
+		spots, err := tx.List()
+		if err != nil {
+			return err
+		}
+		for _, spot := range spots {
+			// both run so a spot registered and claimed by old names is renamed in one go
+			if r, c := rename(&spot.RegisteredBy), rename(&spot.ClaimedBy); r || c {
+				if err := tx.Put(spot); err != nil {
+					return err
+				}
+				changed++
+			}
+		}
+		recs, err := ListRecurrences(tx)
+		if err != nil {
+			return err
+		}
+		for _, rec := range recs {
+			if rename(&rec.RegisteredBy) {
+				if err := PutRecurrence(tx, rec); err != nil {
+					return err
+				}
+				changed++
+			}
+		}
+		as, err := ListAssignments(tx)
+		if err != nil {
+			return err
+		}
+		for _, a := range as {
+			if h, b := rename(&a.Holder), rename(&a.AssignedBy); h || b {
+				if err := PutAssignment(tx, a); err != nil {
+					return err
+				}
+				changed++
+			}
+		}
+		known, err := ListCatalog(tx)
+		if err != nil {
+			return err
+		}
+		for _, c := range known {
+			if rename(&c.Holder) {
+				if err := PutCatalogSpot(tx, c); err != nil {
+					return err
+				}
+				changed++
+			}
+		}
+		for name, id := range ids {
+			if _, err := GetUser(tx, id); err == ErrNotFound {
+				if err := PutUser(tx, User{ID: id, Name: name}); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+	return changed, err
+}
@@ -0,0 +1,56 @@
+package data
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUsers(t *testing.T) {
+	m := NewMemoryStore()
+	u := User{ID: "U1", Name: "slackuser", TeamID: "T1"}
+	assert.Nil(t, PutUser(m, u))
+	got, err := GetUser(m, "U1")
+	assert.Nil(t, err)
+	assert.Equal(t, u, got)
+	us, _ := ListUsers(m)
+	assert.Equal(t, map[string]User{"U1": u}, us)
+}
+
+func TestMigrateUserNames(t *testing.T) {
+	bolt, cleanup := newTestBoltStore(t)
+	defer cleanup()
+	for name, store := range map[string]Store{"memory": NewMemoryStore(), "bolt": bolt} {
+		t.Run(name, func(t *testing.T) {
+			store.Put(Spot{ID: "B1", OpenDate: "2020-01-08", RegDate: "2020-01-07", RegisteredBy: "slackuser", ClaimedBy: "ponyboy"})
+			store.Put(Spot{ID: "B2", OpenDate: "2020-01-08", RegDate: "2020-01-07", RegisteredBy: "U9"})
+			PutRecurrence(store, Recurrence{ID: "B1", Weekdays: []time.Weekday{time.Friday}, RegDate: "2020-01-07", RegisteredBy: "slackuser"})
+			PutAssignment(store, Assignment{ID: "B1", Holder: "slackuser", AssignedBy: "boss"})
+			PutCatalogSpot(store, CatalogSpot{ID: "B1", Holder: "slackuser"})
+
+			changed, err := MigrateUserNames(store, map[string]string{"slackuser": "U1", "ponyboy": "U2", "boss": "U3"})
+			assert.Nil(t, err)
+			assert.Equal(t, 4, changed)
+
+			spot, _ := store.Get("B1-2020-01-08")
+			assert.Equal(t, "U1", spot.RegisteredBy)
+			assert.Equal(t, "U2", spot.ClaimedBy)
+			byUser, _ := store.ListByUser("U1")
+			assert.Contains(t, byUser, "B1-2020-01-08", "should index the spot by ID")
+			byUser, _ = store.ListByUser("slackuser")
+			assert.Empty(t, byUser, "should not index the spot by name")
+			rec, _ := GetRecurrence(store, "B1")
+			assert.Equal(t, "U1", rec.RegisteredBy)
+			a, _ := GetAssignment(store, "B1")
+			assert.Equal(t, Assignment{ID: "B1", Holder: "U1", AssignedBy: "U3"}, a)
+			c, _ := GetCatalogSpot(store, "B1")
+			assert.Equal(t, "U1", c.Holder)
+			u, _ := GetUser(store, "U2")
+			assert.Equal(t, User{ID: "U2", Name: "ponyboy"}, u)
+
+			changed, _ = MigrateUserNames(store, map[string]string{"slackuser": "U1", "ponyboy": "U2", "boss": "U3"})
+			assert.Equal(t, 0, changed, "should only migrate once")
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package data
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// waitlist - the collection the users waiting for spots are kept in
+const waitlist = "waitlist"
+
+// Want - a user waiting for a spot to open on a date
+type Want struct {
+	// Date - The date the user wants a spot for
+	Date string
+
+	// User - The user who wants a spot
+	User string
+
+	// Seq - The place of the user in the date's waitlist, lower goes first
+	Seq int
+
+	// WantedAt - When the user joined the waitlist, RFC3339
+	WantedAt string
+
+	// Location - The office or site the user wants a spot at
+	Location string `json:",omitempty"`
+}
+
+// Key - the key for this want
+func (w Want) Key() string {
+	return LocationKey(w.Location, fmt.Sprintf("%s-%s", w.Date, w.User))
+}
+
+// GetWant - get a user's place in the waitlist of a date
+func GetWant(r Reader, key string) (Want, error) {
+	var w Want
+	err := r.GetRecord(waitlist, key, &w)
+	return w, err
+}
+
+// ListWants - list everyone waiting for spots keyed by location, date and user
+func ListWants(r Reader) (map[string]Want, error) {
+	records, err := r.ListRecords(waitlist)
+	if err != nil {
+		return nil, err
+	}
+	ws := make(map[string]Want, len(records))
+	for k, record := range records {
+		var w Want
+		if err := json.Unmarshal(record, &w); err != nil {
+			return nil, err
+		}
+		ws[k] = w
+	}
+	return ws, nil
+}
+
+// Waitlist - the users waiting for a spot at the location on the date, first come first
+func Waitlist(r Reader, location string, date string) ([]Want, error) {
+	all, err := ListWants(r)
+	if err != nil {
+		return nil, err
+	}
+	var ws []Want
+	for _, w := range all {
+		if w.Location == location && w.Date == date {
+			ws = append(ws, w)
+		}
+	}
+	sort.Slice(ws, func(i, j int) bool {
+		return ws[i].Seq < ws[j].Seq
+	})
+	return ws, nil
+}
+
+// PutWant - add or replace a user's place in a waitlist
+func PutWant(tx Tx, w Want) error {
+	return tx.PutRecord(waitlist, w.Key(), w)
+}
+
+// DeleteWant - take a user out of a waitlist
+func DeleteWant(tx Tx, key string) error {
+	return tx.DeleteRecord(waitlist, key)
+}
@@ -0,0 +1,34 @@
+package data
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWants(t *testing.T) {
+	m := NewMemoryStore()
+	w := Want{Date: "2020-01-08", User: "U1", Seq: 2, WantedAt: "2020-01-08T09:30:00Z"}
+	assert.Nil(t, PutWant(m, w))
+	got, err := GetWant(m, "2020-01-08-U1")
+	assert.Nil(t, err)
+	assert.Equal(t, w, got)
+	ws, _ := ListWants(m)
+	assert.Equal(t, map[string]Want{"2020-01-08-U1": w}, ws)
+	assert.Nil(t, DeleteWant(m, w.Key()))
+	_, err = GetWant(m, w.Key())
+	assert.Equal(t, ErrNotFound, err)
+}
+
+func TestWaitlist(t *testing.T) {
+	m := NewMemoryStore()
+	PutWant(m, Want{Date: "2020-01-08", User: "U2", Seq: 2})
+	PutWant(m, Want{Date: "2020-01-09", User: "U3", Seq: 1})
+	PutWant(m, Want{Date: "2020-01-08", User: "U1", Seq: 1})
+	PutWant(m, Want{Date: "2020-01-08", User: "U4", Seq: 1, Location: "uptown"})
+	got, err := Waitlist(m, "", "2020-01-08")
+	assert.Nil(t, err)
+	assert.Equal(t, []Want{{Date: "2020-01-08", User: "U1", Seq: 1}, {Date: "2020-01-08", User: "U2", Seq: 2}}, got, "should list the date's waitlist first come first")
+	got, _ = Waitlist(m, "uptown", "2020-01-08")
+	assert.Equal(t, []Want{{Date: "2020-01-08", User: "U4", Seq: 1, Location: "uptown"}}, got, "should list the waitlist of the location")
+}
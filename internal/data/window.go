@@ -0,0 +1,155 @@
+package data
+
+import (
+	"sort"
+
+	"github.com/jasonholmberg/slashspot/internal/util"
+)
+
+const (
+	// startOfDay, endOfDay - the times an empty start or end of a window stand for
+	startOfDay = "00:00"
+	endOfDay   = "24:00"
+)
+
+type (
+	// Window - part of a day, from From up to To in util.WindowTimeFormat. An empty From is the start of the day and an
+	// empty To the end of it, so the zero Window is the whole day.
+	Window struct {
+		From string `json:",omitempty"`
+		To   string `json:",omitempty"`
+	}
+
+	// Share - a claim of part of the day of a spot
+	Share struct {
+		// User - The user who claimed the window
+		User string
+
+		// Window - The part of the day claimed
+		Window Window
+
+		// ClaimedAt - When the window was claimed, RFC3339
+		ClaimedAt string
+	}
+)
+
+// IsWhole - returns true if the window is the whole day
+func (w Window) IsWhole() bool {
+	return w.From == "" && w.To == ""
+}
+
+// Start - when the window starts, 00:00 for the start of the day
+func (w Window) Start() string {
+	if w.From == "" {
+		return startOfDay
+	}
+	return w.From
+}
+
+// End - when the window ends, 24:00 for the end of the day
+func (w Window) End() string {
+	if w.To == "" {
+		return endOfDay
+	}
+	return w.To
+}
+
+// Contains - returns true if the other window is within this one
+func (w Window) Contains(other Window) bool {
+	return w.Start() <= other.Start() && other.End() <= w.End()
+}
+
+// Overlaps - returns true if the windows have time in common
+func (w Window) Overlaps(other Window) bool {
+	return w.Start() < other.End() && other.Start() < w.End()
+}
+
+// String - am, pm or the start and end like 09:00-12:30, the way util.ParseWindow understands it
+func (w Window) String() string {
+	switch {
+	case w.IsWhole():
+		return "all day"
+	case w.From == "" && w.To == util.Noon:
+		return "am"
+	case w.From == util.Noon && w.To == "":
+		return "pm"
+	}
+	return w.Start() + "-" + w.End()
+}
+
+// IsShared - returns true if parts of the day of the spot have been claimed
+func (s Spot) IsShared() bool {
+	return len(s.Shares) > 0
+}
+
+// IsOpen - returns true if some of the spot's window is not claimed
+func (s Spot) IsOpen() bool {
+	return len(s.Free()) > 0
+}
+
+// OpenFor - returns true if the whole window is within the spot's window and not claimed
+func (s Spot) OpenFor(w Window) bool {
+	if s.IsClaimed() || !s.Window.Contains(w) {
+		return false
+	}
+	for _, share := range s.Shares {
+		if share.Window.Overlaps(w) {
+			return false
+		}
+	}
+	return true
+}
+
+// Free - the parts of the spot's window that are not claimed, in order
+func (s Spot) Free() []Window {
+	if s.IsClaimed() {
+		return nil
+	}
+	shares := append([]Share(nil), s.Shares...)
+	sort.Slice(shares, func(i, j int) bool {
+		return shares[i].Window.Start() < shares[j].Window.Start()
+	})
+	var free []Window
+	start := s.Window.Start()
+	for _, share := range shares {
+		if share.Window.Start() > start {
+			free = append(free, window(start, share.Window.Start()))
+		}
+		if share.Window.End() > start {
+			start = share.Window.End()
+		}
+	}
+	if start < s.Window.End() {
+		free = append(free, window(start, s.Window.End()))
+	}
+	return free
+}
+
+// ClaimOf - when the user claimed the spot or part of its day, returns false if they have not. No one is an empty
+// user, so an empty user has never claimed a spot.
+func (s Spot) ClaimOf(user string) (string, bool) {
+	if user == "" {
+		return "", false
+	}
+	if s.ClaimedBy == user {
+		return s.ClaimedAt, true
+	}
+	for _, share := range s.Shares {
+		if share.User == user {
+			return share.ClaimedAt, true
+		}
+	}
+	return "", false
+}
+
+// window - the window between the times, the start and end of the day are kept empty
+func window(start string, end string) Window {
+	w := Window{From: start, To: end}
+	if w.From == startOfDay {
+		w.From = ""
+	}
+	if w.To == endOfDay {
+		w.To = ""
+	}
+	return w
+}
@@ -0,0 +1,89 @@
+package data
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWindow_String(t *testing.T) {
+	tests := []struct {
+		name   string
+		window Window
+		want   string
+	}{
+		{name: "should be all day", window: Window{}, want: "all day"},
+		{name: "should be the morning", window: Window{To: "12:00"}, want: "am"},
+		{name: "should be the afternoon", window: Window{From: "12:00"}, want: "pm"},
+		{name: "should be a start and end", window: Window{From: "09:00", To: "13:30"}, want: "09:00-13:30"},
+		{name: "should start at the start of the day", window: Window{To: "09:00"}, want: "00:00-09:00"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.window.String())
+		})
+	}
+}
+
+func TestWindow_Overlaps(t *testing.T) {
+	am := Window{To: "12:00"}
+	assert.True(t, am.Overlaps(Window{From: "11:00", To: "13:00"}))
+	assert.False(t, am.Overlaps(Window{From: "12:00"}), "should not overlap a window starting as it ends")
+	assert.True(t, Window{}.Overlaps(am), "should overlap with the whole day")
+	assert.True(t, Window{}.Contains(am))
+	assert.False(t, am.Contains(Window{From: "11:00", To: "13:00"}))
+}
+
+func TestSpot_Free(t *testing.T) {
+	tests := []struct {
+		name string
+		spot Spot
+		want []Window
+	}{
+		{name: "should be free all day", spot: Spot{}, want: []Window{{}}},
+		{name: "should not be free when claimed", spot: Spot{ClaimedBy: "ponyboy"}, want: nil},
+		{name: "should be free in its window", spot: Spot{Window: Window{From: "12:00"}}, want: []Window{{From: "12:00"}}},
+		{
+			name: "should be free around shares",
+			spot: Spot{Shares: []Share{
+				{User: "sodapop", Window: Window{From: "13:00", To: "15:00"}},
+				{User: "ponyboy", Window: Window{From: "09:00", To: "12:00"}},
+			}},
+			want: []Window{{To: "09:00"}, {From: "12:00", To: "13:00"}, {From: "15:00"}},
+		},
+		{
+			name: "should not be free when shares cover its window",
+			spot: Spot{Window: Window{To: "12:00"}, Shares: []Share{{User: "ponyboy", Window: Window{To: "12:00"}}}},
+			want: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.spot.Free())
+			assert.Equal(t, len(tt.want) > 0, tt.spot.IsOpen())
+		})
+	}
+}
+
+func TestSpot_OpenFor(t *testing.T) {
+	spot := Spot{Window: Window{From: "08:00"}, Shares: []Share{{User: "ponyboy", Window: Window{From: "12:00", To: "14:00"}}}}
+	assert.True(t, spot.OpenFor(Window{From: "08:00", To: "12:00"}))
+	assert.False(t, spot.OpenFor(Window{From: "07:00", To: "09:00"}), "should not be open outside its window")
+	assert.False(t, spot.OpenFor(Window{From: "13:00", To: "15:00"}), "should not be open when shared")
+	assert.False(t, Spot{ClaimedBy: "ponyboy"}.OpenFor(Window{To: "12:00"}), "should not be open when claimed")
+}
+
+func TestSpot_ClaimOf(t *testing.T) {
+	spot := Spot{ClaimedBy: "ponyboy", ClaimedAt: "2020-01-08T09:00:00Z"}
+	at, ok := spot.ClaimOf("ponyboy")
+	assert.True(t, ok)
+	assert.Equal(t, "2020-01-08T09:00:00Z", at)
+	shared := Spot{Shares: []Share{{User: "sodapop", ClaimedAt: "2020-01-08T10:00:00Z"}}}
+	at, ok = shared.ClaimOf("sodapop")
+	assert.True(t, ok)
+	assert.Equal(t, "2020-01-08T10:00:00Z", at)
+	_, ok = shared.ClaimOf("ponyboy")
+	assert.False(t, ok)
+	_, ok = Spot{}.ClaimOf("")
+	assert.False(t, ok, "should not count an empty user as the claimant of an open spot")
+}
@@ -0,0 +1,69 @@
+package handlers
+
+import (
+	"sync"
+	"time"
+
+	"github.com/nlopes/slack"
+)
+
+// Admins - decides which slack users are spot admins
+type Admins interface {
+	IsAdmin(userID string) (bool, error)
+}
+
+// AdminList - Admins from a fixed list of slack user IDs
+type AdminList map[string]bool
+
+// NewAdminList - An AdminList constructor
+func NewAdminList(userIDs ...string) AdminList {
+	admins := make(AdminList, len(userIDs))
+	for _, id := range userIDs {
+		admins[id] = true
+	}
+	return admins
+}
+
+// IsAdmin - returns true if the user is in the list
+func (a AdminList) IsAdmin(userID string) (bool, error) {
+	return a[userID], nil
+}
+
+// SlackGroupAdmins - Admins are the members of a slack user group, looked up with the usergroups.users.list API and
+// cached for a few minutes
+type SlackGroupAdmins struct {
+	client  *slack.Client
+	group   string
+	lock    sync.Mutex
+	members map[string]bool
+	expires time.Time
+}
+
+// adminGroupTTL - how long the members of the admin group are cached
+const adminGroupTTL = 5 * time.Minute
+
+// NewSlackGroupAdmins - A SlackGroupAdmins constructor
+func NewSlackGroupAdmins(client *slack.Client, group string) *SlackGroupAdmins {
+	return &SlackGroupAdmins{
+		client: client,
+		group:  group,
+	}
+}
+
+// IsAdmin - returns true if the user is a member of the group
+func (s *SlackGroupAdmins) IsAdmin(userID string) (bool, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.members == nil || time.Now().After(s.expires) {
+		ids, err := s.client.GetUserGroupMembers(s.group)
+		if err != nil {
+			return false, err
+		}
+		s.members = make(map[string]bool, len(ids))
+		for _, id := range ids {
+			s.members[id] = true
+		}
+		s.expires = time.Now().Add(adminGroupTTL)
+	}
+	return s.members[userID], nil
+}
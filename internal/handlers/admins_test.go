@@ -0,0 +1,16 @@
+package handlers
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestAdminList_IsAdmin(t *testing.T) {
+	admins := NewAdminList("U1", "U2")
+	ok, err := admins.IsAdmin("U1")
+	assert.NilError(t, err)
+	assert.Assert(t, ok, "should be an admin")
+	ok, _ = admins.IsAdmin("U3")
+	assert.Assert(t, !ok, "should not be an admin")
+}
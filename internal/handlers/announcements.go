@@ -0,0 +1,219 @@
+package handlers
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/jasonholmberg/slashspot/internal/data"
+	"github.com/jasonholmberg/slashspot/internal/spot"
+	"github.com/nlopes/slack"
+)
+
+const (
+	// AnnouncementTemplate - Spot announcement template, what the spot is on its dates and who registered it
+	AnnouncementTemplate = "Spot %s is %s (from %s)"
+
+	// AnnouncementOpenTemplate - The announced dates the spot can be claimed on
+	AnnouncementOpenTemplate = "open %s"
+
+	// AnnouncementHeldTemplate - The announced dates the spot is held for someone on the waitlist
+	AnnouncementHeldTemplate = "held for the waitlist %s"
+
+	// AnnouncementClaimedTemplate - The announced dates the spot is claimed
+	AnnouncementClaimedTemplate = "claimed %s"
+
+	// announcementQueue - how many events can wait to be announced before they are dropped
+	announcementQueue = 100
+)
+
+type (
+	// chat - posts and updates messages in a slack channel
+	chat interface {
+		post(channel string, text string) (timestamp string, err error)
+		update(channel string, timestamp string, text string) error
+	}
+
+	// slackChat - chat with the chat.postMessage and chat.update APIs
+	slackChat struct {
+		client *slack.Client
+	}
+
+	// SlackAnnouncer - a spot.Notifier that announces newly opened spots in a slack channel, and updates the
+	// announcement when they are claimed
+	SlackAnnouncer struct {
+		chat    chat
+		channel string
+		events  chan spot.Event
+		// announcements - the announcement of each spot registration by key, only used by the worker
+		announcements map[string]*announcement
+	}
+
+	// announcement - a message about a spot registered for one or more dates
+	announcement struct {
+		timestamp string
+		spots     []data.Spot
+	}
+)
+
+func (c slackChat) post(channel string, text string) (string, error) {
+	_, timestamp, err := c.client.PostMessage(channel, slack.MsgOptionText(text, false))
+	return timestamp, err
+}
+
+func (c slackChat) update(channel string, timestamp string, text string) error {
+	_, _, _, err := c.client.UpdateMessage(channel, timestamp, slack.MsgOptionText(text, false))
+	return err
+}
+
+// NewSlackAnnouncer - A SlackAnnouncer constructor, the client needs a bot token with the chat:write scope and the
+// bot must be in the channel
+func NewSlackAnnouncer(client *slack.Client, channel string) *SlackAnnouncer {
+	a := newSlackAnnouncer(slackChat{client: client}, channel)
+	go a.work()
+	return a
+}
+
+func newSlackAnnouncer(chat chat, channel string) *SlackAnnouncer {
+	return &SlackAnnouncer{
+		chat:          chat,
+		channel:       channel,
+		events:        make(chan spot.Event, announcementQueue),
+		announcements: make(map[string]*announcement),
+	}
+}
+
+// Notify - queue the event to be announced, events are announced in order
+func (a *SlackAnnouncer) Notify(e spot.Event) {
+	select {
+	case a.events <- e:
+	default:
+		log.Printf("Too many announcements waiting, dropped %v event for %d spots", e.Kind, len(e.Spots))
+	}
+}
+
+func (a *SlackAnnouncer) work() {
+	for e := range a.events {
+		a.announce(e)
+	}
+}
+
+// announce - post a message for registered spots someone can claim, or update the message of spots claimed, held and
+// released. Spots no one can claim yet are announced once they can be.
+func (a *SlackAnnouncer) announce(e spot.Event) {
+	a.forget(e.Today)
+	if e.Kind == spot.SpotsRegistered {
+		ann := &announcement{spots: append([]data.Spot(nil), e.Spots...)}
+		if ann.open() && !a.post(ann, e.Today) {
+			return
+		}
+		for _, s := range e.Spots {
+			a.announcements[s.Key()] = ann
+		}
+		return
+	}
+	for _, s := range e.Spots {
+		ann, ok := a.announcements[s.Key()]
+		if !ok {
+			continue
+		}
+		ann.replace(s)
+		if ann.timestamp == "" {
+			if ann.open() {
+				a.post(ann, e.Today)
+			}
+			continue
+		}
+		if err := a.chat.update(a.channel, ann.timestamp, ann.text(e.Today)); err != nil {
+			log.Print("Error updating spot announcement ", err)
+		}
+	}
+}
+
+// post - post the announcement, returns false if it could not be posted
+func (a *SlackAnnouncer) post(ann *announcement, today string) bool {
+	timestamp, err := a.chat.post(a.channel, ann.text(today))
+	if err != nil {
+		log.Print("Error announcing spots ", err)
+		return false
+	}
+	ann.timestamp = timestamp
+	return true
+}
+
+// forget - stop keeping announcements whose dates have all passed
+func (a *SlackAnnouncer) forget(today string) {
+	for key, ann := range a.announcements {
+		if ann.over(today) {
+			delete(a.announcements, key)
+		}
+	}
+}
+
+// over - returns true when every date of the announcement is before today
+func (ann *announcement) over(today string) bool {
+	for _, s := range ann.spots {
+		if s.OpenDate >= today {
+			return false
+		}
+	}
+	return true
+}
+
+// replace - replace the announced registration of the spot with the spot
+func (ann *announcement) replace(s data.Spot) {
+	for i := range ann.spots {
+		if ann.spots[i].Key() == s.Key() {
+			ann.spots[i] = s
+		}
+	}
+}
+
+// open - returns true if the spot can be claimed on any of the announced dates
+func (ann *announcement) open() bool {
+	for _, s := range ann.spots {
+		if claimable(s) {
+			return true
+		}
+	}
+	return false
+}
+
+// claimable - returns true if some of the spot is free and it is not held for someone on the waitlist
+func claimable(s data.Spot) bool {
+	return s.IsOpen() && s.HeldFor == ""
+}
+
+// text - the announcement, dates that are today are called today. The dates of a spot shared by users claiming parts
+// of the day come with the parts still free.
+func (ann *announcement) text(today string) string {
+	var open, held, claimed []string
+	for _, s := range ann.spots {
+		date := s.OpenDate
+		if date == today {
+			date = "today"
+		}
+		switch {
+		case claimable(s) && s.IsShared():
+			open = append(open, date+" "+strings.Join(windowTexts(s.Free()), " and "))
+		case claimable(s):
+			open = append(open, date)
+		case s.IsOpen():
+			held = append(held, date)
+		default:
+			claimed = append(claimed, date)
+		}
+	}
+	var parts []string
+	if len(open) > 0 {
+		parts = append(parts, fmt.Sprintf(AnnouncementOpenTemplate, strings.Join(open, ", ")))
+	}
+	if len(held) > 0 {
+		parts = append(parts, fmt.Sprintf(AnnouncementHeldTemplate, strings.Join(held, ", ")))
+	}
+	if len(claimed) > 0 {
+		parts = append(parts, fmt.Sprintf(AnnouncementClaimedTemplate, strings.Join(claimed, ", ")))
+	}
+	id := withWindow(spotName(ann.spots[0].ID, ann.spots[0].Location), ann.spots[0].Window)
+	return fmt.Sprintf(AnnouncementTemplate, id, strings.Join(parts, ", "), fmt.Sprintf("<@%s>", ann.spots[0].RegisteredBy))
+}
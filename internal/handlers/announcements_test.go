@@ -0,0 +1,178 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/jasonholmberg/slashspot/internal/data"
+	"github.com/jasonholmberg/slashspot/internal/spot"
+	"gotest.tools/v3/assert"
+)
+
+// testChat - a chat that remembers the messages instead of sending them, messages are keyed by timestamp
+type testChat struct {
+	messages map[string]string
+	err      error
+}
+
+func (c *testChat) post(channel string, text string) (string, error) {
+	if c.err != nil {
+		return "", c.err
+	}
+	timestamp := fmt.Sprint(len(c.messages) + 1)
+	c.messages[timestamp] = text
+	return timestamp, nil
+}
+
+func (c *testChat) update(channel string, timestamp string, text string) error {
+	c.messages[timestamp] = text
+	return nil
+}
+
+func announcedSpot(id string, days int, claimedBy string) data.Spot {
+	return data.Spot{ID: id, OpenDate: dateSpecForTest(days), RegisteredBy: "U1", ClaimedBy: claimedBy}
+}
+
+// announced - the announcement of a spot registered by U1
+func announced(id string, parts ...string) string {
+	return fmt.Sprintf(AnnouncementTemplate, id, strings.Join(parts, ", "), "<@U1>")
+}
+
+func openOn(dates string) string {
+	return fmt.Sprintf(AnnouncementOpenTemplate, dates)
+}
+
+func heldOn(dates string) string {
+	return fmt.Sprintf(AnnouncementHeldTemplate, dates)
+}
+
+func claimedOn(dates string) string {
+	return fmt.Sprintf(AnnouncementClaimedTemplate, dates)
+}
+
+func heldSpot(id string, days int) data.Spot {
+	s := announcedSpot(id, days, "")
+	s.HeldFor = "U3"
+	s.HeldUntil = "2020-01-08T09:45:00-06:00"
+	return s
+}
+
+func TestSlackAnnouncer_announce(t *testing.T) {
+	today := dateSpecForTest(0)
+	tests := []struct {
+		name   string
+		events []spot.Event
+		want   map[string]string
+	}{
+		{
+			name:   "should announce a spot open today",
+			events: []spot.Event{{Kind: spot.SpotsRegistered, Spots: []data.Spot{announcedSpot("42", 0, "")}, Today: today}},
+			want:   map[string]string{"1": announced("42", openOn("today"))},
+		},
+		{
+			name:   "should announce the part of the day a spot is open",
+			events: []spot.Event{{Kind: spot.SpotsRegistered, Spots: []data.Spot{{ID: "42", OpenDate: today, RegisteredBy: "U1", Window: data.Window{From: "12:00"}}}, Today: today}},
+			want:   map[string]string{"1": announced("42 pm", openOn("today"))},
+		},
+		{
+			name: "should announce a range in one message",
+			events: []spot.Event{
+				{Kind: spot.SpotsRegistered, Spots: []data.Spot{announcedSpot("42", 0, ""), announcedSpot("42", 1, "")}, Today: today},
+			},
+			want: map[string]string{"1": announced("42", openOn("today, "+dateSpecForTest(1)))},
+		},
+		{
+			name: "should update the announcement when the spot is claimed",
+			events: []spot.Event{
+				{Kind: spot.SpotsRegistered, Spots: []data.Spot{announcedSpot("42", 0, "")}, Today: today},
+				{Kind: spot.SpotsRegistered, Spots: []data.Spot{announcedSpot("7", 0, "")}, Today: today},
+				{Kind: spot.SpotClaimed, Spots: []data.Spot{announcedSpot("42", 0, "U2")}, Today: today},
+			},
+			want: map[string]string{
+				"1": announced("42", claimedOn("today")),
+				"2": announced("7", openOn("today")),
+			},
+		},
+		{
+			name: "should show the dates of a range still open",
+			events: []spot.Event{
+				{Kind: spot.SpotsRegistered, Spots: []data.Spot{announcedSpot("42", 0, ""), announcedSpot("42", 1, "")}, Today: today},
+				{Kind: spot.SpotClaimed, Spots: []data.Spot{announcedSpot("42", 0, "U2")}, Today: today},
+			},
+			want: map[string]string{"1": announced("42", openOn(dateSpecForTest(1)), claimedOn("today"))},
+		},
+		{
+			name: "should open the announcement again when the spot is released",
+			events: []spot.Event{
+				{Kind: spot.SpotsRegistered, Spots: []data.Spot{announcedSpot("42", 0, "")}, Today: today},
+				{Kind: spot.SpotClaimed, Spots: []data.Spot{announcedSpot("42", 0, "U2")}, Today: today},
+				{Kind: spot.SpotReleased, Spots: []data.Spot{announcedSpot("42", 0, "")}, Today: today},
+			},
+			want: map[string]string{"1": announced("42", openOn("today"))},
+		},
+		{
+			name: "should announce the parts of the day a shared spot is free",
+			events: []spot.Event{
+				{Kind: spot.SpotsRegistered, Spots: []data.Spot{announcedSpot("42", 0, "")}, Today: today},
+				{Kind: spot.SpotClaimed, Spots: []data.Spot{{ID: "42", OpenDate: today, RegisteredBy: "U1", Shares: []data.Share{{User: "U2", Window: data.Window{To: "12:00"}}}}}, Today: today},
+			},
+			want: map[string]string{"1": announced("42", openOn("today pm"))},
+		},
+		{
+			name: "should announce a spot shared all day as claimed",
+			events: []spot.Event{
+				{Kind: spot.SpotsRegistered, Spots: []data.Spot{announcedSpot("42", 0, "")}, Today: today},
+				{Kind: spot.SpotClaimed, Spots: []data.Spot{{ID: "42", OpenDate: today, RegisteredBy: "U1", Shares: []data.Share{{User: "U2", Window: data.Window{To: "12:00"}}, {User: "U3", Window: data.Window{From: "12:00"}}}}}, Today: today},
+			},
+			want: map[string]string{"1": announced("42", claimedOn("today"))},
+		},
+		{
+			name: "should update the announcement when the spot is held",
+			events: []spot.Event{
+				{Kind: spot.SpotsRegistered, Spots: []data.Spot{announcedSpot("42", 0, ""), announcedSpot("42", 1, "")}, Today: today},
+				{Kind: spot.SpotHeld, Spots: []data.Spot{heldSpot("42", 0)}, Today: today},
+			},
+			want: map[string]string{"1": announced("42", openOn(dateSpecForTest(1)), heldOn("today"))},
+		},
+		{
+			name:   "should not announce a spot held when it is registered",
+			events: []spot.Event{{Kind: spot.SpotsRegistered, Spots: []data.Spot{heldSpot("42", 0)}, Today: today}},
+			want:   map[string]string{},
+		},
+		{
+			name: "should announce a held spot once its hold ends",
+			events: []spot.Event{
+				{Kind: spot.SpotsRegistered, Spots: []data.Spot{heldSpot("42", 0)}, Today: today},
+				{Kind: spot.SpotReleased, Spots: []data.Spot{announcedSpot("42", 0, "")}, Today: today},
+			},
+			want: map[string]string{"1": announced("42", openOn("today"))},
+		},
+		{
+			name: "should not update announcements that are over",
+			events: []spot.Event{
+				{Kind: spot.SpotsRegistered, Spots: []data.Spot{announcedSpot("42", 0, "")}, Today: today},
+				{Kind: spot.SpotClaimed, Spots: []data.Spot{announcedSpot("42", 0, "U2")}, Today: dateSpecForTest(1)},
+			},
+			want: map[string]string{"1": announced("42", openOn("today"))},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &testChat{messages: make(map[string]string)}
+			a := newSlackAnnouncer(c, "C1")
+			for _, e := range tt.events {
+				a.announce(e)
+			}
+			assert.DeepEqual(t, c.messages, tt.want)
+		})
+	}
+}
+
+func TestSlackAnnouncer_announceError(t *testing.T) {
+	c := &testChat{messages: make(map[string]string), err: errors.New("not_in_channel")}
+	a := newSlackAnnouncer(c, "C1")
+	a.announce(spot.Event{Kind: spot.SpotsRegistered, Spots: []data.Spot{announcedSpot("42", 0, "")}, Today: dateSpecForTest(0)})
+	assert.Equal(t, len(a.announcements), 0, "should not keep an announcement that was not posted")
+}
@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jasonholmberg/slashspot/internal/data"
+	"github.com/jasonholmberg/slashspot/internal/util"
+	"github.com/nlopes/slack"
+)
+
+const (
+	// OpenSpotsHeader - the heading of the find message
+	OpenSpotsHeader = "The following spots are available today:"
+
+	// OpenSpotBlockTemplate - a spot in the find message
+	OpenSpotBlockTemplate = "*%s* is open today from %s"
+
+	// OpenSpotWindowsTemplate - the parts of the day a spot in the find message is open, when it is not all day
+	OpenSpotWindowsTemplate = ", %s"
+
+	// ClaimButtonText - the text of the claim buttons in the find message
+	ClaimButtonText = "Claim"
+)
+
+// openSpotBlocks - a section for each open spot with who registered it, what it is like and a button to claim it.
+// While today's spots wait for a draw there are no buttons, only how to enter it.
+func (h *Handler) openSpotBlocks(spots []data.Spot, filters []string) []slack.Block {
+	known := make(map[string]data.CatalogSpot)
+	if catalog, err := h.spots.Catalog(); err == nil {
+		for _, c := range catalog {
+			known[c.ID] = c
+		}
+	}
+	blocks := []slack.Block{slack.NewSectionBlock(markdown(OpenSpotsHeader), nil, nil)}
+	pending := h.spots.DrawPending(h.now())
+	if pending {
+		text := fmt.Sprintf(SpotDrawPendingTemplate, h.now().Format(util.SpotDateFormat), h.spots.Cutoff(h.now()).Format(shortTimeFormat))
+		blocks = append(blocks, slack.NewSectionBlock(markdown(text), nil, nil))
+	}
+	for _, s := range spots {
+		text := fmt.Sprintf(OpenSpotBlockTemplate, s.ID, h.userName(s.RegisteredBy))
+		if !s.Window.IsWhole() || s.IsShared() {
+			text += fmt.Sprintf(OpenSpotWindowsTemplate, joinWindows(s.Free()))
+		}
+		if details := describeSpot(known[s.ID]); details != "" {
+			text += "\n_" + details + "_"
+		}
+		var accessory *slack.Accessory
+		if !pending {
+			button := slack.NewButtonBlockElement(ClaimActionID, claimValue(s.ID, filters), plainText(ClaimButtonText))
+			button.WithStyle(slack.StylePrimary)
+			accessory = slack.NewAccessory(button)
+		}
+		blocks = append(blocks, slack.NewSectionBlock(markdown(text), nil, accessory))
+	}
+	return blocks
+}
+
+// describeSpot - what a spot in the catalog is like, like ev, ada, level 2, north
+func describeSpot(c data.CatalogSpot) string {
+	details := append([]string(nil), c.Attributes...)
+	if c.Level != "" {
+		details = append(details, "level "+c.Level)
+	}
+	if c.Garage != "" {
+		details = append(details, c.Garage)
+	}
+	return strings.Join(details, ", ")
+}
+
+// claimValue - the value of a claim button, the spot and the filters of the find message it is in so the message
+// can be found again once the spot is claimed
+func claimValue(id string, filters []string) string {
+	return strings.Join(append([]string{id}, filters...), " ")
+}
+
+// parseClaimValue - the spot and filters in the value of a claim button
+func parseClaimValue(value string) (string, []string) {
+	fields := strings.Fields(value)
+	if len(fields) == 0 {
+		return "", nil
+	}
+	return fields[0], fields[1:]
+}
@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/jasonholmberg/slashspot/internal/data"
+	"github.com/nlopes/slack"
+	"gotest.tools/v3/assert"
+)
+
+func Test_findMessage(t *testing.T) {
+	h := newTestHandler()
+	registerSpotsForTest(h, testSpots())
+	h.spots.ImportCatalog([]data.CatalogSpot{
+		{ID: "B1", Garage: "north", Level: "1", Attributes: []string{"ev"}},
+		{ID: "B2"},
+		{ID: "B4", Level: "2", Attributes: []string{"ev"}},
+	})
+	got := h.findMessage([]string{"ev"})
+	assert.Equal(t, got.Text, fmt.Sprintf(OpenSpotsTemplate, "B1,B4"))
+	assert.Equal(t, len(got.Blocks), 3)
+
+	section := got.Blocks[1].(*slack.SectionBlock)
+	assert.Equal(t, section.Text.Text, fmt.Sprintf(OpenSpotBlockTemplate, "B1", "slackuser")+"\n_ev, level 1, north_")
+	button := section.Accessory.ButtonElement
+	assert.Equal(t, button.ActionID, ClaimActionID)
+	assert.Equal(t, button.Value, "B1 ev")
+}
+
+func Test_findMessageDrawPending(t *testing.T) {
+	h := newTestHandler()
+	h.spots = h.spots.WithLottery(10*time.Hour, false)
+	registerSpotsForTest(h, testSpots())
+	got := h.findMessage(nil)
+	assert.Equal(t, got.Blocks[1].(*slack.SectionBlock).Text.Text, fmt.Sprintf(SpotDrawPendingTemplate, "2020-01-08", "2020-01-08 10:00"))
+	for _, block := range got.Blocks[2:] {
+		assert.Assert(t, block.(*slack.SectionBlock).Accessory == nil, "should not offer to claim spots waiting for a draw")
+	}
+}
+
+func Test_describeSpot(t *testing.T) {
+	assert.Equal(t, describeSpot(data.CatalogSpot{ID: "B1", Garage: "north", Level: "2", Attributes: []string{"ev", "ada"}}), "ev, ada, level 2, north")
+	assert.Equal(t, describeSpot(data.CatalogSpot{ID: "B1"}), "")
+}
+
+func Test_claimValue(t *testing.T) {
+	id, filters := parseClaimValue(claimValue("B1", []string{"ev", "level:2"}))
+	assert.Equal(t, id, "B1")
+	assert.DeepEqual(t, filters, []string{"ev", "level:2"})
+	id, filters = parseClaimValue(claimValue("B1", nil))
+	assert.Equal(t, id, "B1")
+	assert.Equal(t, len(filters), 0)
+}
@@ -0,0 +1,117 @@
+package handlers
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jasonholmberg/slashspot/internal/data"
+	"github.com/jasonholmberg/slashspot/internal/spot"
+	"github.com/nlopes/slack"
+)
+
+const (
+	// AdminCatalogTemplate - The header for the list of the spots in the catalog
+	AdminCatalogTemplate = "Spot catalog:\n%s"
+
+	// AdminCatalogSpotTemplate - A spot in the catalog, what it is like and who holds it
+	AdminCatalogSpotTemplate = "- %s%s"
+
+	// AdminCatalogHolderTemplate - Who holds a spot in the catalog
+	AdminCatalogHolderTemplate = ", held by %s"
+
+	// NoAdminCatalog - There are no spots in the catalog
+	NoAdminCatalog = "There are no spots in the catalog, any spot can be registered."
+
+	// AdminCatalogAddTemplate - Admin added a spot to the catalog
+	AdminCatalogAddTemplate = "Spot %s is in the catalog"
+
+	// AdminCatalogRetireTemplate - Admin took a spot out of the catalog
+	AdminCatalogRetireTemplate = "Spot %s has been retired from the catalog"
+)
+
+// handleAdminCatalog - admin catalog [add|retire|reassign] ...
+func (h *Handler) handleAdminCatalog(cmd *slack.SlashCommand, params []string) string {
+	if len(params) < 3 {
+		return h.handleAdminCatalogList()
+	}
+	if len(params) < 4 {
+		return IDKBlank
+	}
+	id := params[3]
+	switch action := strings.ToLower(params[2]); action {
+	case "add":
+		c := data.CatalogSpot{ID: id}
+		for _, p := range params[4:] {
+			lower := strings.ToLower(p)
+			switch {
+			case p == "":
+			case strings.HasPrefix(lower, data.LevelFilter):
+				c.Level = p[len(data.LevelFilter):]
+			case strings.HasPrefix(lower, data.GarageFilter):
+				c.Garage = p[len(data.GarageFilter):]
+			default:
+				c.Attributes = append(c.Attributes, lower)
+			}
+		}
+		if _, err := h.spots.AddCatalogSpot(c, cmd.UserID); err != nil {
+			return fmt.Sprintf(AdminErrorTemplate, "add the spot")
+		}
+		return fmt.Sprintf(AdminCatalogAddTemplate, id)
+	case "retire":
+		err := h.spots.RetireCatalogSpot(id, cmd.UserID)
+		if err == spot.ErrUnknownSpot {
+			return fmt.Sprintf(SpotUnknownTemplate, id)
+		}
+		if err != nil {
+			return fmt.Sprintf(AdminErrorTemplate, "retire the spot")
+		}
+		return fmt.Sprintf(AdminCatalogRetireTemplate, id)
+	case "reassign":
+		if len(params) > 5 {
+			return IDKBlank
+		}
+		var holder string
+		if len(params) == 5 {
+			var err error
+			if holder, err = h.userID(params[4]); err != nil {
+				return fmt.Sprintf(UnknownUserTemplate, params[4])
+			}
+		}
+		_, err := h.spots.ReassignCatalogSpot(id, holder, cmd.UserID)
+		if err == spot.ErrUnknownSpot {
+			return fmt.Sprintf(SpotUnknownTemplate, id)
+		}
+		if err != nil {
+			return fmt.Sprintf(AdminErrorTemplate, "reassign the spot")
+		}
+		if holder == "" {
+			return fmt.Sprintf(AdminUnassignTemplate, id)
+		}
+		return fmt.Sprintf(AdminAssignTemplate, id, h.userName(holder))
+	default:
+		return AdminHelpText
+	}
+}
+
+// handleAdminCatalogList - the spots in the catalog at the admin's location, what they are like and who holds them
+func (h *Handler) handleAdminCatalogList() string {
+	catalog, err := h.spots.Catalog()
+	if err != nil {
+		return fmt.Sprintf(AdminErrorTemplate, "list the catalog")
+	}
+	if len(catalog) == 0 {
+		return NoAdminCatalog
+	}
+	var lines []string
+	for _, c := range catalog {
+		details := ""
+		if d := describeSpot(c); d != "" {
+			details = ": " + d
+		}
+		if holder, err := h.spots.Holder(c.ID); err == nil && holder != "" {
+			details += fmt.Sprintf(AdminCatalogHolderTemplate, h.userName(holder))
+		}
+		lines = append(lines, fmt.Sprintf(AdminCatalogSpotTemplate, c.ID, details))
+	}
+	return fmt.Sprintf(AdminCatalogTemplate, strings.Join(lines, "\n"))
+}
@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/jasonholmberg/slashspot/internal/data"
+	"github.com/nlopes/slack"
+	"gotest.tools/v3/assert"
+)
+
+func Test_handleAdminCatalog(t *testing.T) {
+	h := newTestHandler(WithAdmins(NewAdminList("U1")))
+	h.spots.RememberUser(data.User{ID: "U7", Name: "pparker"})
+	admin := &slack.SlashCommand{UserID: "U1", UserName: "boss"}
+	tests := []struct {
+		name   string
+		params []string
+		want   string
+	}{
+		{
+			name:   "should say there is no catalog",
+			params: []string{"admin", "catalog"},
+			want:   NoAdminCatalog,
+		},
+		{
+			name:   "should add a spot",
+			params: []string{"admin", "catalog", "add", "B1", "EV", "level:2", "garage:north"},
+			want:   fmt.Sprintf(AdminCatalogAddTemplate, "B1"),
+		},
+		{
+			name:   "should add a plain spot",
+			params: []string{"admin", "catalog", "add", "B2"},
+			want:   fmt.Sprintf(AdminCatalogAddTemplate, "B2"),
+		},
+		{
+			name:   "should reassign a spot",
+			params: []string{"admin", "catalog", "reassign", "B1", "@pparker"},
+			want:   fmt.Sprintf(AdminAssignTemplate, "B1", "pparker"),
+		},
+		{
+			name:   "should list the catalog",
+			params: []string{"admin", "catalog"},
+			want: fmt.Sprintf(AdminCatalogTemplate, strings.Join([]string{
+				fmt.Sprintf(AdminCatalogSpotTemplate, "B1", ": ev, level 2, north"+fmt.Sprintf(AdminCatalogHolderTemplate, "pparker")),
+				fmt.Sprintf(AdminCatalogSpotTemplate, "B2", ""),
+			}, "\n")),
+		},
+		{
+			name:   "should leave a spot unheld",
+			params: []string{"admin", "catalog", "reassign", "B1"},
+			want:   fmt.Sprintf(AdminUnassignTemplate, "B1"),
+		},
+		{
+			name:   "should not reassign a spot to an unknown user",
+			params: []string{"admin", "catalog", "reassign", "B1", "@mjwatson"},
+			want:   fmt.Sprintf(UnknownUserTemplate, "@mjwatson"),
+		},
+		{
+			name:   "should not reassign an unknown spot",
+			params: []string{"admin", "catalog", "reassign", "Z9", "@pparker"},
+			want:   fmt.Sprintf(SpotUnknownTemplate, "Z9"),
+		},
+		{
+			name:   "should retire a spot",
+			params: []string{"admin", "catalog", "retire", "B2"},
+			want:   fmt.Sprintf(AdminCatalogRetireTemplate, "B2"),
+		},
+		{
+			name:   "should not retire an unknown spot",
+			params: []string{"admin", "catalog", "retire", "B2"},
+			want:   fmt.Sprintf(SpotUnknownTemplate, "B2"),
+		},
+		{
+			name:   "should need a spot",
+			params: []string{"admin", "catalog", "add"},
+			want:   IDKBlank,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, h.forUser(commandUser(admin)).handleAdmin(admin, tt.params), tt.want)
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package handlers
+
+import (
+	"log"
+)
+
+// ResponsePool - a fixed number of workers that finish commands after slack has been answered, so slow work never
+// runs into slack's 3 second timeout
+type ResponsePool struct {
+	jobs chan func()
+}
+
+// NewResponsePool - A ResponsePool constructor, starts the workers. At most queue jobs wait for a worker.
+func NewResponsePool(workers int, queue int) *ResponsePool {
+	p := &ResponsePool{
+		jobs: make(chan func(), queue),
+	}
+	for i := 0; i < workers; i++ {
+		go p.work()
+	}
+	return p
+}
+
+// Submit - queue the job for a worker, returns false when the queue is full
+func (p *ResponsePool) Submit(job func()) bool {
+	select {
+	case p.jobs <- job:
+		return true
+	default:
+		return false
+	}
+}
+
+func (p *ResponsePool) work() {
+	for job := range p.jobs {
+		p.run(job)
+	}
+}
+
+// run - run the job, a job that panics does not take its worker with it
+func (p *ResponsePool) run(job func()) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Print("Error finishing a response ", r)
+		}
+	}()
+	job()
+}
+
+// WithResponsePool - answer slack right away and finish commands and button clicks with the pool, posting the response
+// to the response URL slack gave
+func WithResponsePool(pool *ResponsePool) Option {
+	return func(h *Handler) {
+		h.responses = pool
+	}
+}
@@ -0,0 +1,39 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestResponsePool_Submit(t *testing.T) {
+	done := make(chan int, 2)
+	p := NewResponsePool(1, 2)
+	assert.Assert(t, p.Submit(func() { panic("boom") }), "should queue a job")
+	assert.Assert(t, p.Submit(func() { done <- 1 }), "should queue a job")
+	assert.Equal(t, <-done, 1, "should keep working after a job panics")
+
+	full := NewResponsePool(0, 1)
+	assert.Assert(t, full.Submit(func() {}), "should queue a job")
+	assert.Assert(t, !full.Submit(func() {}), "should not queue a job when the queue is full")
+}
+
+func TestSlashCommandHandlerDeferred(t *testing.T) {
+	server, posted := responseURL(t)
+	defer server.Close()
+	h := newTestHandler(WithResponsePool(NewResponsePool(1, 1)))
+
+	rr := httptest.NewRecorder()
+	h.SlashCommandHandler(rr, signedRequest(t, "/command", url.Values{
+		"command":      {"/spot"},
+		"text":         {"help"},
+		"user_id":      {"U1"},
+		"response_url": {server.URL},
+	}))
+	assert.Equal(t, rr.Code, http.StatusOK)
+	assert.Equal(t, rr.Body.String(), "", "should answer slack right away")
+	assert.Equal(t, (<-posted).Text, HelpText, "should post the response to the response URL")
+}
@@ -25,12 +25,48 @@ With Slash-Spot you can find, reserve and register parking spots.
 
 */spot help* - returns the help text you're currently reading
 */spot version* - returns version information about this utility
-*/spot find or open* - will deliver a list of spots available today
-*/spot claim or take or reserve <spot-id>* - will attempt claim/take/reserve the requested spot
-*/spot reg or register or set <spot-id> [date]* - will make a spot available for use for the day. 
+*/spot find or open [window] [filters]* - will deliver a list of spots available today
+	Filters like _ev_, _compact_, _ada_, _level:2_ or _garage:north_ only find spots with those features.
+	A window like _am_, _pm_ or _09:00-12:30_ only finds spots open for all of it.
+*/spot claim or take or reserve <spot-id> [date] [window]* - will attempt claim/take/reserve the requested spot for today or a later date it is registered for
+	With a window like _am_, _pm_ or _09:00-12:30_ you claim only that part of the day, so others can share the spot.
+*/spot release <spot-id> [date]* - will give back a spot you claimed today or for the date so someone else can use it
+*/spot reg or register or set <spot-id> [date] [weekdays] [window]* - will make a spot available for use for the day. 
 	If a data is given, the spot will be made available for that date. That date must be in the future.
+	The date can be YYYY-MM-DD, _today_, _tomorrow_, a day like _fri_ or _next tue_, _+3d_ or _11/14_.
+	It can also be a range like 2020-01-06..2020-01-17 or a comma separated list of dates and ranges.
+	Add _weekdays_ to leave Saturdays and Sundays out, and a window like _am_, _pm_ or _13:00-18:00_ to make it available for only part of the day.
+*/spot reg <spot-id> every <days> [until <date>]* - will make a spot available on the same days every week, like _every mon,wed until 2020-12-31_
+*/spot reg* - will open a form to pick one of your spots and the date or days to make it available
+*/spot recurring* - will list the spots you have registered to recur
+*/spot recurring cancel <spot-id>* - will stop a spot from recurring
 */spot drop <spot-id>* - will attempt to drop a spot registration as long as your are the registering user
 */spot drop all* - will attempt to drop all spots you have registered.
+*/spot mine* - will list the spots you have registered and who has claimed them
+*/spot want [date]* - will put you on the waitlist for a spot today or on the date, you'll hear from me when one opens
+*/spot want cancel [date]* - will take you off the waitlist
+*/spot enter [date]* - will put you in the draw for the spots of today or the date, when spots are given out by a draw
+*/spot enter cancel [date]* - will take you out of the draw
+*/spot stats [week or month]* - will show how often each spot was shared and claimed this week or month, and how you used them
+*/spot location [name]* - will tell you where you find, claim and register spots when there is more than one location, or make it the named location
+	Add a location like _@downtown_ to any command to use that location once.
+*/spot admin* - will list the commands spot admins can use
+`
+
+	// AdminHelpText - the help text for admins
+	AdminHelpText = `*Slash-Spot Admin Help*:
+*/spot admin list* - will list every current and future registration
+*/spot admin drop <spot-id> [date]* - will drop anyone's registration of a spot for today or the date, even if it is claimed
+*/spot admin release <spot-id>* - will give back a spot claimed today whoever claimed it
+*/spot admin assign <spot-id> [user]* - will make the user the holder of a spot, leave the user out so no one holds it
+*/spot admin purge* - will delete all past registrations, ended recurring spots and the waitlists and draw entries of past dates
+*/spot admin draw [date]* - will show who entered the draw for today or the date, who won and the seed it was made with
+*/spot admin export [week or month]* - will give the use of each spot, holder and claimant this week or month as CSV
+*/spot admin catalog* - will list the spots in the catalog
+*/spot admin catalog add <spot-id> [level:<level>] [garage:<garage>] [attributes]* - will add a spot to the catalog, or change what it is like
+*/spot admin catalog retire <spot-id>* - will take a spot out of the catalog
+*/spot admin catalog reassign <spot-id> [user]* - will make the user the holder of a spot in the catalog, leave the user out so no one holds it
+When there is more than one location these work at yours, add a location like _@downtown_ to use another.
 `
 
 	// VersionText - the version text
@@ -51,20 +87,107 @@ With Slash-Spot you can find, reserve and register parking spots.
 	// NoSpotsAvailable - No spots
 	NoSpotsAvailable = "There are currently no available registered spots."
 
+	// NoMatchingSpotsTemplate - No spots pass the find filters
+	NoMatchingSpotsTemplate = "There are currently no available registered spots matching %s."
+
 	// SpotClaimedTemplate - Spot claimed template
 	SpotClaimedTemplate = "You have claimed spot: %v"
 
 	// SpotClaimErrorTemplate - Claim error template
 	SpotClaimErrorTemplate = "The spot %s is not available today or has not been registered as available"
 
+	// SpotClaimedForTemplate - Spot claimed for a later date template
+	SpotClaimedForTemplate = "You have claimed spot %s for %s"
+
+	// SpotClaimDateErrorTemplate - Claim for a later date error template
+	SpotClaimDateErrorTemplate = "The spot %s is not available on %s or has not been registered as available"
+
+	// SpotBeyondHorizonTemplate - Spot claimed too far ahead template
+	SpotBeyondHorizonTemplate = "Spots can only be claimed up to %d days ahead"
+
+	// SpotClaimLimitTemplate - Too many claims for later dates template
+	SpotClaimLimitTemplate = "You already have %d spots claimed for later days, release one with `/spot release <spot-id> <date>` to claim another"
+
+	// SpotAlreadyClaimedTemplate - Spot already claimed template
+	SpotAlreadyClaimedTemplate = "The spot %s has already been claimed by %s"
+
+	// SpotReleasedTemplate - Spot released template
+	SpotReleasedTemplate = "You have released spot %s, it is open again for today"
+
+	// SpotReleasedForTemplate - Spot released for a later date template
+	SpotReleasedForTemplate = "You have released spot %s, it is open again for %s"
+
+	// SpotReleaseDateErrorTemplate - Release for a later date error template
+	SpotReleaseDateErrorTemplate = "Unable to release spot %s. You have not claimed it for %s."
+
+	// SpotReleaseErrorTemplate - Release error template
+	SpotReleaseErrorTemplate = "Unable to release spot %s. You have not claimed it today."
+
 	// SpotRegisteredTemplate - Spot registered template
 	SpotRegisteredTemplate = "You have registered spot %s. Thank you for sharing"
 
+	// SpotUnknownTemplate - The spot is not in the catalog
+	SpotUnknownTemplate = "There is no spot %s, check the spot number and try again"
+
+	// SpotNotHolderTemplate - Someone else holds the spot
+	SpotNotHolderTemplate = "The spot %s is held by someone else, only its holder can register it"
+
 	// SpotDupeRegistrationErrorTemplate - Spot registration error
 	SpotDupeRegistrationErrorTemplate = "The Spot %v has already been register by %v"
 
 	// SpotDateFormatRegistrationErrorTemplate - Spot registration date format error
-	SpotDateFormatRegistrationErrorTemplate = "The date provided: %s is invalid, please use format YYYY-MM-DD"
+	SpotDateFormatRegistrationErrorTemplate = "The date provided: %s is invalid, please use format YYYY-MM-DD or a date like tomorrow, fri, next tue, +3d or 11/14. Dates can also be a range like tomorrow..fri or a comma separated list of them"
+
+	// SpotRangeRegisteredTemplate - Spot registered for many dates template
+	SpotRangeRegisteredTemplate = "You have registered spot %s for %s. Thank you for sharing"
+
+	// SpotRangeDupeTemplate - Some dates of a range were already registered
+	SpotRangeDupeTemplate = "The spot %s was already registered for %s"
+
+	// SpotRangeRegistrationErrorTemplate - Registering a range failed
+	SpotRangeRegistrationErrorTemplate = "Unable to register spot %s, nothing was registered"
+
+	// NoDatesToRegister - A date spec with only weekends and the weekdays option
+	NoDatesToRegister = "There are no weekdays in the dates provided, nothing was registered"
+
+	// SkipWeekendsOption - the register option to leave Saturdays and Sundays out of a range
+	SkipWeekendsOption = "weekdays"
+
+	// SpotRecurringRegisteredTemplate - Spot registered every week template
+	SpotRecurringRegisteredTemplate = "You have registered spot %s every %s until %s. Thank you for sharing"
+
+	// SpotRecurringDupeTemplate - Someone else already has a recurrence for the spot
+	SpotRecurringDupeTemplate = "The Spot %v has already been registered to recur by %v"
+
+	// SpotWeekdaysErrorTemplate - Bad days in a recurring registration
+	SpotWeekdaysErrorTemplate = "The days provided: %s are invalid, please use day names like mon,wed,fri"
+
+	// UntilFurtherNotice - the end of a recurrence that never ends
+	UntilFurtherNotice = "further notice"
+
+	// EveryOption - the register option for recurring registrations
+	EveryOption = "every"
+
+	// shortTimeFormat - how times like the end of a hold or the time of a draw are shown
+	shortTimeFormat = "2006-01-02 15:04"
+
+	// UntilOption - the register option to end a recurring registration
+	UntilOption = "until"
+
+	// MyRecurrencesTemplate - The header for the list of a user's recurrences
+	MyRecurrencesTemplate = "Your recurring spots:\n%s"
+
+	// MyRecurrenceTemplate - A recurrence in a list
+	MyRecurrenceTemplate = "- %s every %s until %s"
+
+	// NoRecurrences - The user has no recurrences
+	NoRecurrences = "You have no recurring spots."
+
+	// SpotRecurrenceCancelledTemplate - Recurrence cancelled template
+	SpotRecurrenceCancelledTemplate = "Spot %s will no longer recur"
+
+	// SpotRecurrenceCancelErrorTemplate - Recurrence cancel error template
+	SpotRecurrenceCancelErrorTemplate = "Unable to cancel recurring spot %s. It does not recur or you did not register it."
 
 	// SpotPastDateRegistrationErrorTemplate - Spot past date error
 	SpotPastDateRegistrationErrorTemplate = "The date provided: %s, is in the past,"
@@ -77,10 +200,188 @@ With Slash-Spot you can find, reserve and register parking spots.
 
 	// SpotDropRegErrorTemplate - Error respose template for drop registration error
 	SpotDropRegErrorTemplate = "Unable to drop registration %v. The registration has been claimed or you did not create this registration."
+
+	// MyRegistrationsTemplate - The header for the list of a user's registrations
+	MyRegistrationsTemplate = "Your registered spots:\n%s"
+
+	// MyOpenRegistrationTemplate - A registration no one has claimed
+	MyOpenRegistrationTemplate = "- %s on %s is open"
+
+	// MySharedRegistrationTemplate - A registration users have claimed parts of the day of
+	MySharedRegistrationTemplate = "- %s on %s is shared by %s"
+
+	// MyClaimedRegistrationTemplate - A registration someone has claimed
+	MyClaimedRegistrationTemplate = "- %s on %s was claimed by %s"
+
+	// NoRegistrations - The user has no registrations
+	NoRegistrations = "You have no registered spots."
+
+	// NotAdmin - The user is not an admin
+	NotAdmin = "Only spot admins can do that."
+
+	// AllRegistrationsTemplate - The header for the list of every registration
+	AllRegistrationsTemplate = "All registered spots:\n%s"
+
+	// AdminOpenRegistrationTemplate - A registration no one has claimed in the list of every registration
+	AdminOpenRegistrationTemplate = "- %s on %s registered by %s is open"
+
+	// AdminSharedRegistrationTemplate - A registration users have claimed parts of the day of, for admins
+	AdminSharedRegistrationTemplate = "- %s on %s registered by %s is shared by %s"
+
+	// AdminClaimedRegistrationTemplate - A registration someone has claimed in the list of every registration
+	AdminClaimedRegistrationTemplate = "- %s on %s registered by %s was claimed by %s"
+
+	// NoAllRegistrations - There are no registrations
+	NoAllRegistrations = "There are no registered spots."
+
+	// AdminDropTemplate - Admin dropped a registration
+	AdminDropTemplate = "Registration for spot %s on %s by %s has been dropped"
+
+	// AdminDropErrorTemplate - Admin drop error
+	AdminDropErrorTemplate = "Unable to drop registration %s on %s. It is not registered."
+
+	// AdminReleaseTemplate - Admin released a claim
+	AdminReleaseTemplate = "Spot %s has been released, it is open again for today"
+
+	// AdminReleaseErrorTemplate - Admin release error
+	AdminReleaseErrorTemplate = "Unable to release spot %s. It has not been claimed today."
+
+	// AdminAssignTemplate - Admin assigned a spot
+	AdminAssignTemplate = "Spot %s is now held by %s"
+
+	// AdminUnassignTemplate - Admin left a spot without a holder
+	AdminUnassignTemplate = "Spot %s is no longer held by anyone"
+
+	// AdminPurgeTemplate - Admin purged past data
+	AdminPurgeTemplate = "Purged %d past registrations"
+
+	// UnknownUserTemplate - The user has never used spot
+	UnknownUserTemplate = "I don't know who %s is, mention them like @name or have them use /spot first"
+
+	// AdminErrorTemplate - Any other admin error
+	AdminErrorTemplate = "Unable to %s, check the logs for details"
 )
 
+// Handler - serves the slack endpoints for spot
+type Handler struct {
+	spots         *spot.Service
+	teamTimezones map[string]*time.Location
+	userTimezones UserTimezones
+	admins        []Admins
+	views         Views
+	responses     *ResponsePool
+	inChannel     map[string]bool
+	// locations - where spots are, the first is where users who have not picked a location are
+	locations      []string
+	locationAdmins map[string][]Admins
+	// announce - the command whose response can be shared with the channel, set on the per request copy of the
+	// Handler when a spot was claimed or registered
+	announce string
+}
+
+// Option - configures a Handler
+type Option func(h *Handler)
+
+// New - A Handler constructor
+func New(spots *spot.Service, options ...Option) *Handler {
+	h := &Handler{
+		spots: spots,
+	}
+	for _, option := range options {
+		option(h)
+	}
+	return h
+}
+
+// WithTeamTimezones - work in a timezone per slack workspace, keyed by team ID. Other workspaces use the spot service's location.
+func WithTeamTimezones(locs map[string]*time.Location) Option {
+	return func(h *Handler) {
+		h.teamTimezones = locs
+	}
+}
+
+// WithUserTimezones - work in each user's own timezone when it can be found
+func WithUserTimezones(timezones UserTimezones) Option {
+	return func(h *Handler) {
+		h.userTimezones = timezones
+	}
+}
+
+// WithInChannel - share the response of successful claims and registrations with the whole channel for the commands,
+// claim and reg. Other responses are only seen by the user who sent the command.
+func WithInChannel(commands ...string) Option {
+	return func(h *Handler) {
+		h.inChannel = make(map[string]bool, len(commands))
+		for _, command := range commands {
+			h.inChannel[command] = true
+		}
+	}
+}
+
+// WithAdmins - let users the admins agree on use the admin commands and register any spot
+func WithAdmins(admins ...Admins) Option {
+	return func(h *Handler) {
+		h.admins = append(h.admins, admins...)
+	}
+}
+
+// forUser - a copy of the handler whose spot service works in the user's timezone, their workspace's timezone or the
+// default, in that order, at the user's default location. If the user is an admin the spot service knows it. The
+// user is remembered for display.
+func (h *Handler) forUser(user data.User) *Handler {
+	u := *h
+	if loc, ok := h.teamTimezones[user.TeamID]; ok {
+		u.spots = h.spots.In(loc)
+	}
+	if h.userTimezones != nil && user.ID != "" {
+		loc, err := h.userTimezones.Location(user.ID)
+		if err != nil {
+			log.Printf("Unable to find the timezone of %v: %v", user.ID, err)
+		} else {
+			u.spots = h.spots.In(loc)
+		}
+	}
+	if err := u.spots.RememberUser(user); err != nil {
+		log.Printf("Unable to remember user %v: %v", user.ID, err)
+	}
+	return u.at(user.ID, u.defaultLocation(user.ID))
+}
+
+// commandUser - the user who sent a slash command
+func commandUser(cmd *slack.SlashCommand) data.User {
+	return data.User{ID: cmd.UserID, Name: cmd.UserName, TeamID: cmd.TeamID}
+}
+
+// isAdmin - returns true if any of the handler's admins agree the user is an admin
+func (h *Handler) isAdmin(userID string) bool {
+	if userID == "" {
+		return false
+	}
+	for _, admins := range h.admins {
+		ok, err := admins.IsAdmin(userID)
+		if err != nil {
+			log.Printf("Unable to check if %v is an admin: %v", userID, err)
+			continue
+		}
+		if ok {
+			return true
+		}
+	}
+	return false
+}
+
+// userName - the name to show for the user with the ID
+func (h *Handler) userName(id string) string {
+	return h.spots.UserName(id)
+}
+
+// now - the current time by the handler's spot service
+func (h *Handler) now() time.Time {
+	return h.spots.Now()
+}
+
 // SlashCommandHandler - the root handler for spot.  Capture the incoming command from slack and delegates it off to other internal handlers.
-func SlashCommandHandler(w http.ResponseWriter, r *http.Request) {
+func (h *Handler) SlashCommandHandler(w http.ResponseWriter, r *http.Request) {
 	verifier, err := slack.NewSecretsVerifier(r.Header, os.Getenv("SPOT_SLACK_SIGNING_SECRET"))
 	if err != nil {
 		log.Print("ERROR - slashspot may not be configured correctly, check you set up: ", err)
@@ -104,16 +405,47 @@ func SlashCommandHandler(w http.ResponseWriter, r *http.Request) {
 
 	switch s.Command {
 	case "/spot":
-		spotCommandHandler(&s, w)
+		if h.responses != nil && s.ResponseURL != "" {
+			if h.responses.Submit(func() { h.deferredSpotCommand(&s) }) {
+				w.WriteHeader(http.StatusOK)
+				return
+			}
+			log.Print("Too many commands waiting, answering right away")
+		}
+		h.spotCommandHandler(&s, w)
 	default:
 		w.WriteHeader(http.StatusBadRequest)
 		return
 	}
 }
 
-func spotCommandHandler(cmd *slack.SlashCommand, w http.ResponseWriter) {
+func (h *Handler) spotCommandHandler(cmd *slack.SlashCommand, w http.ResponseWriter) {
+	if msg, ok := h.spotCommand(cmd); ok {
+		writeMessage(w, msg)
+	}
+}
+
+// deferredSpotCommand - run the spot command after slack has been answered and post the response to the command's
+// response URL
+func (h *Handler) deferredSpotCommand(cmd *slack.SlashCommand) {
+	msg, ok := h.spotCommand(cmd)
+	if !ok {
+		return
+	}
+	if err := postMessageWithRetries(cmd.ResponseURL, msg); err != nil {
+		log.Print("Error posting the response to a command ", err)
+	}
+}
+
+// spotCommand - run the spot command, returns false when there is nothing to say
+func (h *Handler) spotCommand(cmd *slack.SlashCommand) (message, bool) {
+	h = h.forUser(commandUser(cmd))
 	params := strings.Split(cmd.Text, " ")
 	log.Printf("Spot command received %v", params)
+	if location, rest, ok := h.splitLocation(params[1:]); ok {
+		h = h.at(cmd.UserID, location)
+		params = append(params[:1], rest...)
+	}
 	var response string
 	switch action := params[0]; action {
 	case "", " ":
@@ -121,19 +453,44 @@ func spotCommandHandler(cmd *slack.SlashCommand, w http.ResponseWriter) {
 	case "help":
 		response = handleHelp()
 	case "find", "open":
-		response = handleFind(params)
+		msg := h.handleFind(params)
+		msg.ResponseType = ResponseEphemeral
+		return msg, true
 	case "reg", "register", "set":
-		response = handleRegister(cmd, params)
+		response = h.handleRegister(cmd, params)
 	case "claim", "take", "reserve":
-		response = handleClaim(cmd, params)
+		response = h.handleClaim(cmd, params)
+	case "release":
+		response = h.handleRelease(cmd, params)
 	case "drop":
-		response = handleDrop(cmd, params)
+		response = h.handleDrop(cmd, params)
+	case "mine":
+		response = h.handleMine(cmd)
+	case "recurring":
+		response = h.handleRecurring(cmd, params)
+	case "want":
+		response = h.handleWant(cmd, params)
+	case "enter":
+		response = h.handleEnter(cmd, params)
+	case "location":
+		response = h.handleLocation(cmd, params)
+	case "stats":
+		response = h.handleStats(cmd, params)
+	case "admin":
+		response = h.handleAdmin(cmd, params)
 	case "version":
 		response = handleVersion()
 	default:
 		response = handleUnknown(action)
 	}
-	w.Write([]byte(response))
+	if response == "" {
+		return message{}, false
+	}
+	msg := message{ResponseType: ResponseEphemeral, Text: response}
+	if h.inChannel[h.announce] {
+		msg.ResponseType = ResponseInChannel
+	}
+	return msg, true
 }
 
 func handleBlank() string {
@@ -144,73 +501,453 @@ func handleVersion() string {
 	return fmt.Sprintf(VersionText, config.Version, config.GitHash, config.BuildTime)
 }
 
-func handleFind(params []string) string {
-	spots, err := spot.Find()
+func (h *Handler) handleFind(params []string) message {
+	var filters []string
+	for _, p := range params[1:] {
+		if p != "" {
+			filters = append(filters, p)
+		}
+	}
+	return h.findMessage(filters)
+}
+
+// findMessage - the spots open today that match the filters, each with a button to claim it
+func (h *Handler) findMessage(params []string) message {
+	window, filters, err := splitWindow(params)
 	if err != nil {
-		return NoSpotsAvailable
+		return message{Text: fmt.Sprintf(SpotWindowFormatErrorTemplate, strings.Join(params, " "))}
 	}
-	var spotIds []string
+	spots, err := h.spots.FindWindow(window, filters...)
+	if err != nil && len(params) > 0 {
+		return message{Text: fmt.Sprintf(NoMatchingSpotsTemplate, strings.Join(params, " "))}
+	}
+	if err != nil {
+		return message{Text: NoSpotsAvailable}
+	}
+	var open []data.Spot
 	for _, s := range spots {
+		open = append(open, s)
+	}
+	sort.Slice(open, func(i, j int) bool {
+		return open[i].ID < open[j].ID
+	})
+	var spotIds []string
+	for _, s := range open {
 		spotIds = append(spotIds, s.ID)
 	}
-	sort.Strings(spotIds)
-	return fmt.Sprintf(OpenSpotsTemplate, strings.Join(spotIds, ","))
+	return message{
+		Text:   fmt.Sprintf(OpenSpotsTemplate, strings.Join(spotIds, ",")),
+		Blocks: h.openSpotBlocks(open, h.withLocation(params)),
+	}
 }
 
-func handleRegister(cmd *slack.SlashCommand, params []string) string {
+func (h *Handler) handleRegister(cmd *slack.SlashCommand, params []string) string {
 	var newSpot data.Spot
 	var err error
 	if len(params) <= 1 {
-		return IDKBlank
+		return h.openRegisterModal(cmd)
 	}
-	if len(params) == 2 {
-		newSpot, err = spot.Register(params[1], cmd.UserName, time.Now())
+	if len(params) > 2 && strings.ToLower(params[2]) == EveryOption {
+		return h.handleRegisterRecurring(cmd, params)
+	}
+	window, spec, err := splitWindow(params[2:])
+	if err != nil {
+		return fmt.Sprintf(SpotWindowFormatErrorTemplate, strings.Join(params[2:], " "))
+	}
+	if len(spec) == 0 {
+		newSpot, err = h.spots.RegisterWindow(params[1], cmd.UserID, h.now(), window)
+		if response, ok := refusedRegistration(params[1], err); ok {
+			return response
+		}
 		if err != nil {
-			return fmt.Sprintf(SpotDupeRegistrationErrorTemplate, params[1], newSpot.RegisteredBy)
+			return fmt.Sprintf(SpotDupeRegistrationErrorTemplate, params[1], h.userName(newSpot.RegisteredBy))
 		}
 	}
-	if len(params) > 2 {
-		openDate, err := time.Parse(util.SpotDateFormat, params[2])
+	if len(spec) > 0 {
+		skipWeekends := len(spec) > 1 && strings.ToLower(spec[len(spec)-1]) == SkipWeekendsOption
+		if skipWeekends {
+			spec = spec[:len(spec)-1]
+		}
+		openDates, err := util.ParseDates(strings.Join(spec, " "), h.now(), skipWeekends)
 		if err != nil {
-			return fmt.Sprintf(SpotDateFormatRegistrationErrorTemplate, params[2])
+			return fmt.Sprintf(SpotDateFormatRegistrationErrorTemplate, strings.Join(spec, " "))
+		}
+		for _, openDate := range openDates {
+			if util.BeforeNow(openDate.Format(util.SpotDateFormat), h.now()) {
+				return fmt.Sprintf(SpotPastDateRegistrationErrorTemplate, openDate.Format(util.SpotDateFormat))
+			}
+		}
+		if len(openDates) != 1 {
+			return h.handleRegisterRange(cmd, params[1], openDates, window)
 		}
-		if util.BeforeNow(params[2]) {
-			return fmt.Sprintf(SpotPastDateRegistrationErrorTemplate, params[2])
+		newSpot, err = h.spots.RegisterWindow(params[1], cmd.UserID, openDates[0], window)
+		if response, ok := refusedRegistration(params[1], err); ok {
+			return response
 		}
-		newSpot, err = spot.Register(params[1], cmd.UserName, openDate)
 		if err != nil {
-			return fmt.Sprintf(SpotDupeRegistrationErrorTemplate, params[1], newSpot.RegisteredBy)
+			return fmt.Sprintf(SpotDupeRegistrationErrorTemplate, params[1], h.userName(newSpot.RegisteredBy))
 		}
 	}
-	return fmt.Sprintf(SpotRegisteredTemplate, newSpot.ID)
+	h.announce = "reg"
+	return fmt.Sprintf(SpotRegisteredTemplate, withWindow(newSpot.ID, window))
 }
 
-func handleClaim(cmd *slack.SlashCommand, params []string) string {
+func (h *Handler) handleRegisterRecurring(cmd *slack.SlashCommand, params []string) string {
+	if len(params) < 4 || (len(params) > 4 && (len(params) < 6 || strings.ToLower(params[4]) != UntilOption)) {
+		return IDKBlank
+	}
+	weekdays, err := util.ParseWeekdays(params[3])
+	if err != nil {
+		return fmt.Sprintf(SpotWeekdaysErrorTemplate, params[3])
+	}
+	var until time.Time
+	if len(params) >= 6 {
+		spec := strings.Join(params[5:], " ")
+		if until, err = util.ParseDate(spec, h.now()); err != nil {
+			return fmt.Sprintf(SpotDateFormatRegistrationErrorTemplate, spec)
+		}
+		if util.BeforeNow(until.Format(util.SpotDateFormat), h.now()) {
+			return fmt.Sprintf(SpotPastDateRegistrationErrorTemplate, until.Format(util.SpotDateFormat))
+		}
+	}
+	rec, err := h.spots.RegisterRecurring(params[1], cmd.UserID, weekdays, until)
+	if response, ok := refusedRegistration(params[1], err); ok {
+		return response
+	}
+	if err != nil {
+		return fmt.Sprintf(SpotRecurringDupeTemplate, params[1], h.userName(rec.RegisteredBy))
+	}
+	h.announce = "reg"
+	return fmt.Sprintf(SpotRecurringRegisteredTemplate, rec.ID, util.FormatWeekdays(rec.Weekdays), recurrenceUntil(rec))
+}
+
+func recurrenceUntil(rec data.Recurrence) string {
+	if rec.Until == "" {
+		return UntilFurtherNotice
+	}
+	return rec.Until
+}
+
+func (h *Handler) handleRegisterRange(cmd *slack.SlashCommand, id string, openDates []time.Time, window data.Window) string {
+	if len(openDates) == 0 {
+		return NoDatesToRegister
+	}
+	result, err := h.spots.RegisterRangeWindow(id, cmd.UserID, openDates, window)
+	if response, ok := refusedRegistration(id, err); ok {
+		return response
+	}
+	if err != nil {
+		return fmt.Sprintf(SpotRangeRegistrationErrorTemplate, id)
+	}
+	var lines []string
+	if len(result.Registered) > 0 {
+		h.announce = "reg"
+		lines = append(lines, fmt.Sprintf(SpotRangeRegisteredTemplate, id, withWindow(joinOpenDates(result.Registered), window)))
+	}
+	if len(result.AlreadyRegistered) > 0 {
+		lines = append(lines, fmt.Sprintf(SpotRangeDupeTemplate, id, joinOpenDates(result.AlreadyRegistered)))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func joinOpenDates(spots []data.Spot) string {
+	var dates []string
+	for _, s := range spots {
+		dates = append(dates, s.OpenDate)
+	}
+	return strings.Join(dates, ", ")
+}
+
+// refusedRegistration - the response when the spot is not one the user can register
+func refusedRegistration(id string, err error) (string, bool) {
+	switch err {
+	case spot.ErrUnknownSpot:
+		return fmt.Sprintf(SpotUnknownTemplate, id), true
+	case spot.ErrNotHolder:
+		return fmt.Sprintf(SpotNotHolderTemplate, id), true
+	}
+	return "", false
+}
+
+// handleClaim - claim <spot-id> [date] [window]
+func (h *Handler) handleClaim(cmd *slack.SlashCommand, params []string) string {
 	if len(params) < 2 {
 		return IDKBlank
 	}
-	spot, err := spot.Claim(params[1], cmd.UserName)
+	window, spec, err := splitWindow(params[2:])
+	if err != nil {
+		return fmt.Sprintf(SpotWindowFormatErrorTemplate, strings.Join(params[2:], " "))
+	}
+	date := h.now()
+	if len(spec) > 0 {
+		if date, err = util.ParseDate(strings.Join(spec, " "), h.now()); err != nil {
+			return fmt.Sprintf(SpotDateFormatRegistrationErrorTemplate, strings.Join(spec, " "))
+		}
+		if day := date.Format(util.SpotDateFormat); util.BeforeNow(day, h.now()) {
+			return fmt.Sprintf(SpotPastDateRegistrationErrorTemplate, day)
+		}
+	}
+	return h.claimWindow(params[1], cmd.UserID, date, window)
+}
+
+// claim - claim a spot for the user today and say how it went
+func (h *Handler) claim(id string, userID string) string {
+	return h.claimWindow(id, userID, h.now(), data.Window{})
+}
+
+// claimWindow - claim a spot, or part of its day, for the user on the date and say how it went
+func (h *Handler) claimWindow(id string, userID string, date time.Time, window data.Window) string {
+	day := date.Format(util.SpotDateFormat)
+	today := day == h.now().Format(util.SpotDateFormat)
+	claimed, err := h.spots.ClaimWindow(id, userID, date, window)
+	if err == spot.ErrHeld {
+		return fmt.Sprintf(SpotHeldTemplate, id, h.userName(claimed.HeldFor), heldUntil(claimed))
+	}
+	if err == spot.ErrDrawPending {
+		return fmt.Sprintf(SpotDrawPendingTemplate, day, h.spots.Cutoff(date).Format(shortTimeFormat))
+	}
+	if err == spot.ErrBeyondHorizon {
+		return fmt.Sprintf(SpotBeyondHorizonTemplate, h.spots.ClaimHorizon())
+	}
+	if err == spot.ErrClaimLimit {
+		return fmt.Sprintf(SpotClaimLimitTemplate, h.spots.ClaimLimit())
+	}
+	if quotaErr, ok := err.(spot.QuotaError); ok {
+		return quotaMessage(quotaErr)
+	}
+	if err == spot.ErrOutsideWindow {
+		return fmt.Sprintf(SpotOutsideWindowTemplate, id, claimed.Window, day)
+	}
+	if err == spot.ErrWindowTaken && claimed.IsOpen() {
+		return fmt.Sprintf(SpotWindowTakenTemplate, id, day, joinWindows(claimed.Free()))
+	}
+	if err == spot.ErrWindowTaken {
+		return fmt.Sprintf(SpotSharedTemplate, id, day, h.sharedBy(claimed))
+	}
+	if err != nil && claimed.IsClaimed() {
+		return fmt.Sprintf(SpotAlreadyClaimedTemplate, id, h.userName(claimed.ClaimedBy))
+	}
+	if err != nil && today {
+		return fmt.Sprintf(SpotClaimErrorTemplate, id)
+	}
 	if err != nil {
-		return fmt.Sprintf(SpotClaimErrorTemplate, params[1])
+		return fmt.Sprintf(SpotClaimDateErrorTemplate, id, day)
 	}
-	return fmt.Sprintf(SpotClaimedTemplate, spot.ID)
+	h.announce = "claim"
+	if today {
+		return fmt.Sprintf(SpotClaimedTemplate, withWindow(claimed.ID, window))
+	}
+	return fmt.Sprintf(SpotClaimedForTemplate, withWindow(claimed.ID, window), claimed.OpenDate)
 }
 
-func handleDrop(cmd *slack.SlashCommand, params []string) string {
+// handleRelease - release <spot-id> [date]
+func (h *Handler) handleRelease(cmd *slack.SlashCommand, params []string) string {
+	if len(params) < 2 {
+		return IDKBlank
+	}
+	if len(params) == 2 {
+		spot, err := h.spots.Release(params[1], cmd.UserID)
+		if err != nil {
+			return fmt.Sprintf(SpotReleaseErrorTemplate, params[1])
+		}
+		return fmt.Sprintf(SpotReleasedTemplate, spot.ID)
+	}
+	spec := strings.Join(params[2:], " ")
+	date, err := util.ParseDate(spec, h.now())
+	if err != nil {
+		return fmt.Sprintf(SpotDateFormatRegistrationErrorTemplate, spec)
+	}
+	day := date.Format(util.SpotDateFormat)
+	if util.BeforeNow(day, h.now()) {
+		return fmt.Sprintf(SpotPastDateRegistrationErrorTemplate, day)
+	}
+	spot, err := h.spots.ReleaseOn(params[1], cmd.UserID, date)
+	if err != nil {
+		return fmt.Sprintf(SpotReleaseDateErrorTemplate, params[1], day)
+	}
+	return fmt.Sprintf(SpotReleasedForTemplate, spot.ID, day)
+}
+
+func (h *Handler) handleDrop(cmd *slack.SlashCommand, params []string) string {
 	if len(params) < 2 {
 		return IDKBlank
 	}
 	if strings.ToLower(params[1]) == "all" {
-		spot.DropAllRegistrations(cmd.UserName)
+		h.spots.DropAllRegistrations(cmd.UserID)
 		return fmt.Sprintf(SpotDropAllRegTemplate, cmd.UserName)
 	}
-	err := spot.DropRegistration(params[1], cmd.UserName)
+	err := h.spots.DropRegistration(params[1], cmd.UserID)
 	if err != nil {
 		return fmt.Sprintf(SpotDropRegErrorTemplate, params[1])
 	}
 	return fmt.Sprintf(SpotDropRegTemplate, params[1])
 }
 
+func (h *Handler) handleMine(cmd *slack.SlashCommand) string {
+	regs, err := h.spots.Registrations(cmd.UserID)
+	if err != nil || len(regs) == 0 {
+		return NoRegistrations
+	}
+	var lines []string
+	for _, reg := range regs {
+		if reg.IsClaimed() {
+			lines = append(lines, fmt.Sprintf(MyClaimedRegistrationTemplate, withWindow(spotName(reg.ID, reg.Location), reg.Window), reg.OpenDate, h.userName(reg.ClaimedBy)))
+			continue
+		}
+		if reg.IsShared() {
+			lines = append(lines, fmt.Sprintf(MySharedRegistrationTemplate, withWindow(spotName(reg.ID, reg.Location), reg.Window), reg.OpenDate, h.sharedBy(reg)))
+			continue
+		}
+		lines = append(lines, fmt.Sprintf(MyOpenRegistrationTemplate, withWindow(spotName(reg.ID, reg.Location), reg.Window), reg.OpenDate))
+	}
+	return fmt.Sprintf(MyRegistrationsTemplate, strings.Join(lines, "\n"))
+}
+
+func (h *Handler) handleRecurring(cmd *slack.SlashCommand, params []string) string {
+	if len(params) > 1 {
+		if len(params) != 3 || strings.ToLower(params[1]) != "cancel" {
+			return IDKBlank
+		}
+		if err := h.spots.CancelRecurrence(params[2], cmd.UserID); err != nil {
+			return fmt.Sprintf(SpotRecurrenceCancelErrorTemplate, params[2])
+		}
+		return fmt.Sprintf(SpotRecurrenceCancelledTemplate, params[2])
+	}
+	recs, err := h.spots.Recurrences(cmd.UserID)
+	if err != nil || len(recs) == 0 {
+		return NoRecurrences
+	}
+	var lines []string
+	for _, rec := range recs {
+		lines = append(lines, fmt.Sprintf(MyRecurrenceTemplate, spotName(rec.ID, rec.Location), util.FormatWeekdays(rec.Weekdays), recurrenceUntil(rec)))
+	}
+	return fmt.Sprintf(MyRecurrencesTemplate, strings.Join(lines, "\n"))
+}
+
+func (h *Handler) handleAdmin(cmd *slack.SlashCommand, params []string) string {
+	if !h.spots.IsAdmin(cmd.UserID) {
+		return NotAdmin
+	}
+	if len(params) < 2 {
+		return AdminHelpText
+	}
+	switch action := strings.ToLower(params[1]); action {
+	case "list":
+		return h.handleAdminList(cmd)
+	case "drop":
+		return h.handleAdminDrop(cmd, params)
+	case "release":
+		return h.handleAdminRelease(cmd, params)
+	case "assign":
+		return h.handleAdminAssign(cmd, params)
+	case "draw":
+		return h.handleAdminDraw(params)
+	case "export":
+		return h.handleAdminExport(params)
+	case "catalog":
+		return h.handleAdminCatalog(cmd, params)
+	case "purge":
+		purged, err := h.spots.Purge(cmd.UserID)
+		if err != nil {
+			return fmt.Sprintf(AdminErrorTemplate, action)
+		}
+		return fmt.Sprintf(AdminPurgeTemplate, purged)
+	default:
+		return AdminHelpText
+	}
+}
+
+func (h *Handler) handleAdminList(cmd *slack.SlashCommand) string {
+	regs, err := h.spots.AllRegistrations(cmd.UserID)
+	if err != nil {
+		return fmt.Sprintf(AdminErrorTemplate, "list")
+	}
+	if len(regs) == 0 {
+		return NoAllRegistrations
+	}
+	var lines []string
+	for _, reg := range regs {
+		if reg.IsClaimed() {
+			lines = append(lines, fmt.Sprintf(AdminClaimedRegistrationTemplate, withWindow(reg.ID, reg.Window), reg.OpenDate, h.userName(reg.RegisteredBy), h.userName(reg.ClaimedBy)))
+			continue
+		}
+		if reg.IsShared() {
+			lines = append(lines, fmt.Sprintf(AdminSharedRegistrationTemplate, withWindow(reg.ID, reg.Window), reg.OpenDate, h.userName(reg.RegisteredBy), h.sharedBy(reg)))
+			continue
+		}
+		lines = append(lines, fmt.Sprintf(AdminOpenRegistrationTemplate, withWindow(reg.ID, reg.Window), reg.OpenDate, h.userName(reg.RegisteredBy)))
+	}
+	return fmt.Sprintf(AllRegistrationsTemplate, strings.Join(lines, "\n"))
+}
+
+// handleAdminDrop - admin drop <spot-id> [date]
+func (h *Handler) handleAdminDrop(cmd *slack.SlashCommand, params []string) string {
+	if len(params) < 3 {
+		return IDKBlank
+	}
+	openDate := h.now()
+	if len(params) > 3 {
+		spec := strings.Join(params[3:], " ")
+		var err error
+		if openDate, err = util.ParseDate(spec, h.now()); err != nil {
+			return fmt.Sprintf(SpotDateFormatRegistrationErrorTemplate, spec)
+		}
+	}
+	dropped, err := h.spots.ForceDrop(params[2], openDate, cmd.UserID)
+	if err != nil {
+		return fmt.Sprintf(AdminDropErrorTemplate, params[2], openDate.Format(util.SpotDateFormat))
+	}
+	return fmt.Sprintf(AdminDropTemplate, dropped.ID, dropped.OpenDate, h.userName(dropped.RegisteredBy))
+}
+
+// handleAdminRelease - admin release <spot-id>
+func (h *Handler) handleAdminRelease(cmd *slack.SlashCommand, params []string) string {
+	if len(params) < 3 {
+		return IDKBlank
+	}
+	if _, err := h.spots.ForceRelease(params[2], cmd.UserID); err != nil {
+		return fmt.Sprintf(AdminReleaseErrorTemplate, params[2])
+	}
+	return fmt.Sprintf(AdminReleaseTemplate, params[2])
+}
+
+// handleAdminAssign - admin assign <spot-id> [user]
+func (h *Handler) handleAdminAssign(cmd *slack.SlashCommand, params []string) string {
+	if len(params) < 3 || len(params) > 4 {
+		return IDKBlank
+	}
+	var holder string
+	if len(params) == 4 {
+		var err error
+		if holder, err = h.userID(params[3]); err != nil {
+			return fmt.Sprintf(UnknownUserTemplate, params[3])
+		}
+	}
+	_, err := h.spots.AssignHolder(params[2], holder, cmd.UserID)
+	if err == spot.ErrUnknownSpot {
+		return fmt.Sprintf(SpotUnknownTemplate, params[2])
+	}
+	if err != nil {
+		return fmt.Sprintf(AdminErrorTemplate, "assign")
+	}
+	if holder == "" {
+		return fmt.Sprintf(AdminUnassignTemplate, params[2])
+	}
+	return fmt.Sprintf(AdminAssignTemplate, params[2], h.userName(holder))
+}
+
+// userID - the ID of a user given as a slack mention like <@U0123|jjrambo> or as a name spot has seen like @jjrambo
+func (h *Handler) userID(in string) (string, error) {
+	if strings.HasPrefix(in, "<@") && strings.HasSuffix(in, ">") {
+		return strings.SplitN(strings.TrimSuffix(strings.TrimPrefix(in, "<@"), ">"), "|", 2)[0], nil
+	}
+	user, err := h.spots.FindUser(strings.TrimPrefix(in, "@"))
+	if err != nil {
+		return "", err
+	}
+	return user.ID, nil
+}
+
 func handleHelp() string {
 	return HelpText
 }
@@ -1,10 +1,12 @@
 package handlers
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http/httptest"
 	"net/url"
-	"os"
+	"strings"
 	"testing"
 	"time"
 
@@ -28,8 +30,13 @@ func formValsHelper(in map[string]string) url.Values {
 	return values
 }
 
+// ephemeral - the JSON response only the user who sent a command sees
+func ephemeral(text string) string {
+	b, _ := json.Marshal(message{ResponseType: ResponseEphemeral, Text: text})
+	return string(b)
+}
+
 func Test_spotCommandHandler(t *testing.T) {
-	defer cleanup()
 	type args struct {
 		cmd *slack.SlashCommand
 		rr  *httptest.ResponseRecorder
@@ -48,7 +55,7 @@ func Test_spotCommandHandler(t *testing.T) {
 				},
 				rr: httptest.NewRecorder(),
 			},
-			expectedResponse: IDKBlank,
+			expectedResponse: ephemeral(IDKBlank),
 		},
 		{
 			name: "Test help command",
@@ -59,7 +66,7 @@ func Test_spotCommandHandler(t *testing.T) {
 				},
 				rr: httptest.NewRecorder(),
 			},
-			expectedResponse: HelpText,
+			expectedResponse: ephemeral(HelpText),
 		},
 		{
 			name: "Test find command",
@@ -70,7 +77,7 @@ func Test_spotCommandHandler(t *testing.T) {
 				},
 				rr: httptest.NewRecorder(),
 			},
-			expectedResponse: NoSpotsAvailable,
+			expectedResponse: ephemeral(NoSpotsAvailable),
 		},
 		{
 			name: "Test unknown command",
@@ -81,7 +88,7 @@ func Test_spotCommandHandler(t *testing.T) {
 				},
 				rr: httptest.NewRecorder(),
 			},
-			expectedResponse: fmt.Sprintf(IDKTemplate, "bacon"),
+			expectedResponse: ephemeral(fmt.Sprintf(IDKTemplate, "bacon")),
 		},
 		{
 			name: "Test reg command",
@@ -92,7 +99,7 @@ func Test_spotCommandHandler(t *testing.T) {
 				},
 				rr: httptest.NewRecorder(),
 			},
-			expectedResponse: fmt.Sprintf(SpotRegisteredTemplate, "12"),
+			expectedResponse: ephemeral(fmt.Sprintf(SpotRegisteredTemplate, "12")),
 		},
 		{
 			name: "Test claim command",
@@ -103,7 +110,7 @@ func Test_spotCommandHandler(t *testing.T) {
 				},
 				rr: httptest.NewRecorder(),
 			},
-			expectedResponse: fmt.Sprintf(SpotClaimedTemplate, "12"),
+			expectedResponse: ephemeral(fmt.Sprintf(SpotClaimedTemplate, "12")),
 		},
 		{
 			name: "Test reg command",
@@ -114,7 +121,7 @@ func Test_spotCommandHandler(t *testing.T) {
 				},
 				rr: httptest.NewRecorder(),
 			},
-			expectedResponse: fmt.Sprintf(SpotRegisteredTemplate, "13"),
+			expectedResponse: ephemeral(fmt.Sprintf(SpotRegisteredTemplate, "13")),
 		},
 		{
 			name: "Test drop command",
@@ -125,7 +132,7 @@ func Test_spotCommandHandler(t *testing.T) {
 				},
 				rr: httptest.NewRecorder(),
 			},
-			expectedResponse: fmt.Sprintf(SpotDropRegTemplate, "13"),
+			expectedResponse: ephemeral(fmt.Sprintf(SpotDropRegTemplate, "13")),
 		},
 		{
 			name: "Test reg command",
@@ -136,7 +143,7 @@ func Test_spotCommandHandler(t *testing.T) {
 				},
 				rr: httptest.NewRecorder(),
 			},
-			expectedResponse: fmt.Sprintf(SpotRegisteredTemplate, "14"),
+			expectedResponse: ephemeral(fmt.Sprintf(SpotRegisteredTemplate, "14")),
 		},
 		{
 			name: "Test drop command",
@@ -147,13 +154,13 @@ func Test_spotCommandHandler(t *testing.T) {
 				},
 				rr: httptest.NewRecorder(),
 			},
-			expectedResponse: fmt.Sprintf(SpotDropAllRegTemplate, "scooby"),
+			expectedResponse: ephemeral(fmt.Sprintf(SpotDropAllRegTemplate, "scooby")),
 		},
 	}
+	h := newTestHandler()
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			data.Open()
-			spotCommandHandler(tt.args.cmd, tt.args.rr)
+			h.spotCommandHandler(tt.args.cmd, tt.args.rr)
 			if tt.args.rr.Code >= 300 {
 				t.Errorf("Spot call return a non 200 response: %v", tt.args.rr.Code)
 			}
@@ -162,55 +169,63 @@ func Test_spotCommandHandler(t *testing.T) {
 	}
 }
 
-func cleanup() {
-	os.Remove(data.FilePath())
+// testLocation - where the test handler works, a fixed zone so the tests do not depend on the host
+var testLocation = time.FixedZone("CST", -6*60*60)
+
+// testNow - the time the test handler's clock is stopped at, a Wednesday morning
+func testNow() time.Time {
+	return time.Date(2020, 1, 8, 9, 30, 0, 0, testLocation)
+}
+
+func newTestHandler(options ...Option) *Handler {
+	service := spot.NewService(data.NewMemoryStore()).In(testLocation).WithClock(util.FixedClock(testNow()))
+	return New(service, options...)
 }
 
 func testSpots() []data.Spot {
 	return []data.Spot{
 		{
 			ID:           "B0",
-			OpenDate:     time.Now().AddDate(0, 0, -1).Format(util.SpotDateFormat),
-			RegDate:      time.Now().Format(util.SpotDateFormat),
+			OpenDate:     testNow().AddDate(0, 0, -1).Format(util.SpotDateFormat),
+			RegDate:      testNow().Format(util.SpotDateFormat),
 			RegisteredBy: "Fred",
 		},
 		{
 			ID:           "B1",
-			OpenDate:     time.Now().Format(util.SpotDateFormat),
-			RegDate:      time.Now().Format(util.SpotDateFormat),
+			OpenDate:     testNow().Format(util.SpotDateFormat),
+			RegDate:      testNow().Format(util.SpotDateFormat),
 			RegisteredBy: "slackuser",
 		},
 		{
 			ID:           "B2",
-			OpenDate:     time.Now().Format(util.SpotDateFormat),
-			RegDate:      time.Now().Format(util.SpotDateFormat),
+			OpenDate:     testNow().Format(util.SpotDateFormat),
+			RegDate:      testNow().Format(util.SpotDateFormat),
 			RegisteredBy: "slackuser",
 		},
 		{
 			ID:           "B3",
-			OpenDate:     time.Now().AddDate(0, 0, 1).Format(util.SpotDateFormat),
-			RegDate:      time.Now().Format(util.SpotDateFormat),
+			OpenDate:     testNow().AddDate(0, 0, 1).Format(util.SpotDateFormat),
+			RegDate:      testNow().Format(util.SpotDateFormat),
 			RegisteredBy: "FredsMom",
 		},
 		{
 			ID:           "B4",
-			OpenDate:     time.Now().Format(util.SpotDateFormat),
-			RegDate:      time.Now().Format(util.SpotDateFormat),
+			OpenDate:     testNow().Format(util.SpotDateFormat),
+			RegDate:      testNow().Format(util.SpotDateFormat),
 			RegisteredBy: "BarneysMom",
 		},
 	}
 }
 
 // registers spots for test and ignores errors
-func registerSpotsForTest(spots []data.Spot) {
+func registerSpotsForTest(h *Handler, spots []data.Spot) {
 	for _, newSpot := range spots {
 		od, _ := time.Parse(util.SpotDateFormat, newSpot.OpenDate)
-		spot.Register(newSpot.ID, newSpot.RegisteredBy, od)
+		h.spots.Register(newSpot.ID, newSpot.RegisteredBy, od)
 	}
 }
 
 func Test_handleFind(t *testing.T) {
-	defer cleanup()
 	type args struct {
 		params []string
 		spots  []data.Spot
@@ -236,21 +251,40 @@ func Test_handleFind(t *testing.T) {
 			},
 			want: NoSpotsAvailable,
 		},
+		{
+			name: "Should find spots matching filters",
+			args: args{
+				params: []string{"find", "ev", "", "level:2"},
+				spots:  testSpots(),
+			},
+			want: fmt.Sprintf(OpenSpotsTemplate, "B4"),
+		},
+		{
+			name: "Should not find spots matching filters",
+			args: args{
+				params: []string{"find", "ada"},
+				spots:  testSpots(),
+			},
+			want: fmt.Sprintf(NoMatchingSpotsTemplate, "ada"),
+		},
 	}
 	for _, tt := range tests {
-		cleanup()
-		data.Open()
-		registerSpotsForTest(tt.args.spots)
+		h := newTestHandler()
+		registerSpotsForTest(h, tt.args.spots)
+		h.spots.ImportCatalog([]data.CatalogSpot{
+			{ID: "B1", Level: "1", Attributes: []string{"ev"}},
+			{ID: "B2", Level: "2"},
+			{ID: "B4", Level: "2", Attributes: []string{"ev"}},
+		})
 		t.Run(tt.name, func(t *testing.T) {
-			if got := handleFind(tt.args.params); got != tt.want {
-				t.Errorf("handleFind() = %v, want %v", got, tt.want)
+			if got := h.handleFind(tt.args.params); got.Text != tt.want {
+				t.Errorf("handleFind() = %v, want %v", got.Text, tt.want)
 			}
 		})
 	}
 }
 
 func Test_handleRegister(t *testing.T) {
-	defer cleanup()
 	type args struct {
 		params []string
 		cmd    *slack.SlashCommand
@@ -265,7 +299,7 @@ func Test_handleRegister(t *testing.T) {
 			args: args{
 				params: []string{"reg", "A1"},
 				cmd: &slack.SlashCommand{
-					UserName: "slackuser",
+					UserID: "slackuser",
 				},
 			},
 			want: fmt.Sprintf(SpotRegisteredTemplate, "A1"),
@@ -273,9 +307,9 @@ func Test_handleRegister(t *testing.T) {
 		{
 			name: "Should register a spot for one day in the future",
 			args: args{
-				params: []string{"reg", "A2", time.Now().AddDate(0, 0, 1).Format(util.SpotDateFormat)},
+				params: []string{"reg", "A2", testNow().AddDate(0, 0, 1).Format(util.SpotDateFormat)},
 				cmd: &slack.SlashCommand{
-					UserName: "slackuser",
+					UserID: "slackuser",
 				},
 			},
 			want: fmt.Sprintf(SpotRegisteredTemplate, "A2"),
@@ -283,27 +317,126 @@ func Test_handleRegister(t *testing.T) {
 		{
 			name: "Should not register a spot for day in the past",
 			args: args{
-				params: []string{"reg", "A2", time.Now().AddDate(0, 0, -1).Format(util.SpotDateFormat)},
+				params: []string{"reg", "A2", testNow().AddDate(0, 0, -1).Format(util.SpotDateFormat)},
+				cmd: &slack.SlashCommand{
+					UserID: "slackuser",
+				},
+			},
+			want: fmt.Sprintf(SpotPastDateRegistrationErrorTemplate, testNow().AddDate(0, 0, -1).Format(util.SpotDateFormat)),
+		},
+		{
+			name: "Should register a spot for a range of dates",
+			args: args{
+				params: []string{"reg", "A3", dateSpecForTest(1) + util.DateRangeSep + dateSpecForTest(2)},
+				cmd: &slack.SlashCommand{
+					UserID: "slackuser",
+				},
+			},
+			want: fmt.Sprintf(SpotRangeRegisteredTemplate, "A3", dateSpecForTest(1)+", "+dateSpecForTest(2)),
+		},
+		{
+			name: "Should register a spot for a list of dates",
+			args: args{
+				params: []string{"reg", "A3", dateSpecForTest(3) + util.DateListSep + dateSpecForTest(1)},
 				cmd: &slack.SlashCommand{
-					UserName: "slackuser",
+					UserID: "slackuser",
 				},
 			},
-			want: fmt.Sprintf(SpotPastDateRegistrationErrorTemplate, time.Now().AddDate(0, 0, -1).Format(util.SpotDateFormat)),
+			want: fmt.Sprintf(SpotRangeRegisteredTemplate, "A3", dateSpecForTest(1)+", "+dateSpecForTest(3)),
+		},
+		{
+			name: "Should not register a range reaching into the past",
+			args: args{
+				params: []string{"reg", "A3", dateSpecForTest(-1) + util.DateRangeSep + dateSpecForTest(1)},
+				cmd: &slack.SlashCommand{
+					UserID: "slackuser",
+				},
+			},
+			want: fmt.Sprintf(SpotPastDateRegistrationErrorTemplate, dateSpecForTest(-1)),
+		},
+		{
+			name: "Should register a spot for tomorrow",
+			args: args{
+				params: []string{"reg", "A4", "tomorrow"},
+				cmd: &slack.SlashCommand{
+					UserID: "slackuser",
+				},
+			},
+			want: fmt.Sprintf(SpotRegisteredTemplate, "A4"),
+		},
+		{
+			name: "Should register a spot for a range of natural dates",
+			args: args{
+				params: []string{"reg", "A4", "today..+2d"},
+				cmd: &slack.SlashCommand{
+					UserID: "slackuser",
+				},
+			},
+			want: fmt.Sprintf(SpotRangeRegisteredTemplate, "A4", dateSpecForTest(0)+", "+dateSpecForTest(1)+", "+dateSpecForTest(2)),
+		},
+		{
+			name: "Should register a spot for next weekday",
+			args: args{
+				params: []string{"reg", "A4", "next", strings.ToLower(testNow().Weekday().String())},
+				cmd: &slack.SlashCommand{
+					UserID: "slackuser",
+				},
+			},
+			want: fmt.Sprintf(SpotRegisteredTemplate, "A4"),
+		},
+		{
+			name: "Should not register a bad date",
+			args: args{
+				params: []string{"reg", "A3", "someday"},
+				cmd: &slack.SlashCommand{
+					UserID: "slackuser",
+				},
+			},
+			want: fmt.Sprintf(SpotDateFormatRegistrationErrorTemplate, "someday"),
 		},
 	}
 	for _, tt := range tests {
-		cleanup()
-		data.Open()
+		h := newTestHandler()
 		t.Run(tt.name, func(t *testing.T) {
-			if got := handleRegister(tt.args.cmd, tt.args.params); got != tt.want {
+			if got := h.handleRegister(tt.args.cmd, tt.args.params); got != tt.want {
 				t.Errorf("handleRegister() = %v, want %v", got, tt.want)
 			}
 		})
 	}
 }
 
+// dateSpecForTest - the date days from today as it would be typed in a command
+func dateSpecForTest(days int) string {
+	return testNow().AddDate(0, 0, days).Format(util.SpotDateFormat)
+}
+
+func Test_handleRegisterUnknownSpot(t *testing.T) {
+	h := newTestHandler()
+	h.spots.ImportCatalog([]data.CatalogSpot{{ID: "A1"}})
+	cmd := &slack.SlashCommand{UserID: "slackuser"}
+	assert.Equal(t, h.handleRegister(cmd, []string{"reg", "A1"}), fmt.Sprintf(SpotRegisteredTemplate, "A1"))
+	assert.Equal(t, h.handleRegister(cmd, []string{"reg", "Z9"}), fmt.Sprintf(SpotUnknownTemplate, "Z9"))
+	assert.Equal(t, h.handleRegister(cmd, []string{"reg", "Z9", "tomorrow..fri"}), fmt.Sprintf(SpotUnknownTemplate, "Z9"))
+	assert.Equal(t, h.handleRegister(cmd, []string{"reg", "Z9", "every", "mon"}), fmt.Sprintf(SpotUnknownTemplate, "Z9"))
+}
+
+func Test_handleRegisterHeldSpot(t *testing.T) {
+	h := newTestHandler()
+	h.spots.ImportCatalog([]data.CatalogSpot{{ID: "A1", Holder: "slackuser"}})
+	assert.Equal(t, h.handleRegister(&slack.SlashCommand{UserID: "pparker"}, []string{"reg", "A1"}), fmt.Sprintf(SpotNotHolderTemplate, "A1"))
+	assert.Equal(t, h.handleRegister(&slack.SlashCommand{UserID: "slackuser"}, []string{"reg", "A1"}), fmt.Sprintf(SpotRegisteredTemplate, "A1"))
+}
+
+func Test_handleRegisterRangeDupes(t *testing.T) {
+	h := newTestHandler()
+	cmd := &slack.SlashCommand{UserID: "slackuser"}
+	h.handleRegister(cmd, []string{"reg", "A3", dateSpecForTest(2)})
+	got := h.handleRegister(cmd, []string{"reg", "A3", dateSpecForTest(1) + util.DateRangeSep + dateSpecForTest(2)})
+	want := fmt.Sprintf(SpotRangeRegisteredTemplate, "A3", dateSpecForTest(1)) + "\n" + fmt.Sprintf(SpotRangeDupeTemplate, "A3", dateSpecForTest(2))
+	assert.Equal(t, got, want)
+}
+
 func Test_handleClaim(t *testing.T) {
-	defer cleanup()
 	type args struct {
 		params []string
 		cmd    *slack.SlashCommand
@@ -318,7 +451,7 @@ func Test_handleClaim(t *testing.T) {
 			args: args{
 				params: []string{"take", "B4"},
 				cmd: &slack.SlashCommand{
-					UserName: "ponyboy",
+					UserID: "ponyboy",
 				},
 			},
 			want: fmt.Sprintf(SpotClaimedTemplate, "B4"),
@@ -328,7 +461,7 @@ func Test_handleClaim(t *testing.T) {
 			args: args{
 				params: []string{"take"},
 				cmd: &slack.SlashCommand{
-					UserName: "ponyboy",
+					UserID: "ponyboy",
 				},
 			},
 			want: IDKBlank,
@@ -338,26 +471,294 @@ func Test_handleClaim(t *testing.T) {
 			args: args{
 				params: []string{"take", "X11"},
 				cmd: &slack.SlashCommand{
-					UserName: "ponyboy",
+					UserID: "ponyboy",
 				},
 			},
 			want: fmt.Sprintf(SpotClaimErrorTemplate, "X11"),
 		},
+		{
+			name: "should claim a spot for a later date",
+			args: args{
+				params: []string{"take", "B3", "tomorrow"},
+				cmd: &slack.SlashCommand{
+					UserID: "ponyboy",
+				},
+			},
+			want: fmt.Sprintf(SpotClaimedForTemplate, "B3", dateSpecForTest(1)),
+		},
+		{
+			name: "should not claim a spot not registered for a later date",
+			args: args{
+				params: []string{"take", "B4", "tomorrow"},
+				cmd: &slack.SlashCommand{
+					UserID: "ponyboy",
+				},
+			},
+			want: fmt.Sprintf(SpotClaimDateErrorTemplate, "B4", dateSpecForTest(1)),
+		},
+		{
+			name: "should not claim a spot for a past date",
+			args: args{
+				params: []string{"take", "B0", dateSpecForTest(-1)},
+				cmd: &slack.SlashCommand{
+					UserID: "ponyboy",
+				},
+			},
+			want: fmt.Sprintf(SpotPastDateRegistrationErrorTemplate, dateSpecForTest(-1)),
+		},
+		{
+			name: "should not claim a spot beyond the horizon",
+			args: args{
+				params: []string{"take", "B3", "+30d"},
+				cmd: &slack.SlashCommand{
+					UserID: "ponyboy",
+				},
+			},
+			want: fmt.Sprintf(SpotBeyondHorizonTemplate, spot.DefaultClaimHorizon),
+		},
+		{
+			name: "should not understand a bad date",
+			args: args{
+				params: []string{"take", "B3", "someday"},
+				cmd: &slack.SlashCommand{
+					UserID: "ponyboy",
+				},
+			},
+			want: fmt.Sprintf(SpotDateFormatRegistrationErrorTemplate, "someday"),
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			cleanup()
-			data.Open()
-			registerSpotsForTest(testSpots())
-			if got := handleClaim(tt.args.cmd, tt.args.params); got != tt.want {
+			h := newTestHandler()
+			registerSpotsForTest(h, testSpots())
+			if got := h.handleClaim(tt.args.cmd, tt.args.params); got != tt.want {
 				t.Errorf("handleReserve() = %v, want %v", got, tt.want)
 			}
 		})
 	}
 }
 
+func Test_handleClaimAlreadyClaimed(t *testing.T) {
+	h := newTestHandler()
+	registerSpotsForTest(h, testSpots())
+	h.handleClaim(&slack.SlashCommand{UserID: "ponyboy"}, []string{"take", "B4"})
+	got := h.handleClaim(&slack.SlashCommand{UserID: "sodapop"}, []string{"take", "B4"})
+	assert.Equal(t, got, fmt.Sprintf(SpotAlreadyClaimedTemplate, "B4", "ponyboy"))
+}
+
+func Test_handleRelease(t *testing.T) {
+	type args struct {
+		params []string
+		cmd    *slack.SlashCommand
+	}
+	tests := []struct {
+		name string
+		args args
+		want string
+	}{
+		{
+			name: "should release a claimed spot",
+			args: args{
+				params: []string{"release", "B4"},
+				cmd: &slack.SlashCommand{
+					UserID: "ponyboy",
+				},
+			},
+			want: fmt.Sprintf(SpotReleasedTemplate, "B4"),
+		},
+		{
+			name: "should not release a spot claimed by someone else",
+			args: args{
+				params: []string{"release", "B4"},
+				cmd: &slack.SlashCommand{
+					UserID: "sodapop",
+				},
+			},
+			want: fmt.Sprintf(SpotReleaseErrorTemplate, "B4"),
+		},
+		{
+			name: "should need a spot id",
+			args: args{
+				params: []string{"release"},
+				cmd: &slack.SlashCommand{
+					UserID: "ponyboy",
+				},
+			},
+			want: IDKBlank,
+		},
+		{
+			name: "should release a spot claimed for a later date",
+			args: args{
+				params: []string{"release", "B3", "tomorrow"},
+				cmd: &slack.SlashCommand{
+					UserID: "ponyboy",
+				},
+			},
+			want: fmt.Sprintf(SpotReleasedForTemplate, "B3", dateSpecForTest(1)),
+		},
+		{
+			name: "should not release a spot not claimed for the date",
+			args: args{
+				params: []string{"release", "B4", "tomorrow"},
+				cmd: &slack.SlashCommand{
+					UserID: "ponyboy",
+				},
+			},
+			want: fmt.Sprintf(SpotReleaseDateErrorTemplate, "B4", dateSpecForTest(1)),
+		},
+		{
+			name: "should not release a spot for a date before today",
+			args: args{
+				params: []string{"release", "B4", dateSpecForTest(-1)},
+				cmd: &slack.SlashCommand{
+					UserID: "ponyboy",
+				},
+			},
+			want: fmt.Sprintf(SpotPastDateRegistrationErrorTemplate, dateSpecForTest(-1)),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newTestHandler()
+			registerSpotsForTest(h, testSpots())
+			h.handleClaim(&slack.SlashCommand{UserID: "ponyboy"}, []string{"take", "B4"})
+			h.handleClaim(&slack.SlashCommand{UserID: "ponyboy"}, []string{"take", "B3", "tomorrow"})
+			if got := h.handleRelease(tt.args.cmd, tt.args.params); got != tt.want {
+				t.Errorf("handleRelease() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_handleMine(t *testing.T) {
+	tests := []struct {
+		name string
+		user string
+		want string
+	}{
+		{
+			name: "should list registrations and claims",
+			user: "slackuser",
+			want: fmt.Sprintf(MyRegistrationsTemplate, strings.Join([]string{
+				fmt.Sprintf(MyClaimedRegistrationTemplate, "B1", testNow().Format(util.SpotDateFormat), "ponyboy"),
+				fmt.Sprintf(MyOpenRegistrationTemplate, "B2", testNow().Format(util.SpotDateFormat)),
+			}, "\n")),
+		},
+		{
+			name: "should have no registrations",
+			user: "ponyboy",
+			want: NoRegistrations,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newTestHandler()
+			registerSpotsForTest(h, testSpots())
+			h.handleClaim(&slack.SlashCommand{UserID: "ponyboy"}, []string{"take", "B1"})
+			if got := h.handleMine(&slack.SlashCommand{UserID: tt.user}); got != tt.want {
+				t.Errorf("handleMine() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_handleRegisterRecurring(t *testing.T) {
+	tests := []struct {
+		name   string
+		params []string
+		want   string
+	}{
+		{
+			name:   "should register every friday",
+			params: []string{"reg", "R1", "every", "fri"},
+			want:   fmt.Sprintf(SpotRecurringRegisteredTemplate, "R1", "fri", UntilFurtherNotice),
+		},
+		{
+			name:   "should register until a date",
+			params: []string{"reg", "R1", "every", "wed,mon", "until", dateSpecForTest(30)},
+			want:   fmt.Sprintf(SpotRecurringRegisteredTemplate, "R1", "mon,wed", dateSpecForTest(30)),
+		},
+		{
+			name:   "should not register bad days",
+			params: []string{"reg", "R1", "every", "someday"},
+			want:   fmt.Sprintf(SpotWeekdaysErrorTemplate, "someday"),
+		},
+		{
+			name:   "should not register until a past date",
+			params: []string{"reg", "R1", "every", "fri", "until", dateSpecForTest(-1)},
+			want:   fmt.Sprintf(SpotPastDateRegistrationErrorTemplate, dateSpecForTest(-1)),
+		},
+		{
+			name:   "should need days",
+			params: []string{"reg", "R1", "every"},
+			want:   IDKBlank,
+		},
+		{
+			name:   "should not register someone else's recurring spot",
+			params: []string{"reg", "R2", "every", "fri"},
+			want:   fmt.Sprintf(SpotRecurringDupeTemplate, "R2", "FredsMom"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newTestHandler()
+			h.handleRegister(&slack.SlashCommand{UserID: "FredsMom"}, []string{"reg", "R2", "every", "mon"})
+			if got := h.handleRegister(&slack.SlashCommand{UserID: "slackuser"}, tt.params); got != tt.want {
+				t.Errorf("handleRegister() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_handleRecurring(t *testing.T) {
+	tests := []struct {
+		name   string
+		user   string
+		params []string
+		want   string
+	}{
+		{
+			name:   "should list recurrences",
+			user:   "slackuser",
+			params: []string{"recurring"},
+			want: fmt.Sprintf(MyRecurrencesTemplate, strings.Join([]string{
+				fmt.Sprintf(MyRecurrenceTemplate, "R1", "fri", UntilFurtherNotice),
+				fmt.Sprintf(MyRecurrenceTemplate, "R2", "mon,tue", dateSpecForTest(30)),
+			}, "\n")),
+		},
+		{
+			name:   "should have no recurrences",
+			user:   "ponyboy",
+			params: []string{"recurring"},
+			want:   NoRecurrences,
+		},
+		{
+			name:   "should cancel a recurrence",
+			user:   "slackuser",
+			params: []string{"recurring", "cancel", "R1"},
+			want:   fmt.Sprintf(SpotRecurrenceCancelledTemplate, "R1"),
+		},
+		{
+			name:   "should not cancel someone else's recurrence",
+			user:   "ponyboy",
+			params: []string{"recurring", "cancel", "R1"},
+			want:   fmt.Sprintf(SpotRecurrenceCancelErrorTemplate, "R1"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newTestHandler()
+			cmd := &slack.SlashCommand{UserID: "slackuser"}
+			h.handleRegister(cmd, []string{"reg", "R1", "every", "fri"})
+			h.handleRegister(cmd, []string{"reg", "R2", "every", "mon,tue", "until", dateSpecForTest(30)})
+			if got := h.handleRecurring(&slack.SlashCommand{UserID: tt.user}, tt.params); got != tt.want {
+				t.Errorf("handleRecurring() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func Test_handleHelp(t *testing.T) {
-	defer cleanup()
 	tests := []struct {
 		name string
 		want string
@@ -377,7 +778,6 @@ func Test_handleHelp(t *testing.T) {
 }
 
 func Test_handleUnknown(t *testing.T) {
-	defer cleanup()
 	type args struct {
 		action string
 	}
@@ -404,7 +804,6 @@ func Test_handleUnknown(t *testing.T) {
 }
 
 func Test_handleVersion(t *testing.T) {
-	defer cleanup()
 	tests := []struct {
 		name string
 		want string
@@ -422,3 +821,205 @@ func Test_handleVersion(t *testing.T) {
 		})
 	}
 }
+
+type testTimezones map[string]*time.Location
+
+func (tz testTimezones) Location(userID string) (*time.Location, error) {
+	loc, ok := tz[userID]
+	if !ok {
+		return nil, errors.New("no timezone")
+	}
+	return loc, nil
+}
+
+func Test_forUser(t *testing.T) {
+	chicago, _ := time.LoadLocation("America/Chicago")
+	tokyo, _ := time.LoadLocation("Asia/Tokyo")
+	h := newTestHandler(
+		WithTeamTimezones(map[string]*time.Location{"T1": chicago}),
+		WithUserTimezones(testTimezones{"U1": tokyo}),
+	)
+	tests := []struct {
+		name string
+		cmd  *slack.SlashCommand
+		want *time.Location
+	}{
+		{
+			name: "should use the user's timezone",
+			cmd:  &slack.SlashCommand{TeamID: "T1", UserID: "U1"},
+			want: tokyo,
+		},
+		{
+			name: "should use the workspace's timezone",
+			cmd:  &slack.SlashCommand{TeamID: "T1", UserID: "U2"},
+			want: chicago,
+		},
+		{
+			name: "should use the default timezone",
+			cmd:  &slack.SlashCommand{TeamID: "T2", UserID: "U2"},
+			want: testLocation,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := h.forUser(commandUser(tt.cmd))
+			assert.Equal(t, u.spots.Location(), tt.want)
+			assert.Assert(t, u.now().Equal(testNow()), "should keep the handler's clock")
+		})
+	}
+}
+
+func Test_handleAdmin(t *testing.T) {
+	h := newTestHandler(WithAdmins(NewAdminList("U1")))
+	registerSpotsForTest(h, testSpots())
+	h.spots.ImportCatalog([]data.CatalogSpot{{ID: "B1"}, {ID: "B2"}, {ID: "B3"}, {ID: "B4"}})
+	h.spots.Claim("B1", "ponyboy")
+	h.spots.RememberUser(data.User{ID: "U7", Name: "pparker"})
+	admin := &slack.SlashCommand{UserID: "U1", UserName: "boss"}
+	today := testNow().Format(util.SpotDateFormat)
+	tomorrow := testNow().AddDate(0, 0, 1).Format(util.SpotDateFormat)
+	tests := []struct {
+		name   string
+		cmd    *slack.SlashCommand
+		params []string
+		want   string
+	}{
+		{
+			name:   "should not let anyone else use admin commands",
+			cmd:    &slack.SlashCommand{UserID: "U2", UserName: "slackuser"},
+			params: []string{"admin", "list"},
+			want:   NotAdmin,
+		},
+		{
+			name:   "should give admin help",
+			cmd:    admin,
+			params: []string{"admin"},
+			want:   AdminHelpText,
+		},
+		{
+			name:   "should list every registration",
+			cmd:    admin,
+			params: []string{"admin", "list"},
+			want: fmt.Sprintf(AllRegistrationsTemplate, strings.Join([]string{
+				fmt.Sprintf(AdminClaimedRegistrationTemplate, "B1", today, "slackuser", "ponyboy"),
+				fmt.Sprintf(AdminOpenRegistrationTemplate, "B2", today, "slackuser"),
+				fmt.Sprintf(AdminOpenRegistrationTemplate, "B4", today, "BarneysMom"),
+				fmt.Sprintf(AdminOpenRegistrationTemplate, "B3", tomorrow, "FredsMom"),
+			}, "\n")),
+		},
+		{
+			name:   "should release anyone's claim",
+			cmd:    admin,
+			params: []string{"admin", "release", "B1"},
+			want:   fmt.Sprintf(AdminReleaseTemplate, "B1"),
+		},
+		{
+			name:   "should not release an open spot",
+			cmd:    admin,
+			params: []string{"admin", "release", "B1"},
+			want:   fmt.Sprintf(AdminReleaseErrorTemplate, "B1"),
+		},
+		{
+			name:   "should drop anyone's registration on a date",
+			cmd:    admin,
+			params: []string{"admin", "drop", "B3", "tomorrow"},
+			want:   fmt.Sprintf(AdminDropTemplate, "B3", tomorrow, "FredsMom"),
+		},
+		{
+			name:   "should not drop a missing registration",
+			cmd:    admin,
+			params: []string{"admin", "drop", "B3"},
+			want:   fmt.Sprintf(AdminDropErrorTemplate, "B3", today),
+		},
+		{
+			name:   "should assign a spot",
+			cmd:    admin,
+			params: []string{"admin", "assign", "B2", "@pparker"},
+			want:   fmt.Sprintf(AdminAssignTemplate, "B2", "pparker"),
+		},
+		{
+			name:   "should assign a spot to a mentioned user",
+			cmd:    admin,
+			params: []string{"admin", "assign", "B2", "<@U7|pparker>"},
+			want:   fmt.Sprintf(AdminAssignTemplate, "B2", "pparker"),
+		},
+		{
+			name:   "should not assign a spot to an unknown user",
+			cmd:    admin,
+			params: []string{"admin", "assign", "B2", "@mjwatson"},
+			want:   fmt.Sprintf(UnknownUserTemplate, "@mjwatson"),
+		},
+		{
+			name:   "should unassign a spot",
+			cmd:    admin,
+			params: []string{"admin", "assign", "B2"},
+			want:   fmt.Sprintf(AdminUnassignTemplate, "B2"),
+		},
+		{
+			name:   "should not assign an unknown spot",
+			cmd:    admin,
+			params: []string{"admin", "assign", "Z9", "pparker"},
+			want:   fmt.Sprintf(SpotUnknownTemplate, "Z9"),
+		},
+		{
+			name:   "should purge past registrations",
+			cmd:    admin,
+			params: []string{"admin", "purge"},
+			want:   fmt.Sprintf(AdminPurgeTemplate, 1),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, h.forUser(commandUser(tt.cmd)).handleAdmin(tt.cmd, tt.params), tt.want)
+		})
+	}
+}
+
+func Test_spotCommandHandlerShowsNames(t *testing.T) {
+	h := newTestHandler()
+	for _, cmd := range []*slack.SlashCommand{
+		{Text: "reg B1", UserID: "U1", UserName: "slackuser"},
+		{Text: "claim B1", UserID: "U2", UserName: "ponyboy"},
+		{Text: "claim B1", UserID: "U3", UserName: "sodapop"},
+	} {
+		rr := httptest.NewRecorder()
+		h.spotCommandHandler(cmd, rr)
+		if cmd.UserID == "U3" {
+			assert.Equal(t, rr.Body.String(), ephemeral(fmt.Sprintf(SpotAlreadyClaimedTemplate, "B1", "ponyboy")))
+		}
+	}
+	regs, _ := h.spots.Registrations("U1")
+	assert.Equal(t, regs[0].RegisteredBy, "U1", "should keep users by ID")
+	assert.Equal(t, regs[0].ClaimedBy, "U2", "should keep users by ID")
+}
+
+func Test_spotCommandHandlerInChannel(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{name: "should share a registration", text: "reg B1", want: ResponseInChannel},
+		{name: "should share a claim", text: "claim B1", want: ResponseInChannel},
+		{name: "should not share a failed claim", text: "take B1", want: ResponseEphemeral},
+		{name: "should share a range registration", text: "reg B2 tomorrow..fri", want: ResponseInChannel},
+		{name: "should not share a failed registration", text: "reg B1", want: ResponseEphemeral},
+		{name: "should not share find results", text: "find", want: ResponseEphemeral},
+		{name: "should not share help", text: "help", want: ResponseEphemeral},
+	}
+	h := newTestHandler(WithInChannel("reg", "claim"))
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			h.spotCommandHandler(&slack.SlashCommand{Text: tt.text, UserID: "U1"}, rr)
+			var got message
+			assert.NilError(t, json.Unmarshal(rr.Body.Bytes(), &got))
+			assert.Equal(t, got.ResponseType, tt.want)
+		})
+	}
+
+	h = newTestHandler(WithInChannel("claim"))
+	rr := httptest.NewRecorder()
+	h.spotCommandHandler(&slack.SlashCommand{Text: "reg B1", UserID: "U1"}, rr)
+	assert.Equal(t, rr.Body.String(), ephemeral(fmt.Sprintf(SpotRegisteredTemplate, "B1")), "should not share commands left out")
+}
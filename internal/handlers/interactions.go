@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"os"
+
+	"github.com/jasonholmberg/slashspot/internal/data"
+	"github.com/nlopes/slack"
+)
+
+// ClaimActionID - the action of the claim buttons in the find message
+const ClaimActionID = "claim"
+
+// InteractionHandler - the handler for slack interactions, like clicking a button in one of spot's messages
+func (h *Handler) InteractionHandler(w http.ResponseWriter, r *http.Request) {
+	verifier, err := slack.NewSecretsVerifier(r.Header, os.Getenv("SPOT_SLACK_SIGNING_SECRET"))
+	if err != nil {
+		log.Print("ERROR - slashspot may not be configured correctly, check you set up: ", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	r.Body = ioutil.NopCloser(io.TeeReader(r.Body, &verifier))
+	if err = r.ParseForm(); err != nil {
+		log.Print(err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	if err = verifier.Ensure(); err != nil {
+		log.Print(err)
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	var callback slack.InteractionCallback
+	if err = json.Unmarshal([]byte(r.PostForm.Get("payload")), &callback); err != nil {
+		log.Print(err)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	switch callback.Type {
+	case slack.InteractionTypeBlockActions:
+		h.blockActionsHandler(&callback, w)
+	case interactionTypeViewSubmission:
+		h.viewSubmissionHandler([]byte(r.PostForm.Get("payload")), w)
+	default:
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+}
+
+// interactionUser - the user who interacted with a message
+func interactionUser(callback *slack.InteractionCallback) data.User {
+	return data.User{ID: callback.User.ID, Name: callback.User.Name, TeamID: callback.Team.ID}
+}
+
+func (h *Handler) blockActionsHandler(callback *slack.InteractionCallback, w http.ResponseWriter) {
+	h = h.forUser(interactionUser(callback))
+	for _, action := range callback.ActionCallback.BlockActions {
+		log.Printf("Spot action received %v %v", action.ActionID, action.Value)
+		switch action.ActionID {
+		case ClaimActionID:
+			action := action
+			claim := func() {
+				msg := h.handleClaimAction(callback, action)
+				if err := postMessageWithRetries(callback.ResponseURL, msg); err != nil {
+					log.Print("Error updating message ", err)
+				}
+			}
+			if h.responses == nil || !h.responses.Submit(claim) {
+				claim()
+			}
+		}
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// handleClaimAction - claim the spot of a claim button and update the find message it was in with the outcome
+func (h *Handler) handleClaimAction(callback *slack.InteractionCallback, action *slack.BlockAction) message {
+	id, filters := parseClaimValue(action.Value)
+	if location, rest, ok := h.splitLocation(filters); ok {
+		h = h.at(callback.User.ID, location)
+		filters = rest
+	}
+	window, _, _ := splitWindow(filters)
+	outcome := h.claimWindow(id, callback.User.ID, h.now(), window)
+	msg := h.findMessage(filters)
+	msg.ReplaceOriginal = true
+	msg.Text = outcome + "\n" + msg.Text
+	msg.Blocks = append([]slack.Block{slack.NewContextBlock("", markdown(outcome))}, msg.Blocks...)
+	return msg
+}
@@ -0,0 +1,96 @@
+package handlers
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nlopes/slack"
+	"gotest.tools/v3/assert"
+)
+
+const testSigningSecret = "shhh"
+
+// signedRequest - a request signed the way slack signs them
+func signedRequest(t *testing.T, path string, form url.Values) *http.Request {
+	os.Setenv("SPOT_SLACK_SIGNING_SECRET", testSigningSecret)
+	body := form.Encode()
+	ts := strconv.FormatInt(time.Now().Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(testSigningSecret))
+	fmt.Fprintf(mac, "v0:%s:%s", ts, body)
+	r := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	r.Header.Set("X-Slack-Request-Timestamp", ts)
+	r.Header.Set("X-Slack-Signature", "v0="+hex.EncodeToString(mac.Sum(nil)))
+	return r
+}
+
+// responseURL - a server standing in for a slack response URL, the messages posted to it are sent on the channel
+func responseURL(t *testing.T) (*httptest.Server, chan message) {
+	posted := make(chan message, 10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := ioutil.ReadAll(r.Body)
+		var msg struct {
+			ReplaceOriginal bool   `json:"replace_original"`
+			Text            string `json:"text"`
+		}
+		if err := json.Unmarshal(b, &msg); err != nil {
+			t.Error(err)
+		}
+		posted <- message{ReplaceOriginal: msg.ReplaceOriginal, Text: msg.Text}
+	}))
+	return server, posted
+}
+
+func claimPayload(t *testing.T, responseURL string, userID string, value string) url.Values {
+	payload, err := json.Marshal(map[string]interface{}{
+		"type":         slack.InteractionTypeBlockActions,
+		"user":         map[string]string{"id": userID, "name": "ponyboy"},
+		"team":         map[string]string{"id": "T1"},
+		"response_url": responseURL,
+		"actions": []map[string]string{
+			{"action_id": ClaimActionID, "block_id": "b1", "type": "button", "value": value},
+		},
+	})
+	assert.NilError(t, err)
+	return url.Values{"payload": {string(payload)}}
+}
+
+func TestInteractionHandler(t *testing.T) {
+	server, posted := responseURL(t)
+	defer server.Close()
+	h := newTestHandler()
+	registerSpotsForTest(h, testSpots())
+
+	rr := httptest.NewRecorder()
+	h.InteractionHandler(rr, signedRequest(t, "/interactions", claimPayload(t, server.URL, "U2", "B1")))
+	assert.Equal(t, rr.Code, http.StatusOK)
+	msg := <-posted
+	assert.Assert(t, msg.ReplaceOriginal, "should update the find message")
+	assert.Equal(t, msg.Text, fmt.Sprintf(SpotClaimedTemplate, "B1")+"\n"+fmt.Sprintf(OpenSpotsTemplate, "B2,B4"))
+
+	rr = httptest.NewRecorder()
+	h.InteractionHandler(rr, signedRequest(t, "/interactions", claimPayload(t, server.URL, "U3", "B1")))
+	msg = <-posted
+	assert.Equal(t, msg.Text, fmt.Sprintf(SpotAlreadyClaimedTemplate, "B1", "ponyboy")+"\n"+fmt.Sprintf(OpenSpotsTemplate, "B2,B4"))
+}
+
+func TestInteractionHandlerUnsigned(t *testing.T) {
+	h := newTestHandler()
+	r := signedRequest(t, "/interactions", claimPayload(t, "http://localhost", "U2", "B1"))
+	r.Header.Set("X-Slack-Signature", "v0="+hex.EncodeToString([]byte("forged")))
+	rr := httptest.NewRecorder()
+	h.InteractionHandler(rr, r)
+	assert.Equal(t, rr.Code, http.StatusUnauthorized)
+}
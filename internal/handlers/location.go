@@ -0,0 +1,151 @@
+package handlers
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/nlopes/slack"
+)
+
+const (
+	// LocationTemplate - The user's location template
+	LocationTemplate = "You find, claim and register spots at %s. Use `/spot location <name>` to pick another of %s, or add @<name> to a command to use it once"
+
+	// LocationSetTemplate - Location picked template
+	LocationSetTemplate = "You now find, claim and register spots at %s"
+
+	// LocationUnknownTemplate - Unknown location picked template
+	LocationUnknownTemplate = "I don't know the location %s, pick one of %s"
+
+	// LocationErrorTemplate - Location pick error template
+	LocationErrorTemplate = "Your location could not be set to %s"
+
+	// LocationsOffText - Location asked for when there is only one
+	LocationsOffText = "There is only one location, every spot is there"
+
+	// locationPrefix - starts a location given in a command, like @downtown
+	locationPrefix = "@"
+)
+
+// WithLocations - find, claim and register spots at the locations. Users pick where with @<location> in a command or
+// once with /spot location, users who have not picked are at the first location. Without locations every spot is at
+// no location.
+func WithLocations(locations ...string) Option {
+	return func(h *Handler) {
+		h.locations = append([]string(nil), locations...)
+	}
+}
+
+// WithLocationAdmins - let users the admins agree on use the admin commands and register any spot at the location
+func WithLocationAdmins(location string, admins ...Admins) Option {
+	return func(h *Handler) {
+		if h.locationAdmins == nil {
+			h.locationAdmins = make(map[string][]Admins)
+		}
+		h.locationAdmins[location] = append(h.locationAdmins[location], admins...)
+	}
+}
+
+// handleLocation - location [name]
+func (h *Handler) handleLocation(cmd *slack.SlashCommand, params []string) string {
+	if len(h.locations) == 0 {
+		return LocationsOffText
+	}
+	names := strings.Join(h.locations, ", ")
+	if len(params) < 2 || params[1] == "" {
+		return fmt.Sprintf(LocationTemplate, h.spots.Where(), names)
+	}
+	location, ok := h.knownLocation(params[1])
+	if !ok {
+		return fmt.Sprintf(LocationUnknownTemplate, params[1], names)
+	}
+	if err := h.spots.SetDefaultLocation(cmd.UserID, location); err != nil {
+		return fmt.Sprintf(LocationErrorTemplate, location)
+	}
+	return fmt.Sprintf(LocationSetTemplate, location)
+}
+
+// knownLocation - the location with the name, with or without the @ and compared without case
+func (h *Handler) knownLocation(name string) (string, bool) {
+	name = strings.TrimPrefix(name, locationPrefix)
+	for _, location := range h.locations {
+		if strings.EqualFold(location, name) {
+			return location, true
+		}
+	}
+	return "", false
+}
+
+// splitLocation - take a location like @downtown out of the params. Returns false when none is given. Only known
+// locations are taken, so users mentioned like @name are left alone.
+func (h *Handler) splitLocation(params []string) (string, []string, bool) {
+	for i, p := range params {
+		if !strings.HasPrefix(p, locationPrefix) {
+			continue
+		}
+		if location, ok := h.knownLocation(p); ok {
+			rest := append(append([]string(nil), params[:i]...), params[i+1:]...)
+			return location, rest, true
+		}
+	}
+	return "", params, false
+}
+
+// withLocation - the params followed by the handler's location, so it is kept by the values of buttons
+func (h *Handler) withLocation(params []string) []string {
+	if h.spots.Where() == "" {
+		return params
+	}
+	return append(append([]string(nil), params...), locationPrefix+h.spots.Where())
+}
+
+// defaultLocation - the location the user picked, or the first location when they have not picked a known one
+func (h *Handler) defaultLocation(userID string) string {
+	if len(h.locations) == 0 {
+		return ""
+	}
+	if location, ok := h.knownLocation(h.spots.DefaultLocation(userID)); ok {
+		return location
+	}
+	return h.locations[0]
+}
+
+// at - a copy of the handler whose spot service works at the location. If the user is an admin, or an admin of the
+// location, the spot service knows it.
+func (h *Handler) at(userID string, location string) *Handler {
+	u := *h
+	u.spots = h.spots.At(location)
+	if h.isAdmin(userID) || h.isLocationAdmin(location, userID) {
+		u.spots = u.spots.WithAdmins(userID)
+	} else {
+		u.spots = u.spots.WithAdmins()
+	}
+	return &u
+}
+
+// isLocationAdmin - returns true if any of the location's admins agree the user is an admin
+func (h *Handler) isLocationAdmin(location string, userID string) bool {
+	if userID == "" {
+		return false
+	}
+	for _, admins := range h.locationAdmins[location] {
+		ok, err := admins.IsAdmin(userID)
+		if err != nil {
+			log.Printf("Unable to check if %v is an admin of %v: %v", userID, location, err)
+			continue
+		}
+		if ok {
+			return true
+		}
+	}
+	return false
+}
+
+// spotName - the spot ID followed by its location, if it is at one
+func spotName(id string, location string) string {
+	if location == "" {
+		return id
+	}
+	return id + " " + locationPrefix + location
+}
@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/nlopes/slack"
+	"gotest.tools/v3/assert"
+)
+
+func Test_handleLocation(t *testing.T) {
+	tests := []struct {
+		name   string
+		params []string
+		want   string
+	}{
+		{name: "should start users at the first location", params: []string{"location"}, want: fmt.Sprintf(LocationTemplate, "downtown", "downtown, uptown")},
+		{name: "should pick a location", params: []string{"location", "@Uptown"}, want: fmt.Sprintf(LocationSetTemplate, "uptown")},
+		{name: "should remember the location", params: []string{"location"}, want: fmt.Sprintf(LocationTemplate, "uptown", "downtown, uptown")},
+		{name: "should not pick an unknown location", params: []string{"location", "harbor"}, want: fmt.Sprintf(LocationUnknownTemplate, "harbor", "downtown, uptown")},
+	}
+	h := newTestHandler(WithLocations("downtown", "uptown"))
+	cmd := &slack.SlashCommand{UserID: "U1", UserName: "slackuser"}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := h.forUser(commandUser(cmd)).handleLocation(cmd, tt.params)
+			assert.Equal(t, got, tt.want)
+		})
+	}
+
+	off := newTestHandler()
+	assert.Equal(t, off.handleLocation(cmd, []string{"location", "uptown"}), LocationsOffText, "should say there is one location")
+}
+
+func Test_spotCommandAtLocation(t *testing.T) {
+	h := newTestHandler(WithLocations("downtown", "uptown"))
+	for _, text := range []string{"reg 12", "reg 12 @uptown"} {
+		msg, _ := h.spotCommand(&slack.SlashCommand{Text: text, UserID: "U1", UserName: "slackuser"})
+		assert.Equal(t, msg.Text, fmt.Sprintf(SpotRegisteredTemplate, "12"), text)
+	}
+	msg, _ := h.spotCommand(&slack.SlashCommand{Text: "claim 12 @uptown", UserID: "U2", UserName: "ponyboy"})
+	assert.Equal(t, msg.Text, fmt.Sprintf(SpotClaimedTemplate, "12"))
+	regs, _ := h.spots.Registrations("U1")
+	assert.Equal(t, len(regs), 2)
+	assert.Equal(t, regs[0].Location, "downtown")
+	assert.Equal(t, regs[0].ClaimedBy, "", "should leave the spot at the user's location open")
+	assert.Equal(t, regs[1].Location, "uptown")
+	assert.Equal(t, regs[1].ClaimedBy, "U2", "should claim the spot at the location given")
+
+	msg, _ = h.spotCommand(&slack.SlashCommand{Text: "mine", UserID: "U1", UserName: "slackuser"})
+	want := fmt.Sprintf(MyRegistrationsTemplate, fmt.Sprintf(MyOpenRegistrationTemplate, "12 @downtown", dateSpecForTest(0))+"\n"+
+		fmt.Sprintf(MyClaimedRegistrationTemplate, "12 @uptown", dateSpecForTest(0), "ponyboy"))
+	assert.Equal(t, msg.Text, want, "should list registrations at every location")
+}
+
+func Test_spotCommandLocationAdmins(t *testing.T) {
+	h := newTestHandler(WithLocations("downtown", "uptown"), WithLocationAdmins("uptown", NewAdminList("U9")))
+	msg, _ := h.spotCommand(&slack.SlashCommand{Text: "admin list", UserID: "U9", UserName: "boss"})
+	assert.Equal(t, msg.Text, NotAdmin, "should not be an admin at other locations")
+	msg, _ = h.spotCommand(&slack.SlashCommand{Text: "admin list @uptown", UserID: "U9", UserName: "boss"})
+	assert.Equal(t, msg.Text, NoAllRegistrations, "should be an admin at the location")
+}
+
+func Test_splitLocation(t *testing.T) {
+	h := newTestHandler(WithLocations("downtown"))
+	location, rest, ok := h.splitLocation([]string{"B1", "@Downtown", "am"})
+	assert.Assert(t, ok)
+	assert.Equal(t, location, "downtown")
+	assert.DeepEqual(t, rest, []string{"B1", "am"})
+	_, rest, ok = h.splitLocation([]string{"B1", "@ponyboy"})
+	assert.Assert(t, !ok, "should leave users mentioned by name alone")
+	assert.DeepEqual(t, rest, []string{"B1", "@ponyboy"})
+}
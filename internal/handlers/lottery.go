@@ -0,0 +1,124 @@
+package handlers
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jasonholmberg/slashspot/internal/data"
+	"github.com/jasonholmberg/slashspot/internal/spot"
+	"github.com/jasonholmberg/slashspot/internal/util"
+	"github.com/nlopes/slack"
+)
+
+const (
+	// EnterTemplate - Draw entered template
+	EnterTemplate = "You are in the draw for %s, it is made at %s. Good luck!"
+
+	// EnterAlreadyTemplate - Draw entered twice template
+	EnterAlreadyTemplate = "You are already in the draw for %s"
+
+	// EnterClosedTemplate - Draw entered after its cutoff template
+	EnterClosedTemplate = "The draw for %s is closed"
+
+	// EnterErrorTemplate - Draw entry error template
+	EnterErrorTemplate = "You could not be put in the draw for %s"
+
+	// EnterCancelledTemplate - Draw left template
+	EnterCancelledTemplate = "You are out of the draw for %s"
+
+	// EnterNotEnteredTemplate - Draw left without being in it template
+	EnterNotEnteredTemplate = "You are not in the draw for %s"
+
+	// LotteryOffText - Draw entered while spots are first come first served
+	LotteryOffText = "There is no draw, spots are first come first served. Use `/spot find` to see the open ones"
+
+	// SpotDrawPendingTemplate - Spot claimed before the draw template
+	SpotDrawPendingTemplate = "Spots for %s are given out by a draw at %s, use `/spot enter` to take part"
+
+	// AdminDrawTemplate - Draw audit template
+	AdminDrawTemplate = "The %s draw for %s was made %s with seed %d\n- Entered: %s\n- Won: %s"
+
+	// AdminNoDrawTemplate - No draw made for a date template
+	AdminNoDrawTemplate = "No draw has been made for %s"
+)
+
+// handleEnter - enter [date] or enter cancel [date]
+func (h *Handler) handleEnter(cmd *slack.SlashCommand, params []string) string {
+	if !h.spots.Lottery() {
+		return LotteryOffText
+	}
+	cancel := len(params) > 1 && strings.ToLower(params[1]) == "cancel"
+	spec := params[1:]
+	if cancel {
+		spec = params[2:]
+	}
+	date := h.now()
+	if len(spec) > 0 {
+		var err error
+		if date, err = util.ParseDate(strings.Join(spec, " "), h.now()); err != nil {
+			return fmt.Sprintf(SpotDateFormatRegistrationErrorTemplate, strings.Join(spec, " "))
+		}
+	}
+	day := date.Format(util.SpotDateFormat)
+	if cancel {
+		if err := h.spots.CancelEntry(cmd.UserID, date); err != nil {
+			return fmt.Sprintf(EnterNotEnteredTemplate, day)
+		}
+		return fmt.Sprintf(EnterCancelledTemplate, day)
+	}
+	switch err := h.spots.Enter(cmd.UserID, date); err {
+	case nil:
+		return fmt.Sprintf(EnterTemplate, day, h.spots.Cutoff(date).Format(shortTimeFormat))
+	case spot.ErrAlreadyEntered:
+		return fmt.Sprintf(EnterAlreadyTemplate, day)
+	case spot.ErrDrawClosed:
+		return fmt.Sprintf(EnterClosedTemplate, day)
+	default:
+		return fmt.Sprintf(EnterErrorTemplate, day)
+	}
+}
+
+// handleAdminDraw - admin draw [date]
+func (h *Handler) handleAdminDraw(params []string) string {
+	date := h.now()
+	if len(params) > 2 {
+		spec := strings.Join(params[2:], " ")
+		var err error
+		if date, err = util.ParseDate(spec, h.now()); err != nil {
+			return fmt.Sprintf(SpotDateFormatRegistrationErrorTemplate, spec)
+		}
+	}
+	draw, err := h.spots.DrawOf(date)
+	if err == data.ErrNotFound {
+		return fmt.Sprintf(AdminNoDrawTemplate, date.Format(util.SpotDateFormat))
+	}
+	if err != nil {
+		return fmt.Sprintf(AdminErrorTemplate, "draw")
+	}
+	kind := "unweighted"
+	if draw.Weighted {
+		kind = "weighted"
+	}
+	drawnAt := draw.DrawnAt
+	if at, err := time.Parse(time.RFC3339, draw.DrawnAt); err == nil {
+		drawnAt = at.Format(shortTimeFormat)
+	}
+	entrants := make([]string, 0, len(draw.Entrants))
+	for _, user := range draw.Entrants {
+		entrants = append(entrants, h.userName(user))
+	}
+	wins := make([]string, 0, len(draw.Wins))
+	for _, win := range draw.Wins {
+		wins = append(wins, fmt.Sprintf("%s by %s", win.Spot, h.userName(win.User)))
+	}
+	return fmt.Sprintf(AdminDrawTemplate, kind, draw.Date, drawnAt, draw.Seed, orNone(entrants), orNone(wins))
+}
+
+// orNone - the items separated by commas, or none when there are none
+func orNone(items []string) string {
+	if len(items) == 0 {
+		return "none"
+	}
+	return strings.Join(items, ", ")
+}
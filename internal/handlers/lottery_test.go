@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/jasonholmberg/slashspot/internal/data"
+	"github.com/jasonholmberg/slashspot/internal/spot"
+	"github.com/jasonholmberg/slashspot/internal/util"
+	"github.com/nlopes/slack"
+	"gotest.tools/v3/assert"
+)
+
+// newLotteryHandler - a test handler whose draws close at 10:00, after the test clock's 9:30
+func newLotteryHandler(options ...Option) *Handler {
+	service := spot.NewService(data.NewMemoryStore()).In(testLocation).WithClock(util.FixedClock(testNow())).WithLottery(10*time.Hour, false)
+	return New(service, options...)
+}
+
+func Test_handleEnter(t *testing.T) {
+	today := dateSpecForTest(0)
+	tests := []struct {
+		name   string
+		userID string
+		params []string
+		want   string
+	}{
+		{name: "should enter the user in today's draw", userID: "U1", params: []string{"enter"}, want: fmt.Sprintf(EnterTemplate, today, today+" 10:00")},
+		{name: "should only enter the user once", userID: "U1", params: []string{"enter", "today"}, want: fmt.Sprintf(EnterAlreadyTemplate, today)},
+		{name: "should enter the user in a later draw", userID: "U1", params: []string{"enter", "tomorrow"}, want: fmt.Sprintf(EnterTemplate, dateSpecForTest(1), dateSpecForTest(1)+" 10:00")},
+		{name: "should not enter the user in a past draw", userID: "U1", params: []string{"enter", dateSpecForTest(-1)}, want: fmt.Sprintf(EnterClosedTemplate, dateSpecForTest(-1))},
+		{name: "should not understand a bad date", userID: "U1", params: []string{"enter", "someday"}, want: fmt.Sprintf(SpotDateFormatRegistrationErrorTemplate, "someday")},
+		{name: "should take the user out of the draw", userID: "U1", params: []string{"enter", "cancel", "tomorrow"}, want: fmt.Sprintf(EnterCancelledTemplate, dateSpecForTest(1))},
+		{name: "should say the user is not in the draw", userID: "U1", params: []string{"enter", "cancel", "tomorrow"}, want: fmt.Sprintf(EnterNotEnteredTemplate, dateSpecForTest(1))},
+	}
+	h := newLotteryHandler()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := h.handleEnter(&slack.SlashCommand{UserID: tt.userID}, tt.params)
+			assert.Equal(t, got, tt.want)
+		})
+	}
+
+	off := newTestHandler()
+	assert.Equal(t, off.handleEnter(&slack.SlashCommand{UserID: "U1"}, []string{"enter"}), LotteryOffText, "should say there is no draw")
+}
+
+func Test_claimBeforeDraw(t *testing.T) {
+	h := newLotteryHandler()
+	h.spots.Register("B1", "slackuser", testNow())
+	assert.Equal(t, h.claim("B1", "U1"), fmt.Sprintf(SpotDrawPendingTemplate, dateSpecForTest(0), dateSpecForTest(0)+" 10:00"))
+
+	h.spots.Draw(testNow())
+	assert.Equal(t, h.claim("B1", "U1"), fmt.Sprintf(SpotClaimedTemplate, "B1"), "should be first come first served after the draw")
+}
+
+func Test_handleAdminDraw(t *testing.T) {
+	admin := &slack.SlashCommand{UserID: "U1", UserName: "boss"}
+	h := newLotteryHandler(WithAdmins(NewAdminList("U1"))).forUser(commandUser(admin))
+	today := dateSpecForTest(0)
+	assert.Equal(t, h.handleAdmin(admin, []string{"admin", "draw"}), fmt.Sprintf(AdminNoDrawTemplate, today))
+
+	h.spots.Register("B1", "slackuser", testNow())
+	h.spots.Enter("U2", testNow())
+	draw, err := h.spots.Draw(testNow())
+	assert.NilError(t, err)
+	want := fmt.Sprintf(AdminDrawTemplate, "unweighted", today, today+" 09:30", draw.Seed, "U2", "B1 by U2")
+	assert.Equal(t, h.handleAdmin(admin, []string{"admin", "draw", "today"}), want)
+	assert.Equal(t, h.handleAdmin(admin, []string{"admin", "draw", "someday"}), fmt.Sprintf(SpotDateFormatRegistrationErrorTemplate, "someday"))
+}
@@ -0,0 +1,112 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/nlopes/slack"
+)
+
+const (
+	// ResponseEphemeral - only the user who sent the command sees the response
+	ResponseEphemeral = "ephemeral"
+
+	// ResponseInChannel - everyone in the channel the command was sent from sees the response
+	ResponseInChannel = "in_channel"
+)
+
+// message - a slack message sent in response to a command or an interaction
+type message struct {
+	ResponseType    string        `json:"response_type,omitempty"`
+	ReplaceOriginal bool          `json:"replace_original,omitempty"`
+	Text            string        `json:"text"`
+	Blocks          []slack.Block `json:"blocks,omitempty"`
+}
+
+// responseClient - posts messages to response URLs
+var responseClient = &http.Client{Timeout: 10 * time.Second}
+
+var (
+	// responseAttempts - how many times a message is posted to a response URL before giving up
+	responseAttempts = 3
+
+	// responseRetryWait - how long to wait before posting a message again, doubled after each attempt
+	responseRetryWait = 500 * time.Millisecond
+)
+
+// statusError - a response URL answered with an error status
+type statusError struct {
+	status     string
+	statusCode int
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("posting response got %v", e.status)
+}
+
+// temporary - returns true when posting again may work
+func (e *statusError) temporary() bool {
+	return e.statusCode == http.StatusTooManyRequests || e.statusCode >= http.StatusInternalServerError
+}
+
+// writeMessage - write the message as the JSON response to a request
+func writeMessage(w http.ResponseWriter, msg message) {
+	b, err := json.Marshal(msg)
+	if err != nil {
+		log.Print("Error marshalling response ", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(b)
+}
+
+// postMessage - post the message to a response URL slack gave with a command or an interaction
+func postMessage(url string, msg message) error {
+	b, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	resp, err := responseClient.Post(url, "application/json", bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return &statusError{status: resp.Status, statusCode: resp.StatusCode}
+	}
+	return nil
+}
+
+// postMessageWithRetries - post the message to a response URL, trying again when the post fails in a way that may
+// not last
+func postMessageWithRetries(url string, msg message) error {
+	wait := responseRetryWait
+	var err error
+	for attempt := 1; attempt <= responseAttempts; attempt++ {
+		if err = postMessage(url, msg); err == nil {
+			return nil
+		}
+		if se, ok := err.(*statusError); ok && !se.temporary() {
+			return err
+		}
+		if attempt < responseAttempts {
+			log.Printf("Error posting response, attempt %d of %d: %v", attempt, responseAttempts, err)
+			time.Sleep(wait)
+			wait *= 2
+		}
+	}
+	return err
+}
+
+func markdown(text string) *slack.TextBlockObject {
+	return slack.NewTextBlockObject(slack.MarkdownType, text, false, false)
+}
+
+func plainText(text string) *slack.TextBlockObject {
+	return slack.NewTextBlockObject(slack.PlainTextType, text, false, false)
+}
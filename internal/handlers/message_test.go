@@ -0,0 +1,40 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+)
+
+func Test_postMessageWithRetries(t *testing.T) {
+	defer func(wait time.Duration) { responseRetryWait = wait }(responseRetryWait)
+	responseRetryWait = time.Millisecond
+	tests := []struct {
+		name         string
+		statuses     []int
+		wantErr      bool
+		wantAttempts int
+	}{
+		{name: "should post once when it works", statuses: []int{http.StatusOK}, wantAttempts: 1},
+		{name: "should try again after a server error", statuses: []int{http.StatusBadGateway, http.StatusOK}, wantAttempts: 2},
+		{name: "should try again when rate limited", statuses: []int{http.StatusTooManyRequests, http.StatusOK}, wantAttempts: 2},
+		{name: "should give up after the last attempt", statuses: []int{http.StatusInternalServerError, http.StatusInternalServerError, http.StatusInternalServerError}, wantErr: true, wantAttempts: 3},
+		{name: "should not try again when the request is bad", statuses: []int{http.StatusNotFound}, wantErr: true, wantAttempts: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			attempts := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.statuses[attempts])
+				attempts++
+			}))
+			defer server.Close()
+			err := postMessageWithRetries(server.URL, message{Text: "hi"})
+			assert.Equal(t, err != nil, tt.wantErr)
+			assert.Equal(t, attempts, tt.wantAttempts)
+		})
+	}
+}
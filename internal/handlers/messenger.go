@@ -0,0 +1,93 @@
+package handlers
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/jasonholmberg/slashspot/internal/spot"
+	"github.com/nlopes/slack"
+)
+
+const (
+	// HeldMessageTemplate - Spot held direct message template
+	HeldMessageTemplate = "Spot %s opened on %s and is held for you until %s, claim it with `%s`"
+
+	// AssignedMessageTemplate - Spot claimed from the waitlist direct message template
+	AssignedMessageTemplate = "Spot %s opened on %s and has been claimed for you from the waitlist"
+
+	// WonMessageTemplate - Spot won in a draw direct message template
+	WonMessageTemplate = "You won spot %s in the draw for %s"
+
+	// LostMessageTemplate - Draw lost direct message template
+	LostMessageTemplate = "No spot for you in the draw for %s. Use `/spot want` to wait for one"
+
+	// messageQueue - how many messages can wait to be sent before they are dropped
+	messageQueue = 100
+)
+
+// SlackMessenger - a spot.Notifier that sends direct messages to users about spots given to them, by the waitlist
+// or a draw, and to the users who lost a draw
+type SlackMessenger struct {
+	chat   chat
+	events chan spot.Event
+}
+
+// NewSlackMessenger - A SlackMessenger constructor, the client needs a bot token with the chat:write scope
+func NewSlackMessenger(client *slack.Client) *SlackMessenger {
+	m := newSlackMessenger(slackChat{client: client})
+	go m.work()
+	return m
+}
+
+func newSlackMessenger(chat chat) *SlackMessenger {
+	return &SlackMessenger{
+		chat:   chat,
+		events: make(chan spot.Event, messageQueue),
+	}
+}
+
+// Notify - queue messages for held, assigned and drawn spots, other events are ignored
+func (m *SlackMessenger) Notify(e spot.Event) {
+	switch e.Kind {
+	case spot.SpotHeld, spot.SpotAssigned, spot.LotteryWon, spot.LotteryLost:
+	default:
+		return
+	}
+	select {
+	case m.events <- e:
+	default:
+		log.Printf("Too many messages waiting, dropped %v event for %d spots", e.Kind, len(e.Spots))
+	}
+}
+
+func (m *SlackMessenger) work() {
+	for e := range m.events {
+		m.send(e)
+	}
+}
+
+// send - tell the users what happened
+func (m *SlackMessenger) send(e spot.Event) {
+	for _, s := range e.Spots {
+		switch e.Kind {
+		case spot.SpotHeld:
+			m.post(s.HeldFor, fmt.Sprintf(HeldMessageTemplate, spotName(s.ID, s.Location), s.OpenDate, heldUntil(s), claimCommand(s)))
+		case spot.SpotAssigned:
+			m.post(s.ClaimedBy, fmt.Sprintf(AssignedMessageTemplate, s.ID, s.OpenDate))
+		case spot.LotteryWon:
+			m.post(s.ClaimedBy, fmt.Sprintf(WonMessageTemplate, s.ID, s.OpenDate))
+		}
+	}
+	if e.Kind == spot.LotteryLost {
+		for _, user := range e.Users {
+			m.post(user, fmt.Sprintf(LostMessageTemplate, e.Date))
+		}
+	}
+}
+
+// post - send a direct message to the user
+func (m *SlackMessenger) post(user string, text string) {
+	if _, err := m.chat.post(user, text); err != nil {
+		log.Printf("Error sending a message to %v: %v", user, err)
+	}
+}
@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/jasonholmberg/slashspot/internal/data"
+	"github.com/jasonholmberg/slashspot/internal/spot"
+	"gotest.tools/v3/assert"
+)
+
+func TestSlackMessenger_send(t *testing.T) {
+	today := dateSpecForTest(0)
+	tests := []struct {
+		name  string
+		event spot.Event
+		want  map[string]string
+	}{
+		{
+			name:  "should tell the user a spot is held for them",
+			event: spot.Event{Kind: spot.SpotHeld, Spots: []data.Spot{{ID: "B1", OpenDate: today, HeldFor: "U1", HeldUntil: "2020-01-08T09:45:00-06:00"}}},
+			want:  map[string]string{"1": fmt.Sprintf(HeldMessageTemplate, "B1", today, "2020-01-08 09:45", "/spot claim B1 "+today)},
+		},
+		{
+			name:  "should tell the user how to claim a held spot on its date at its location",
+			event: spot.Event{Kind: spot.SpotHeld, Spots: []data.Spot{{ID: "B4", OpenDate: dateSpecForTest(1), Location: "uptown", HeldFor: "U1", HeldUntil: "2020-01-08T09:45:00-06:00"}}},
+			want:  map[string]string{"1": fmt.Sprintf(HeldMessageTemplate, "B4 @uptown", dateSpecForTest(1), "2020-01-08 09:45", "/spot claim B4 @uptown "+dateSpecForTest(1))},
+		},
+		{
+			name:  "should tell the user a spot was claimed for them",
+			event: spot.Event{Kind: spot.SpotAssigned, Spots: []data.Spot{{ID: "B2", OpenDate: today, ClaimedBy: "U2"}}},
+			want:  map[string]string{"1": fmt.Sprintf(AssignedMessageTemplate, "B2", today)},
+		},
+		{
+			name:  "should tell the user they won a spot",
+			event: spot.Event{Kind: spot.LotteryWon, Spots: []data.Spot{{ID: "B3", OpenDate: today, ClaimedBy: "U3"}}},
+			want:  map[string]string{"1": fmt.Sprintf(WonMessageTemplate, "B3", today)},
+		},
+		{
+			name:  "should tell every loser of a draw",
+			event: spot.Event{Kind: spot.LotteryLost, Users: []string{"U4", "U5"}, Date: today},
+			want:  map[string]string{"1": fmt.Sprintf(LostMessageTemplate, today), "2": fmt.Sprintf(LostMessageTemplate, today)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &testChat{messages: make(map[string]string)}
+			newSlackMessenger(c).send(tt.event)
+			assert.DeepEqual(t, c.messages, tt.want)
+		})
+	}
+}
+
+func TestSlackMessenger_Notify(t *testing.T) {
+	m := newSlackMessenger(&testChat{messages: make(map[string]string)})
+	m.Notify(spot.Event{Kind: spot.SpotClaimed, Spots: []data.Spot{{ID: "B1"}}})
+	assert.Equal(t, len(m.events), 0, "should not message about claimed spots")
+	m.Notify(spot.Event{Kind: spot.LotteryLost, Users: []string{"U1"}})
+	assert.Equal(t, len(m.events), 1, "should message about lost draws")
+}
@@ -0,0 +1,229 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/jasonholmberg/slashspot/internal/data"
+	"github.com/jasonholmberg/slashspot/internal/util"
+	"github.com/nlopes/slack"
+)
+
+const (
+	// RegisterCallbackID - the callback of the register modal
+	RegisterCallbackID = "register"
+
+	// the blocks of the register modal, each block's element has the same action ID
+	spotBlockID  = "spot"
+	dateBlockID  = "date"
+	everyBlockID = "every"
+	untilBlockID = "until"
+
+	// maxSpotOptions - the most spots slack will show in a select, a text input is used for more
+	maxSpotOptions = 100
+
+	// PickASpot - The register modal was submitted without a spot
+	PickASpot = "Pick a spot to register"
+
+	// PickADate - The register modal was submitted without a date or days
+	PickADate = "Pick a date, or days of the week for the spot to recur"
+)
+
+// interactionTypeViewSubmission - a modal was submitted, the slack library does not know about modals yet
+const interactionTypeViewSubmission = slack.InteractionType("view_submission")
+
+type (
+	// viewSubmission - the parts of a view_submission payload spot uses
+	viewSubmission struct {
+		User slack.User `json:"user"`
+		Team slack.Team `json:"team"`
+		View struct {
+			CallbackID string `json:"callback_id"`
+			State      struct {
+				Values map[string]map[string]viewValue `json:"values"`
+			} `json:"state"`
+		} `json:"view"`
+	}
+
+	// viewValue - the value of an element in a submitted view
+	viewValue struct {
+		Value          string       `json:"value"`
+		SelectedDate   string       `json:"selected_date"`
+		SelectedOption *viewOption  `json:"selected_option"`
+		SelectedOpts   []viewOption `json:"selected_options"`
+	}
+
+	viewOption struct {
+		Value string `json:"value"`
+	}
+
+	// viewResponse - the response to a view_submission
+	viewResponse struct {
+		ResponseAction string            `json:"response_action"`
+		Errors         map[string]string `json:"errors,omitempty"`
+		View           *View             `json:"view,omitempty"`
+	}
+)
+
+// WithViews - open modals, like the register modal, with views
+func WithViews(views Views) Option {
+	return func(h *Handler) {
+		h.views = views
+	}
+}
+
+// openRegisterModal - open the register modal for the user who sent the command
+func (h *Handler) openRegisterModal(cmd *slack.SlashCommand) string {
+	if h.views == nil || cmd.TriggerID == "" {
+		return IDKBlank
+	}
+	if err := h.views.Open(cmd.TriggerID, h.registerView(cmd.UserID)); err != nil {
+		log.Print("Error opening the register modal ", err)
+		return IDKBlank
+	}
+	return ""
+}
+
+// registerView - the register modal, the user picks one of the spots they can register, a date or days for it to recur
+func (h *Handler) registerView(userID string) View {
+	var spotElement interface{} = TextInputElement{Type: "plain_text_input", ActionID: spotBlockID}
+	if ids, err := h.spots.RegistrableSpots(userID); err == nil && len(ids) > 0 && len(ids) <= maxSpotOptions {
+		var options []*slack.OptionBlockObject
+		for _, id := range ids {
+			options = append(options, slack.NewOptionBlockObject(id, plainText(id)))
+		}
+		spotElement = slack.NewOptionsSelectBlockElement(slack.OptTypeStatic, plainText("Spot"), spotBlockID, options...)
+	}
+	date := slack.NewDatePickerBlockElement(dateBlockID)
+	date.InitialDate = h.now().Format(util.SpotDateFormat)
+	var days []*slack.OptionBlockObject
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		days = append(days, slack.NewOptionBlockObject(util.FormatWeekdays([]time.Weekday{d}), plainText(d.String())))
+	}
+	every := slack.NewOptionsSelectBlockElement("multi_static_select", plainText("Days"), everyBlockID, days...)
+	until := slack.NewDatePickerBlockElement(untilBlockID)
+
+	dateBlock := NewInputBlock(dateBlockID, "Date", date)
+	dateBlock.Optional = true
+	everyBlock := NewInputBlock(everyBlockID, "Every", every)
+	everyBlock.Optional = true
+	everyBlock.Hint = plainText("Pick days to register the spot every week instead of on the date")
+	untilBlock := NewInputBlock(untilBlockID, "Until", until)
+	untilBlock.Optional = true
+	untilBlock.Hint = plainText("Leave empty to recur until further notice")
+	return View{
+		Type:       "modal",
+		CallbackID: RegisterCallbackID,
+		Title:      plainText("Register a spot"),
+		Submit:     plainText("Register"),
+		Close:      plainText("Cancel"),
+		Blocks: []interface{}{
+			NewInputBlock(spotBlockID, "Spot", spotElement),
+			dateBlock,
+			everyBlock,
+			untilBlock,
+		},
+	}
+}
+
+func (h *Handler) viewSubmissionHandler(payload []byte, w http.ResponseWriter) {
+	var submission viewSubmission
+	if err := json.Unmarshal(payload, &submission); err != nil {
+		log.Print(err)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	if submission.View.CallbackID != RegisterCallbackID {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	h = h.forUser(data.User{ID: submission.User.ID, Name: submission.User.Name, TeamID: submission.Team.ID})
+	b, err := json.Marshal(h.handleRegisterSubmission(submission.User.ID, submission.View.State.Values))
+	if err != nil {
+		log.Print(err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(b)
+}
+
+// handleRegisterSubmission - register the spot picked in the register modal. Problems are shown next to the field
+// they are about, otherwise the modal says what was registered.
+func (h *Handler) handleRegisterSubmission(userID string, values map[string]map[string]viewValue) viewResponse {
+	field := func(blockID string) viewValue {
+		return values[blockID][blockID]
+	}
+	invalid := func(blockID string, text string) viewResponse {
+		return viewResponse{ResponseAction: "errors", Errors: map[string]string{blockID: text}}
+	}
+	id := strings.TrimSpace(field(spotBlockID).Value)
+	if option := field(spotBlockID).SelectedOption; option != nil {
+		id = option.Value
+	}
+	if id == "" {
+		return invalid(spotBlockID, PickASpot)
+	}
+
+	var response string
+	if days := field(everyBlockID).SelectedOpts; len(days) > 0 {
+		var names []string
+		for _, day := range days {
+			names = append(names, day.Value)
+		}
+		weekdays, err := util.ParseWeekdays(strings.Join(names, util.DateListSep))
+		if err != nil {
+			return invalid(everyBlockID, fmt.Sprintf(SpotWeekdaysErrorTemplate, strings.Join(names, util.DateListSep)))
+		}
+		var until time.Time
+		if spec := field(untilBlockID).SelectedDate; spec != "" {
+			if until, err = util.ParseTime(spec, h.spots.Location()); err != nil {
+				return invalid(untilBlockID, fmt.Sprintf(SpotDateFormatRegistrationErrorTemplate, spec))
+			}
+			if util.BeforeNow(spec, h.now()) {
+				return invalid(untilBlockID, fmt.Sprintf(SpotPastDateRegistrationErrorTemplate, spec))
+			}
+		}
+		rec, err := h.spots.RegisterRecurring(id, userID, weekdays, until)
+		if refused, ok := refusedRegistration(id, err); ok {
+			return invalid(spotBlockID, refused)
+		}
+		if err != nil {
+			return invalid(spotBlockID, fmt.Sprintf(SpotRecurringDupeTemplate, id, h.userName(rec.RegisteredBy)))
+		}
+		response = fmt.Sprintf(SpotRecurringRegisteredTemplate, rec.ID, util.FormatWeekdays(rec.Weekdays), recurrenceUntil(rec))
+	} else {
+		spec := field(dateBlockID).SelectedDate
+		if spec == "" {
+			return invalid(dateBlockID, PickADate)
+		}
+		openDate, err := util.ParseTime(spec, h.spots.Location())
+		if err != nil {
+			return invalid(dateBlockID, fmt.Sprintf(SpotDateFormatRegistrationErrorTemplate, spec))
+		}
+		if util.BeforeNow(spec, h.now()) {
+			return invalid(dateBlockID, fmt.Sprintf(SpotPastDateRegistrationErrorTemplate, spec))
+		}
+		newSpot, err := h.spots.Register(id, userID, openDate)
+		if refused, ok := refusedRegistration(id, err); ok {
+			return invalid(spotBlockID, refused)
+		}
+		if err != nil {
+			return invalid(spotBlockID, fmt.Sprintf(SpotDupeRegistrationErrorTemplate, id, h.userName(newSpot.RegisteredBy)))
+		}
+		response = fmt.Sprintf(SpotRangeRegisteredTemplate, newSpot.ID, newSpot.OpenDate)
+	}
+	return viewResponse{
+		ResponseAction: "update",
+		View: &View{
+			Type:   "modal",
+			Title:  plainText("Register a spot"),
+			Close:  plainText("Done"),
+			Blocks: []interface{}{slack.NewSectionBlock(markdown(response), nil, nil)},
+		},
+	}
+}
@@ -0,0 +1,179 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/jasonholmberg/slashspot/internal/data"
+	"github.com/nlopes/slack"
+	"gotest.tools/v3/assert"
+)
+
+// testViews - Views that remember the views opened instead of opening them
+type testViews struct {
+	opened []View
+	err    error
+}
+
+func (v *testViews) Open(triggerID string, view View) error {
+	if v.err != nil {
+		return v.err
+	}
+	v.opened = append(v.opened, view)
+	return nil
+}
+
+func Test_openRegisterModal(t *testing.T) {
+	tests := []struct {
+		name       string
+		views      *testViews
+		triggerID  string
+		want       string
+		wantOpened int
+	}{
+		{name: "should open the register modal", views: &testViews{}, triggerID: "trigger", want: "", wantOpened: 1},
+		{name: "should not know what to do without a trigger", views: &testViews{}, want: ephemeral(IDKBlank)},
+		{name: "should not know what to do when the modal does not open", views: &testViews{err: errors.New("expired_trigger_id")}, triggerID: "trigger", want: ephemeral(IDKBlank)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newTestHandler(WithViews(tt.views))
+			rr := httptest.NewRecorder()
+			h.spotCommandHandler(&slack.SlashCommand{Text: "reg", UserID: "U1", TriggerID: tt.triggerID}, rr)
+			assert.Equal(t, rr.Body.String(), tt.want)
+			assert.Equal(t, len(tt.views.opened), tt.wantOpened)
+		})
+	}
+}
+
+func Test_registerView(t *testing.T) {
+	h := newTestHandler()
+	view := h.registerView("U1")
+	assert.Equal(t, view.CallbackID, RegisterCallbackID)
+	_, ok := view.Blocks[0].(InputBlock).Element.(TextInputElement)
+	assert.Assert(t, ok, "should ask for the spot without a catalog")
+
+	h.spots.ImportCatalog([]data.CatalogSpot{{ID: "B1", Holder: "U1"}, {ID: "B2", Holder: "U2"}, {ID: "B3"}})
+	view = h.registerView("U1")
+	spots, ok := view.Blocks[0].(InputBlock).Element.(*slack.SelectBlockElement)
+	assert.Assert(t, ok, "should pick the spot from the catalog")
+	var ids []string
+	for _, option := range spots.Options {
+		ids = append(ids, option.Value)
+	}
+	assert.DeepEqual(t, ids, []string{"B1"})
+}
+
+// submission - the state of a submitted register modal
+func submission(spot string, date string, days []string, until string) map[string]map[string]viewValue {
+	values := map[string]map[string]viewValue{
+		spotBlockID:  {spotBlockID: {SelectedOption: &viewOption{Value: spot}}},
+		dateBlockID:  {dateBlockID: {SelectedDate: date}},
+		everyBlockID: {everyBlockID: {}},
+		untilBlockID: {untilBlockID: {SelectedDate: until}},
+	}
+	if spot == "" {
+		values[spotBlockID] = map[string]viewValue{spotBlockID: {}}
+	}
+	for _, day := range days {
+		every := values[everyBlockID][everyBlockID]
+		every.SelectedOpts = append(every.SelectedOpts, viewOption{Value: day})
+		values[everyBlockID][everyBlockID] = every
+	}
+	return values
+}
+
+func Test_handleRegisterSubmission(t *testing.T) {
+	tests := []struct {
+		name       string
+		values     map[string]map[string]viewValue
+		wantErrors map[string]string
+		want       string
+	}{
+		{
+			name:   "should register the spot on the date",
+			values: submission("B1", dateSpecForTest(1), nil, ""),
+			want:   fmt.Sprintf(SpotRangeRegisteredTemplate, "B1", dateSpecForTest(1)),
+		},
+		{
+			name:       "should say the spot is already registered",
+			values:     submission("B1", dateSpecForTest(1), nil, ""),
+			wantErrors: map[string]string{spotBlockID: fmt.Sprintf(SpotDupeRegistrationErrorTemplate, "B1", "U1")},
+		},
+		{
+			name:   "should register a typed spot",
+			values: map[string]map[string]viewValue{spotBlockID: {spotBlockID: {Value: " B2 "}}, dateBlockID: {dateBlockID: {SelectedDate: dateSpecForTest(0)}}},
+			want:   fmt.Sprintf(SpotRangeRegisteredTemplate, "B2", dateSpecForTest(0)),
+		},
+		{
+			name:   "should register the spot every week",
+			values: submission("B3", dateSpecForTest(0), []string{"mon", "wed"}, dateSpecForTest(30)),
+			want:   fmt.Sprintf(SpotRecurringRegisteredTemplate, "B3", "mon,wed", dateSpecForTest(30)),
+		},
+		{
+			name:       "should want a spot",
+			values:     submission("", dateSpecForTest(1), nil, ""),
+			wantErrors: map[string]string{spotBlockID: PickASpot},
+		},
+		{
+			name:       "should want a date or days",
+			values:     submission("B4", "", nil, ""),
+			wantErrors: map[string]string{dateBlockID: PickADate},
+		},
+		{
+			name:       "should not register in the past",
+			values:     submission("B4", dateSpecForTest(-1), nil, ""),
+			wantErrors: map[string]string{dateBlockID: fmt.Sprintf(SpotPastDateRegistrationErrorTemplate, dateSpecForTest(-1))},
+		},
+		{
+			name:       "should not recur until the past",
+			values:     submission("B4", "", []string{"fri"}, dateSpecForTest(-1)),
+			wantErrors: map[string]string{untilBlockID: fmt.Sprintf(SpotPastDateRegistrationErrorTemplate, dateSpecForTest(-1))},
+		},
+		{
+			name:       "should not register a spot held by someone else",
+			values:     submission("H1", dateSpecForTest(1), nil, ""),
+			wantErrors: map[string]string{spotBlockID: fmt.Sprintf(SpotNotHolderTemplate, "H1")},
+		},
+	}
+	h := newTestHandler()
+	h.spots.ImportCatalog([]data.CatalogSpot{{ID: "B1"}, {ID: "B2"}, {ID: "B3"}, {ID: "B4"}, {ID: "H1", Holder: "U2"}})
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := h.handleRegisterSubmission("U1", tt.values)
+			if tt.wantErrors != nil {
+				assert.Equal(t, got.ResponseAction, "errors")
+				assert.DeepEqual(t, got.Errors, tt.wantErrors)
+				return
+			}
+			assert.Equal(t, got.ResponseAction, "update")
+			assert.Equal(t, got.View.Blocks[0].(*slack.SectionBlock).Text.Text, tt.want)
+		})
+	}
+}
+
+func TestInteractionHandlerViewSubmission(t *testing.T) {
+	h := newTestHandler()
+	payload, err := json.Marshal(map[string]interface{}{
+		"type": interactionTypeViewSubmission,
+		"user": map[string]string{"id": "U1", "name": "ponyboy"},
+		"team": map[string]string{"id": "T1"},
+		"view": map[string]interface{}{
+			"callback_id": RegisterCallbackID,
+			"state":       map[string]interface{}{"values": submission("B1", dateSpecForTest(-1), nil, "")},
+		},
+	})
+	assert.NilError(t, err)
+
+	rr := httptest.NewRecorder()
+	h.InteractionHandler(rr, signedRequest(t, "/interactions", url.Values{"payload": {string(payload)}}))
+	assert.Equal(t, rr.Code, http.StatusOK)
+	var got viewResponse
+	assert.NilError(t, json.Unmarshal(rr.Body.Bytes(), &got))
+	assert.DeepEqual(t, got.Errors, map[string]string{dateBlockID: fmt.Sprintf(SpotPastDateRegistrationErrorTemplate, dateSpecForTest(-1))})
+}
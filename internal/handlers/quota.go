@@ -0,0 +1,45 @@
+package handlers
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jasonholmberg/slashspot/internal/spot"
+	"github.com/jasonholmberg/slashspot/internal/util"
+)
+
+const (
+	// QuotaPerWeekTemplate - Too many spots claimed in a week template
+	QuotaPerWeekTemplate = "You can claim %d spots a week and have claimed them all for that week, you can claim spots again from %s"
+
+	// QuotaConcurrentTemplate - Too many spots claimed at once template
+	QuotaConcurrentTemplate = "You can have %d spots claimed at a time, release one or claim again from %s"
+
+	// QuotaCooldownTemplate - Spot claimed too soon after the last one template
+	QuotaCooldownTemplate = "You have to wait %s after claiming a spot, you can claim again at %s"
+)
+
+// quotaMessage - which quota rule stopped a claim and when the user can claim again
+func quotaMessage(err spot.QuotaError) string {
+	switch err.Rule {
+	case spot.QuotaPerWeek:
+		return fmt.Sprintf(QuotaPerWeekTemplate, err.Quota.PerWeek, err.Until.Format(util.SpotDateFormat))
+	case spot.QuotaConcurrent:
+		return fmt.Sprintf(QuotaConcurrentTemplate, err.Quota.Concurrent, err.Until.Format(util.SpotDateFormat))
+	default:
+		return fmt.Sprintf(QuotaCooldownTemplate, shortDuration(err.Quota.Cooldown), err.Until.Format(shortTimeFormat))
+	}
+}
+
+// shortDuration - the duration without the zero minutes and seconds, like 2h instead of 2h0m0s
+func shortDuration(d time.Duration) string {
+	short := d.String()
+	if strings.HasSuffix(short, "m0s") {
+		short = strings.TrimSuffix(short, "0s")
+	}
+	if strings.HasSuffix(short, "h0m") {
+		short = strings.TrimSuffix(short, "0m")
+	}
+	return short
+}
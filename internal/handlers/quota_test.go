@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/jasonholmberg/slashspot/internal/data"
+	"github.com/jasonholmberg/slashspot/internal/spot"
+	"github.com/jasonholmberg/slashspot/internal/util"
+	"gotest.tools/v3/assert"
+)
+
+func Test_claimOverQuota(t *testing.T) {
+	service := spot.NewService(data.NewMemoryStore()).In(testLocation).WithClock(util.FixedClock(testNow()))
+	h := New(service.WithQuota(spot.Quota{Cooldown: 90 * time.Minute}))
+	h.spots.Register("B1", "slackuser", testNow())
+	h.spots.Register("B2", "slackuser", testNow())
+	assert.Equal(t, h.claim("B1", "U1"), fmt.Sprintf(SpotClaimedTemplate, "B1"))
+	assert.Equal(t, h.claim("B2", "U1"), fmt.Sprintf(QuotaCooldownTemplate, "1h30m", dateSpecForTest(0)+" 11:00"))
+}
+
+func Test_quotaMessage(t *testing.T) {
+	monday := time.Date(2020, 1, 13, 0, 0, 0, 0, testLocation)
+	tests := []struct {
+		name string
+		err  spot.QuotaError
+		want string
+	}{
+		{
+			name: "should say when the week's claims are used up",
+			err:  spot.QuotaError{Rule: spot.QuotaPerWeek, Quota: spot.Quota{PerWeek: 3}, Until: monday},
+			want: fmt.Sprintf(QuotaPerWeekTemplate, 3, "2020-01-13"),
+		},
+		{
+			name: "should say when too many spots are claimed at once",
+			err:  spot.QuotaError{Rule: spot.QuotaConcurrent, Quota: spot.Quota{Concurrent: 2}, Until: monday},
+			want: fmt.Sprintf(QuotaConcurrentTemplate, 2, "2020-01-13"),
+		},
+		{
+			name: "should say when the cooldown is over",
+			err:  spot.QuotaError{Rule: spot.QuotaCooldown, Quota: spot.Quota{Cooldown: 2 * time.Hour}, Until: monday.Add(8 * time.Hour)},
+			want: fmt.Sprintf(QuotaCooldownTemplate, "2h", "2020-01-13 08:00"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, quotaMessage(tt.err), tt.want)
+		})
+	}
+}
@@ -0,0 +1,140 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jasonholmberg/slashspot/internal/spot"
+	"github.com/jasonholmberg/slashspot/internal/util"
+	"github.com/nlopes/slack"
+)
+
+const (
+	// StatsTemplate - Spot usage template
+	StatsTemplate = "From %s to %s %d spot days were shared and %d were claimed (%d%%)\n%s\n%s"
+
+	// StatsSpotTemplate - Spot usage of one spot template
+	StatsSpotTemplate = "- %s was claimed %d of %d days"
+
+	// StatsYouTemplate - The user's own usage template
+	StatsYouTemplate = "You shared %d spot days, %d were claimed, and claimed %d"
+
+	// NoStatsTemplate - No spots shared in the period template
+	NoStatsTemplate = "No spots were shared from %s to %s"
+
+	// StatsPeriodErrorTemplate - Unknown period template
+	StatsPeriodErrorTemplate = "I can show the stats of this week or month, not %s"
+
+	// StatsErrorTemplate - Stats error template
+	StatsErrorTemplate = "Unable to load the stats from %s to %s"
+
+	// AdminExportTemplate - Admin CSV export template
+	AdminExportTemplate = "Spot usage from %s to %s:\n```\n%s```"
+
+	// statsWeek, statsMonth - the periods stats can be shown for
+	statsWeek  = "week"
+	statsMonth = "month"
+)
+
+// csvHeader - the columns of the CSV export
+var csvHeader = []string{"type", "location", "spot", "user", "shared", "claimed", "utilization"}
+
+// statsPeriod - the first and last dates of this week, Monday to Sunday, or this month. Week when no period is given.
+func statsPeriod(params []string, now time.Time) (time.Time, time.Time, error) {
+	today, _ := util.ParseTime(now.Format(util.SpotDateFormat), now.Location())
+	period := statsWeek
+	if len(params) > 0 && params[0] != "" {
+		period = strings.ToLower(params[0])
+	}
+	switch period {
+	case statsWeek:
+		monday := today.AddDate(0, 0, -((int(today.Weekday()) + 6) % 7))
+		return monday, monday.AddDate(0, 0, 6), nil
+	case statsMonth:
+		first := today.AddDate(0, 0, 1-today.Day())
+		return first, first.AddDate(0, 1, -1), nil
+	}
+	return time.Time{}, time.Time{}, fmt.Errorf("unknown period %v", period)
+}
+
+// handleStats - stats [week|month]
+func (h *Handler) handleStats(cmd *slack.SlashCommand, params []string) string {
+	from, to, err := statsPeriod(params[1:], h.now())
+	if err != nil {
+		return fmt.Sprintf(StatsPeriodErrorTemplate, strings.Join(params[1:], " "))
+	}
+	first, last := from.Format(util.SpotDateFormat), to.Format(util.SpotDateFormat)
+	st, err := h.spots.Stats(from, to)
+	if err != nil {
+		return fmt.Sprintf(StatsErrorTemplate, first, last)
+	}
+	if st.Shared() == 0 {
+		return fmt.Sprintf(NoStatsTemplate, first, last)
+	}
+	var lines []string
+	for _, u := range st.Spots {
+		lines = append(lines, fmt.Sprintf(StatsSpotTemplate, spotName(u.ID, u.Location), u.Claimed, u.Shared))
+	}
+	you := st.User(cmd.UserID)
+	return fmt.Sprintf(StatsTemplate, first, last, st.Shared(), st.Claimed(), percent(st.Claimed(), st.Shared()),
+		strings.Join(lines, "\n"), fmt.Sprintf(StatsYouTemplate, you.Shared, you.SharedClaimed, you.Claimed))
+}
+
+// handleAdminExport - admin export [week|month]
+func (h *Handler) handleAdminExport(params []string) string {
+	from, to, err := statsPeriod(params[2:], h.now())
+	if err != nil {
+		return fmt.Sprintf(StatsPeriodErrorTemplate, strings.Join(params[2:], " "))
+	}
+	first, last := from.Format(util.SpotDateFormat), to.Format(util.SpotDateFormat)
+	st, err := h.spots.Stats(from, to)
+	if err != nil {
+		return fmt.Sprintf(StatsErrorTemplate, first, last)
+	}
+	out, err := h.statsCSV(st)
+	if err != nil {
+		return fmt.Sprintf(AdminErrorTemplate, "export")
+	}
+	return fmt.Sprintf(AdminExportTemplate, first, last, out)
+}
+
+// statsCSV - the usage of each spot, of each holder's spots and of each claimant as CSV. Utilization is the share of
+// the spot days shared that were claimed.
+func (h *Handler) statsCSV(st spot.Stats) (string, error) {
+	var b bytes.Buffer
+	w := csv.NewWriter(&b)
+	rows := [][]string{csvHeader}
+	for _, u := range st.Spots {
+		rows = append(rows, []string{"spot", u.Location, u.ID, "",
+			strconv.Itoa(u.Shared), strconv.Itoa(u.Claimed), utilization(u.Claimed, u.Shared)})
+	}
+	for _, u := range st.Users {
+		if u.Shared > 0 {
+			rows = append(rows, []string{"holder", h.spots.Where(), "", h.userName(u.User),
+				strconv.Itoa(u.Shared), strconv.Itoa(u.SharedClaimed), utilization(u.SharedClaimed, u.Shared)})
+		}
+	}
+	for _, u := range st.Users {
+		if u.Claimed > 0 {
+			rows = append(rows, []string{"claimant", h.spots.Where(), "", h.userName(u.User), "", strconv.Itoa(u.Claimed), ""})
+		}
+	}
+	if err := w.WriteAll(rows); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// percent - the share of claimed in shared as a whole percentage
+func percent(claimed int, shared int) int {
+	return int(spot.Utilization(claimed, shared)*100 + 0.5)
+}
+
+// utilization - the share of claimed in shared with two decimals, like 0.75
+func utilization(claimed int, shared int) string {
+	return strconv.FormatFloat(spot.Utilization(claimed, shared), 'f', 2, 64)
+}
@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/nlopes/slack"
+	"gotest.tools/v3/assert"
+)
+
+func Test_statsPeriod(t *testing.T) {
+	tests := []struct {
+		name     string
+		params   []string
+		wantFrom string
+		wantTo   string
+		wantErr  bool
+	}{
+		{name: "should default to this week", wantFrom: "2020-01-06", wantTo: "2020-01-12"},
+		{name: "should give this week", params: []string{"Week"}, wantFrom: "2020-01-06", wantTo: "2020-01-12"},
+		{name: "should give this month", params: []string{"month"}, wantFrom: "2020-01-01", wantTo: "2020-01-31"},
+		{name: "should not give other periods", params: []string{"year"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			from, to, err := statsPeriod(tt.params, testNow())
+			assert.Equal(t, err != nil, tt.wantErr)
+			if !tt.wantErr {
+				assert.Equal(t, from.Format("2006-01-02"), tt.wantFrom)
+				assert.Equal(t, to.Format("2006-01-02"), tt.wantTo)
+			}
+		})
+	}
+	sunday := time.Date(2020, 1, 12, 18, 0, 0, 0, testLocation)
+	from, _, _ := statsPeriod(nil, sunday)
+	assert.Equal(t, from.Format("2006-01-02"), "2020-01-06", "should start the week on the Monday before a Sunday")
+}
+
+func Test_handleStats(t *testing.T) {
+	h := newTestHandler()
+	cmd := &slack.SlashCommand{UserID: "U1", UserName: "slackuser"}
+	msg, _ := h.spotCommand(&slack.SlashCommand{Text: "stats", UserID: "U1", UserName: "slackuser"})
+	assert.Equal(t, msg.Text, fmt.Sprintf(NoStatsTemplate, "2020-01-06", "2020-01-12"))
+
+	h.spots.Register("B1", "U1", testNow())
+	h.spots.Register("B2", "U1", testNow())
+	h.spots.Claim("B1", "U2")
+	msg, _ = h.spotCommand(&slack.SlashCommand{Text: "stats month", UserID: "U1", UserName: "slackuser"})
+	want := fmt.Sprintf(StatsTemplate, "2020-01-01", "2020-01-31", 2, 1, 50,
+		fmt.Sprintf(StatsSpotTemplate, "B1", 1, 1)+"\n"+fmt.Sprintf(StatsSpotTemplate, "B2", 0, 1),
+		fmt.Sprintf(StatsYouTemplate, 2, 1, 0))
+	assert.Equal(t, msg.Text, want)
+
+	assert.Equal(t, h.handleStats(cmd, []string{"stats", "year"}), fmt.Sprintf(StatsPeriodErrorTemplate, "year"))
+}
+
+func Test_handleAdminExport(t *testing.T) {
+	h := newTestHandler(WithAdmins(NewAdminList("U9")))
+	h.spots.RememberUser(commandUser(&slack.SlashCommand{UserID: "U1", UserName: "slackuser"}))
+	h.spots.RememberUser(commandUser(&slack.SlashCommand{UserID: "U2", UserName: "ponyboy"}))
+	h.spots.Register("B1", "U1", testNow())
+	h.spots.Register("B2", "U1", testNow())
+	h.spots.Claim("B1", "U2")
+
+	msg, _ := h.spotCommand(&slack.SlashCommand{Text: "admin export", UserID: "U9", UserName: "boss"})
+	csv := "type,location,spot,user,shared,claimed,utilization\n" +
+		"spot,,B1,,1,1,1.00\n" +
+		"spot,,B2,,1,0,0.00\n" +
+		"holder,,,slackuser,2,1,0.50\n" +
+		"claimant,,,ponyboy,,1,\n"
+	assert.Equal(t, msg.Text, fmt.Sprintf(AdminExportTemplate, "2020-01-06", "2020-01-12", csv))
+
+	msg, _ = h.spotCommand(&slack.SlashCommand{Text: "admin export", UserID: "U1", UserName: "slackuser"})
+	assert.Equal(t, msg.Text, NotAdmin, "should only export for admins")
+}
@@ -0,0 +1,74 @@
+package handlers
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/nlopes/slack"
+)
+
+// ErrNoTimezone - returned when a slack user has not set a timezone
+var ErrNoTimezone = errors.New("no timezone")
+
+// UserTimezones - finds the timezone a slack user has set
+type UserTimezones interface {
+	Location(userID string) (*time.Location, error)
+}
+
+// SlackUserTimezones - UserTimezones looked up with the slack users.info API and cached for an hour
+type SlackUserTimezones struct {
+	client *slack.Client
+	lock   sync.Mutex
+	cache  map[string]cachedLocation
+}
+
+type cachedLocation struct {
+	loc     *time.Location
+	expires time.Time
+}
+
+// userTimezoneTTL - how long a user's timezone is cached
+const userTimezoneTTL = time.Hour
+
+// NewSlackUserTimezones - A SlackUserTimezones constructor
+func NewSlackUserTimezones(client *slack.Client) *SlackUserTimezones {
+	return &SlackUserTimezones{
+		client: client,
+		cache:  make(map[string]cachedLocation),
+	}
+}
+
+// Location - the user's timezone, ErrNoTimezone when they have not set one so the team's timezone is used
+func (s *SlackUserTimezones) Location(userID string) (*time.Location, error) {
+	s.lock.Lock()
+	cached, ok := s.cache[userID]
+	s.lock.Unlock()
+	if ok && time.Now().Before(cached.expires) {
+		return cached.location()
+	}
+	user, err := s.client.GetUserInfo(userID)
+	if err != nil {
+		return nil, err
+	}
+	// An empty name loads UTC, which is not the user's timezone
+	var loc *time.Location
+	if user.TZ != "" {
+		if loc, err = time.LoadLocation(user.TZ); err != nil {
+			return nil, err
+		}
+	}
+	cached = cachedLocation{loc: loc, expires: time.Now().Add(userTimezoneTTL)}
+	s.lock.Lock()
+	s.cache[userID] = cached
+	s.lock.Unlock()
+	return cached.location()
+}
+
+// location - the cached timezone, ErrNoTimezone when the user had none
+func (c cachedLocation) location() (*time.Location, error) {
+	if c.loc == nil {
+		return nil, ErrNoTimezone
+	}
+	return c.loc, nil
+}
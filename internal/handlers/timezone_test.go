@@ -0,0 +1,46 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/nlopes/slack"
+	"gotest.tools/v3/assert"
+)
+
+func TestSlackUserTimezones_Location(t *testing.T) {
+	tests := []struct {
+		name      string
+		response  string
+		want      string
+		wantErr   string
+		wantCalls int
+	}{
+		{name: "should return the user's timezone", response: `{"ok":true,"user":{"id":"U1","tz":"Asia/Tokyo"}}`, want: "Asia/Tokyo", wantCalls: 1},
+		{name: "should not return UTC when the user has no timezone", response: `{"ok":true,"user":{"id":"U1","tz":""}}`, wantErr: ErrNoTimezone.Error(), wantCalls: 1},
+		{name: "should return the slack error", response: `{"ok":false,"error":"user_not_found"}`, wantErr: "user_not_found", wantCalls: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				calls++
+				w.Write([]byte(tt.response))
+			}))
+			defer server.Close()
+			timezones := NewSlackUserTimezones(slack.New("xoxb-test", slack.OptionAPIURL(server.URL+"/")))
+
+			for i := 0; i < 2; i++ {
+				got, err := timezones.Location("U1")
+				if tt.wantErr != "" {
+					assert.Error(t, err, tt.wantErr)
+				} else {
+					assert.NilError(t, err)
+					assert.Equal(t, got.String(), tt.want)
+				}
+			}
+			assert.Equal(t, calls, tt.wantCalls)
+		})
+	}
+}
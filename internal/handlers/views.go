@@ -0,0 +1,104 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/nlopes/slack"
+)
+
+// slackViewsOpenURL - the slack API method that opens a modal
+const slackViewsOpenURL = "https://slack.com/api/views.open"
+
+type (
+	// View - a slack modal
+	View struct {
+		Type       string                 `json:"type"`
+		CallbackID string                 `json:"callback_id,omitempty"`
+		Title      *slack.TextBlockObject `json:"title"`
+		Submit     *slack.TextBlockObject `json:"submit,omitempty"`
+		Close      *slack.TextBlockObject `json:"close,omitempty"`
+		Blocks     []interface{}          `json:"blocks"`
+	}
+
+	// InputBlock - a block in a modal that collects a value
+	InputBlock struct {
+		Type     string                 `json:"type"`
+		BlockID  string                 `json:"block_id"`
+		Label    *slack.TextBlockObject `json:"label"`
+		Element  interface{}            `json:"element"`
+		Hint     *slack.TextBlockObject `json:"hint,omitempty"`
+		Optional bool                   `json:"optional,omitempty"`
+	}
+
+	// TextInputElement - a plain text input in a modal
+	TextInputElement struct {
+		Type        string                 `json:"type"`
+		ActionID    string                 `json:"action_id"`
+		Placeholder *slack.TextBlockObject `json:"placeholder,omitempty"`
+	}
+
+	// Views - opens slack modals
+	Views interface {
+		Open(triggerID string, view View) error
+	}
+
+	// SlackViews - Views opened with the slack views.open API
+	SlackViews struct {
+		token  string
+		url    string
+		client *http.Client
+	}
+)
+
+// NewInputBlock - An InputBlock constructor
+func NewInputBlock(blockID string, label string, element interface{}) InputBlock {
+	return InputBlock{
+		Type:    "input",
+		BlockID: blockID,
+		Label:   plainText(label),
+		Element: element,
+	}
+}
+
+// NewSlackViews - A SlackViews constructor, the token is a bot token
+func NewSlackViews(token string) *SlackViews {
+	return &SlackViews{
+		token:  token,
+		url:    slackViewsOpenURL,
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Open - open the modal for the user who triggered it
+func (s *SlackViews) Open(triggerID string, view View) error {
+	b, err := json.Marshal(struct {
+		TriggerID string `json:"trigger_id"`
+		View      View   `json:"view"`
+	}{triggerID, view})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, s.url, bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	req.Header.Set("Authorization", "Bearer "+s.token)
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	var result slack.SlackResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return err
+	}
+	if !result.Ok {
+		return errors.New(result.Error)
+	}
+	return nil
+}
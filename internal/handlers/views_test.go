@@ -0,0 +1,48 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestSlackViews_Open(t *testing.T) {
+	tests := []struct {
+		name     string
+		response string
+		wantErr  string
+	}{
+		{name: "should open the view", response: `{"ok":true}`},
+		{name: "should return the slack error", response: `{"ok":false,"error":"expired_trigger_id"}`, wantErr: "expired_trigger_id"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got struct {
+				TriggerID string `json:"trigger_id"`
+				View      View   `json:"view"`
+			}
+			var auth string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				auth = r.Header.Get("Authorization")
+				json.NewDecoder(r.Body).Decode(&got)
+				w.Write([]byte(tt.response))
+			}))
+			defer server.Close()
+			views := NewSlackViews("xoxb-test")
+			views.url = server.URL
+
+			err := views.Open("trigger", View{Type: "modal", CallbackID: RegisterCallbackID, Title: plainText("Register a spot")})
+			if tt.wantErr != "" {
+				assert.Error(t, err, tt.wantErr)
+			} else {
+				assert.NilError(t, err)
+			}
+			assert.Equal(t, auth, "Bearer xoxb-test")
+			assert.Equal(t, got.TriggerID, "trigger")
+			assert.Equal(t, got.View.CallbackID, RegisterCallbackID)
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package handlers
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jasonholmberg/slashspot/internal/data"
+	"github.com/jasonholmberg/slashspot/internal/spot"
+	"github.com/jasonholmberg/slashspot/internal/util"
+	"github.com/nlopes/slack"
+)
+
+const (
+	// WantWaitingTemplate - Waitlist joined template
+	WantWaitingTemplate = "You are number %d on the waitlist for %s. I'll let you know when a spot opens"
+
+	// WantHeldTemplate - Spot held for a user right away template
+	WantHeldTemplate = "Spot %s is open on %s and held for you until %s, claim it with `%s`"
+
+	// WantAssignedTemplate - Spot claimed for a user right away template
+	WantAssignedTemplate = "Spot %s on %s is yours"
+
+	// WantAlreadyWaitingTemplate - Waitlist joined twice template
+	WantAlreadyWaitingTemplate = "You are already on the waitlist for %s"
+
+	// WantErrorTemplate - Waitlist error template
+	WantErrorTemplate = "You could not be put on the waitlist for %s"
+
+	// WantCancelledTemplate - Waitlist left template
+	WantCancelledTemplate = "You are off the waitlist for %s"
+
+	// WantNotWaitingTemplate - Waitlist left without being on it template
+	WantNotWaitingTemplate = "You are not on the waitlist for %s"
+
+	// SpotHeldTemplate - Spot held for someone else on the waitlist template
+	SpotHeldTemplate = "The spot %s is held for %s until %s"
+)
+
+// handleWant - want [date] or want cancel [date]
+func (h *Handler) handleWant(cmd *slack.SlashCommand, params []string) string {
+	cancel := len(params) > 1 && strings.ToLower(params[1]) == "cancel"
+	spec := params[1:]
+	if cancel {
+		spec = params[2:]
+	}
+	date := h.now()
+	if len(spec) > 0 {
+		var err error
+		if date, err = util.ParseDate(strings.Join(spec, " "), h.now()); err != nil {
+			return fmt.Sprintf(SpotDateFormatRegistrationErrorTemplate, strings.Join(spec, " "))
+		}
+	}
+	day := date.Format(util.SpotDateFormat)
+	if cancel {
+		if err := h.spots.CancelWant(cmd.UserID, date); err != nil {
+			return fmt.Sprintf(WantNotWaitingTemplate, day)
+		}
+		return fmt.Sprintf(WantCancelledTemplate, day)
+	}
+	if util.BeforeNow(day, h.now()) {
+		return fmt.Sprintf(SpotPastDateRegistrationErrorTemplate, day)
+	}
+	result, err := h.spots.Want(cmd.UserID, date)
+	if err == spot.ErrAlreadyWaiting {
+		return fmt.Sprintf(WantAlreadyWaitingTemplate, day)
+	}
+	if err != nil {
+		return fmt.Sprintf(WantErrorTemplate, day)
+	}
+	if given := result.Spot; given.ID != "" {
+		if given.IsClaimed() {
+			return fmt.Sprintf(WantAssignedTemplate, given.ID, given.OpenDate)
+		}
+		return fmt.Sprintf(WantHeldTemplate, spotName(given.ID, given.Location), given.OpenDate, heldUntil(given), claimCommand(given))
+	}
+	return fmt.Sprintf(WantWaitingTemplate, result.Position, day)
+}
+
+// claimCommand - the command that claims the spot on its date at its location
+func claimCommand(s data.Spot) string {
+	return fmt.Sprintf("/spot claim %s %s", spotName(s.ID, s.Location), s.OpenDate)
+}
+
+// heldUntil - when the hold of a spot ends
+func heldUntil(s data.Spot) string {
+	until, err := time.Parse(time.RFC3339, s.HeldUntil)
+	if err != nil {
+		return s.HeldUntil
+	}
+	return until.Format(shortTimeFormat)
+}
@@ -0,0 +1,45 @@
+package handlers
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/jasonholmberg/slashspot/internal/spot"
+	"github.com/nlopes/slack"
+	"gotest.tools/v3/assert"
+)
+
+func Test_handleWant(t *testing.T) {
+	holdUntil := testNow().Add(spot.DefaultHoldFor).Format("2006-01-02 15:04")
+	tests := []struct {
+		name   string
+		userID string
+		params []string
+		want   string
+	}{
+		{name: "should put the user on today's waitlist", userID: "U1", params: []string{"want"}, want: fmt.Sprintf(WantWaitingTemplate, 1, dateSpecForTest(0))},
+		{name: "should only put the user on a waitlist once", userID: "U1", params: []string{"want", "today"}, want: fmt.Sprintf(WantAlreadyWaitingTemplate, dateSpecForTest(0))},
+		{name: "should put the user behind the first", userID: "U2", params: []string{"want"}, want: fmt.Sprintf(WantWaitingTemplate, 2, dateSpecForTest(0))},
+		{name: "should put the user on a later date's waitlist", userID: "U1", params: []string{"want", "tomorrow"}, want: fmt.Sprintf(WantWaitingTemplate, 1, dateSpecForTest(1))},
+		{name: "should not put the user on a past waitlist", userID: "U1", params: []string{"want", dateSpecForTest(-1)}, want: fmt.Sprintf(SpotPastDateRegistrationErrorTemplate, dateSpecForTest(-1))},
+		{name: "should not understand a bad date", userID: "U1", params: []string{"want", "someday"}, want: fmt.Sprintf(SpotDateFormatRegistrationErrorTemplate, "someday")},
+		{name: "should take the user off the waitlist", userID: "U1", params: []string{"want", "cancel", "tomorrow"}, want: fmt.Sprintf(WantCancelledTemplate, dateSpecForTest(1))},
+		{name: "should say the user is not on the waitlist", userID: "U1", params: []string{"want", "cancel", "tomorrow"}, want: fmt.Sprintf(WantNotWaitingTemplate, dateSpecForTest(1))},
+		{name: "should hold an open spot right away", userID: "U3", params: []string{"want", dateSpecForTest(2)}, want: fmt.Sprintf(WantHeldTemplate, "B9", dateSpecForTest(2), holdUntil, "/spot claim B9 "+dateSpecForTest(2))},
+	}
+	h := newTestHandler()
+	h.spots.Register("B9", "slackuser", testNow().AddDate(0, 0, 2))
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := h.handleWant(&slack.SlashCommand{UserID: tt.userID}, tt.params)
+			assert.Equal(t, got, tt.want)
+		})
+	}
+
+	msg, _ := h.spotCommand(&slack.SlashCommand{Text: "claim B9 " + dateSpecForTest(2), UserID: "U3"})
+	assert.Equal(t, msg.Text, fmt.Sprintf(SpotClaimedForTemplate, "B9", dateSpecForTest(2)), "should claim the held spot the way the user is told")
+
+	h.spots.Register("B1", "slackuser", testNow())
+	assert.Equal(t, h.claim("B1", "U2"), fmt.Sprintf(SpotHeldTemplate, "B1", "U1", holdUntil), "should not let anyone else claim a held spot")
+	assert.Equal(t, h.claim("B1", "U1"), fmt.Sprintf(SpotClaimedTemplate, "B1"))
+}
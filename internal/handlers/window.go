@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jasonholmberg/slashspot/internal/data"
+	"github.com/jasonholmberg/slashspot/internal/util"
+)
+
+const (
+	// SpotWindowFormatErrorTemplate - More than one part of the day given template
+	SpotWindowFormatErrorTemplate = "Only one part of the day can be given, like am, pm or 09:00-12:30, not %s"
+
+	// SpotOutsideWindowTemplate - Spot claimed outside the part of the day it is available template
+	SpotOutsideWindowTemplate = "The spot %s is only available %s on %s"
+
+	// SpotWindowTakenTemplate - Spot claimed for a part of the day someone else claimed some of template
+	SpotWindowTakenTemplate = "The spot %s is already claimed for some of that time on %s, it is still open %s"
+
+	// SpotSharedTemplate - Spot claimed when users have claimed all of it between them template
+	SpotSharedTemplate = "The spot %s is shared on %s by %s"
+
+	// SharedByTemplate - A user sharing a spot and the part of the day they claimed
+	SharedByTemplate = "%s %s"
+)
+
+// errTwoWindows - returned when more than one part of the day is given
+var errTwoWindows = errors.New("more than one window")
+
+// splitWindow - take the part of the day, like am, pm or 09:00-12:30, out of the params. The window is the whole
+// day when none is given.
+func splitWindow(params []string) (data.Window, []string, error) {
+	var window data.Window
+	var found []string
+	var rest []string
+	for _, p := range params {
+		if from, to, err := util.ParseWindow(p); err == nil {
+			window = data.Window{From: from, To: to}
+			found = append(found, p)
+			continue
+		}
+		rest = append(rest, p)
+	}
+	if len(found) > 1 {
+		return data.Window{}, nil, errTwoWindows
+	}
+	return window, rest, nil
+}
+
+// withWindow - the text followed by the window, unless it is the whole day
+func withWindow(text string, window data.Window) string {
+	if window.IsWhole() {
+		return text
+	}
+	return text + " " + window.String()
+}
+
+// joinWindows - the windows separated by commas
+func joinWindows(windows []data.Window) string {
+	return strings.Join(windowTexts(windows), ", ")
+}
+
+// windowTexts - the text of each window
+func windowTexts(windows []data.Window) []string {
+	var texts []string
+	for _, w := range windows {
+		texts = append(texts, w.String())
+	}
+	return texts
+}
+
+// sharedBy - who shares the spot and the parts of the day they claimed
+func (h *Handler) sharedBy(s data.Spot) string {
+	var shares []string
+	for _, share := range s.Shares {
+		shares = append(shares, fmt.Sprintf(SharedByTemplate, h.userName(share.User), share.Window))
+	}
+	return strings.Join(shares, ", ")
+}
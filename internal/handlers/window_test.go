@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/jasonholmberg/slashspot/internal/data"
+	"github.com/nlopes/slack"
+	"gotest.tools/v3/assert"
+)
+
+func Test_splitWindow(t *testing.T) {
+	tests := []struct {
+		name       string
+		params     []string
+		wantWindow data.Window
+		wantRest   []string
+		wantErr    bool
+	}{
+		{name: "should find no window", params: []string{"tomorrow"}, wantRest: []string{"tomorrow"}},
+		{name: "should take the window out", params: []string{"tomorrow", "pm"}, wantWindow: data.Window{From: "12:00"}, wantRest: []string{"tomorrow"}},
+		{name: "should take a start and end out", params: []string{"9-13", "ev"}, wantWindow: data.Window{From: "09:00", To: "13:00"}, wantRest: []string{"ev"}},
+		{name: "should not take two windows", params: []string{"am", "pm"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			window, rest, err := splitWindow(tt.params)
+			assert.Equal(t, err != nil, tt.wantErr)
+			assert.Equal(t, window, tt.wantWindow)
+			assert.DeepEqual(t, rest, tt.wantRest)
+		})
+	}
+}
+
+func Test_windows(t *testing.T) {
+	h := newTestHandler()
+	cmd := &slack.SlashCommand{UserID: "slackuser"}
+	today := dateSpecForTest(0)
+	steps := []struct {
+		name   string
+		userID string
+		params []string
+		want   string
+	}{
+		{name: "should register a spot for the afternoon", userID: "slackuser", params: []string{"reg", "B1", "pm"}, want: fmt.Sprintf(SpotRegisteredTemplate, "B1 pm")},
+		{name: "should not claim the morning", userID: "ponyboy", params: []string{"claim", "B1", "am"}, want: fmt.Sprintf(SpotOutsideWindowTemplate, "B1", "pm", today)},
+		{name: "should claim part of the afternoon", userID: "ponyboy", params: []string{"claim", "B1", "12:00-14:00"}, want: fmt.Sprintf(SpotClaimedTemplate, "B1 12:00-14:00")},
+		{name: "should not claim a taken window", userID: "sodapop", params: []string{"claim", "B1", "13-15"}, want: fmt.Sprintf(SpotWindowTakenTemplate, "B1", today, "14:00-24:00")},
+		{name: "should share the spot", userID: "sodapop", params: []string{"claim", "B1", "14-24"}, want: fmt.Sprintf(SpotClaimedTemplate, "B1 14:00-24:00")},
+		{name: "should say who shares the spot", userID: "fred", params: []string{"claim", "B1"}, want: fmt.Sprintf(SpotSharedTemplate, "B1", today, "ponyboy 12:00-14:00, sodapop 14:00-24:00")},
+		{name: "should not take two windows", userID: "fred", params: []string{"claim", "B1", "am", "pm"}, want: fmt.Sprintf(SpotWindowFormatErrorTemplate, "am pm")},
+	}
+	for _, tt := range steps {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &slack.SlashCommand{UserID: tt.userID, UserName: tt.userID, Text: strings.Join(tt.params, " ")}
+			got, _ := h.forUser(commandUser(c)).spotCommand(c)
+			assert.Equal(t, got.Text, tt.want)
+		})
+	}
+	assert.Equal(t, h.handleMine(cmd), fmt.Sprintf(MyRegistrationsTemplate, fmt.Sprintf(MySharedRegistrationTemplate, "B1 pm", today, "ponyboy 12:00-14:00, sodapop 14:00-24:00")))
+}
+
+func Test_findWindow(t *testing.T) {
+	h := newTestHandler()
+	h.spots.RegisterWindow("B1", "slackuser", testNow(), data.Window{From: "12:00"})
+	h.spots.Register("B2", "slackuser", testNow())
+	assert.Equal(t, h.findMessage([]string{"am"}).Text, fmt.Sprintf(OpenSpotsTemplate, "B2"))
+	assert.Equal(t, h.findMessage([]string{"pm"}).Text, fmt.Sprintf(OpenSpotsTemplate, "B1,B2"))
+	assert.Equal(t, h.findMessage([]string{"am", "ev"}).Text, fmt.Sprintf(NoMatchingSpotsTemplate, "am ev"))
+
+	blocks := h.findMessage([]string{"pm"}).Blocks
+	section := blocks[1].(*slack.SectionBlock)
+	assert.Equal(t, section.Text.Text, fmt.Sprintf(OpenSpotBlockTemplate, "B1", "slackuser")+fmt.Sprintf(OpenSpotWindowsTemplate, "pm"))
+	assert.Equal(t, section.Accessory.ButtonElement.Value, "B1 pm", "should claim the window from the button")
+}
@@ -1,20 +1,291 @@
 package internal
 
 import (
+	"encoding/json"
 	"fmt"
 	"log"
 	"net/http"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/jasonholmberg/slashspot/internal/data"
 	"github.com/jasonholmberg/slashspot/internal/handlers"
+	"github.com/jasonholmberg/slashspot/internal/spot"
+	"github.com/jasonholmberg/slashspot/internal/util"
+	"github.com/nlopes/slack"
 )
 
-// Run - Run spot bot, run
-func Run() {
-	data.Open()
-	http.HandleFunc("/command", handlers.SlashCommandHandler)
+// Run - Run spot bot, run. If fakeNow is set the clock starts at that time instead of now, for debugging.
+func Run(fakeNow string) {
+	store, err := data.Open()
+	if err != nil {
+		log.Fatal("Error opening spot store ", err)
+	}
+	defer store.Close()
+	loc, err := time.LoadLocation(os.Getenv("SPOT_TIMEZONE"))
+	if err != nil {
+		log.Fatal("Error loading SPOT_TIMEZONE ", err)
+	}
+	var clock util.Clock = util.SystemClock{}
+	if fakeNow != "" {
+		start, err := util.ParseTime(fakeNow, loc)
+		if err != nil {
+			log.Fatal("Error parsing fake now ", err)
+		}
+		log.Println("Pretending it is", start)
+		clock = util.NewOffsetClock(start)
+	}
+	teamLocs, err := util.LoadLocations(os.Getenv("SPOT_TEAM_TIMEZONES"))
+	if err != nil {
+		log.Fatal("Error loading SPOT_TEAM_TIMEZONES ", err)
+	}
+	options := []handlers.Option{handlers.WithTeamTimezones(teamLocs)}
+	client := slack.New(os.Getenv("SPOT_SLACK_BOT_TOKEN"))
+	if token := os.Getenv("SPOT_SLACK_BOT_TOKEN"); token != "" {
+		options = append(options, handlers.WithViews(handlers.NewSlackViews(token)))
+	}
+	if os.Getenv("SPOT_USER_TIMEZONES") == "true" {
+		options = append(options, handlers.WithUserTimezones(handlers.NewSlackUserTimezones(client)))
+	}
+	options = append(options, handlers.WithAdmins(handlers.NewAdminList(envList("SPOT_ADMINS")...)))
+	options = append(options, handlers.WithInChannel(envList("SPOT_IN_CHANNEL")...))
+	if workers := responseWorkers(); workers > 0 {
+		options = append(options, handlers.WithResponsePool(handlers.NewResponsePool(workers, responseQueue)))
+	}
+	if group := os.Getenv("SPOT_ADMIN_GROUP"); group != "" {
+		options = append(options, handlers.WithAdmins(handlers.NewSlackGroupAdmins(client, group)))
+	}
+	locations := envList("SPOT_LOCATIONS")
+	options = append(options, handlers.WithLocations(locations...))
+	for location, users := range locationAdmins() {
+		options = append(options, handlers.WithLocationAdmins(location, handlers.NewAdminList(users...)))
+	}
+	mode, holdFor := waitlist()
+	horizon, limit := futureClaims()
+	spots := spot.NewService(store).In(loc).WithClock(clock).WithWaitlist(mode, holdFor).WithFutureClaims(horizon, limit)
+	spots = spots.WithQuota(quota()).WithHistoryDays(envCount("SPOT_HISTORY_DAYS", 0))
+	if cutoff, ok := lotteryCutoff(); ok {
+		spots = spots.WithLottery(cutoff, os.Getenv("SPOT_LOTTERY_WEIGHTED") == "true")
+	}
+	var notifiers spot.Notifiers
+	if announcers := announcers(client); len(announcers) > 0 {
+		notifiers = append(notifiers, announcers)
+	}
+	if os.Getenv("SPOT_SLACK_BOT_TOKEN") != "" {
+		notifiers = append(notifiers, handlers.NewSlackMessenger(client))
+	}
+	spots = spots.WithNotifier(notifiers)
+	go runScheduled(spots, locations)
+	if path := os.Getenv("SPOT_CATALOG_FILE"); path != "" {
+		if err := seedCatalog(spots, path); err != nil {
+			log.Fatal("Error loading SPOT_CATALOG_FILE ", err)
+		}
+	}
+	h := handlers.New(spots, options...)
+	http.HandleFunc("/command", h.SlashCommandHandler)
+	http.HandleFunc("/interactions", h.InteractionHandler)
 	port := os.Getenv("SPOT_SERVER_PORT")
 	log.Println("Spot's listening on", port)
 	http.ListenAndServe(fmt.Sprint(":", port), nil)
 }
+
+// seedCatalog - load the spot catalog from the JSON file at path when the store has none yet, after that admins keep
+// it with the admin catalog commands
+func seedCatalog(spots *spot.Service, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	catalog, err := data.LoadCatalog(f)
+	if err != nil {
+		return err
+	}
+	seeded, err := spots.SeedCatalog(catalog)
+	if err == nil && !seeded {
+		log.Print("Keeping the spot catalog in the store, not loading ", path)
+	}
+	return err
+}
+
+// defaultResponseWorkers, responseQueue - how many commands are worked on after slack has been answered, and how
+// many can wait for a worker before commands are answered right away again
+const (
+	defaultResponseWorkers = 4
+	responseQueue          = 100
+)
+
+// responseWorkers - the number of workers in SPOT_RESPONSE_WORKERS, 0 answers every command right away
+func responseWorkers() int {
+	return envCount("SPOT_RESPONSE_WORKERS", defaultResponseWorkers)
+}
+
+// scheduleInterval - how often the store is refreshed and due draws are made
+const scheduleInterval = time.Minute
+
+// runScheduled - refresh the store, passing spots whose hold is over to the next user waiting, registering recurring
+// spots and cleaning up old ones, and make today's draw at each location once it is due, from now on forever. Without
+// locations the draw is made at no location.
+func runScheduled(spots *spot.Service, locations []string) {
+	if len(locations) == 0 {
+		locations = []string{""}
+	}
+	tick := time.Tick(scheduleInterval)
+	for ; ; <-tick {
+		if err := spots.Refresh(); err != nil {
+			log.Print("Error refreshing spots ", err)
+		}
+		for _, location := range locations {
+			if err := spots.At(location).DrawDue(); err != nil {
+				log.Print("Error making the draw ", location, " ", err)
+			}
+		}
+	}
+}
+
+// lotteryCutoff - the time of day in SPOT_LOTTERY_CUTOFF, like 08:00, as the time after midnight. Returns false when
+// it is not set and spots are first come first served.
+func lotteryCutoff() (time.Duration, bool) {
+	spec := os.Getenv("SPOT_LOTTERY_CUTOFF")
+	if spec == "" {
+		return 0, false
+	}
+	at, err := time.Parse("15:04", spec)
+	if err != nil {
+		log.Fatal("Error loading SPOT_LOTTERY_CUTOFF ", spec)
+	}
+	return time.Duration(at.Hour())*time.Hour + time.Duration(at.Minute())*time.Minute, true
+}
+
+// waitlist - the waitlist mode in SPOT_WAITLIST_MODE and how long spots are held in SPOT_WAITLIST_HOLD
+func waitlist() (spot.WaitlistMode, time.Duration) {
+	mode := spot.WaitlistMode(os.Getenv("SPOT_WAITLIST_MODE"))
+	switch mode {
+	case "":
+		mode = spot.WaitlistHold
+	case spot.WaitlistHold, spot.WaitlistAssign:
+	default:
+		log.Fatal("Error loading SPOT_WAITLIST_MODE ", mode)
+	}
+	holdFor := spot.DefaultHoldFor
+	if spec := os.Getenv("SPOT_WAITLIST_HOLD"); spec != "" {
+		var err error
+		if holdFor, err = time.ParseDuration(spec); err != nil || holdFor <= 0 {
+			log.Fatal("Error loading SPOT_WAITLIST_HOLD ", spec)
+		}
+	}
+	return mode, holdFor
+}
+
+// futureClaims - how many days ahead spots can be claimed in SPOT_CLAIM_HORIZON, and how many claims for later
+// dates each user can have in SPOT_CLAIM_LIMIT
+func futureClaims() (int, int) {
+	return envCount("SPOT_CLAIM_HORIZON", spot.DefaultClaimHorizon), envCount("SPOT_CLAIM_LIMIT", spot.DefaultClaimLimit)
+}
+
+// quota - the claim quota in SPOT_QUOTA_PER_WEEK, SPOT_QUOTA_CONCURRENT and SPOT_QUOTA_COOLDOWN, rules left out do not
+// limit claims
+func quota() spot.Quota {
+	q := spot.Quota{
+		PerWeek:    envCount("SPOT_QUOTA_PER_WEEK", 0),
+		Concurrent: envCount("SPOT_QUOTA_CONCURRENT", 0),
+	}
+	if spec := os.Getenv("SPOT_QUOTA_COOLDOWN"); spec != "" {
+		var err error
+		if q.Cooldown, err = time.ParseDuration(spec); err != nil || q.Cooldown < 0 {
+			log.Fatal("Error loading SPOT_QUOTA_COOLDOWN ", spec)
+		}
+	}
+	return q
+}
+
+// announcers - an announcer for each location with a channel in SPOT_LOCATION_CHANNELS, like downtown=C0123, and one
+// for other locations in SPOT_ANNOUNCE_CHANNEL
+func announcers(client *slack.Client) spot.LocationNotifiers {
+	announcers := make(spot.LocationNotifiers)
+	if channel := os.Getenv("SPOT_ANNOUNCE_CHANNEL"); channel != "" {
+		announcers[""] = handlers.NewSlackAnnouncer(client, channel)
+	}
+	for location, channel := range envPairs("SPOT_LOCATION_CHANNELS") {
+		announcers[location] = handlers.NewSlackAnnouncer(client, channel)
+	}
+	return announcers
+}
+
+// locationAdmins - the admins of each location in SPOT_LOCATION_ADMINS, a list of user IDs and their location like
+// U0123=downtown
+func locationAdmins() map[string][]string {
+	admins := make(map[string][]string)
+	for _, pair := range envList("SPOT_LOCATION_ADMINS") {
+		kv := strings.SplitN(pair, "=", 2)
+		if len(kv) != 2 {
+			log.Fatal("Error loading SPOT_LOCATION_ADMINS ", pair)
+		}
+		location := strings.TrimSpace(kv[1])
+		admins[location] = append(admins[location], strings.TrimSpace(kv[0]))
+	}
+	return admins
+}
+
+// envPairs - the values of a comma separated list of key=value pairs in the environment variable
+func envPairs(key string) map[string]string {
+	pairs := make(map[string]string)
+	for _, pair := range envList(key) {
+		kv := strings.SplitN(pair, "=", 2)
+		if len(kv) != 2 {
+			log.Fatal("Error loading ", key, " ", pair)
+		}
+		pairs[strings.TrimSpace(kv[0])] = strings.TrimSpace(kv[1])
+	}
+	return pairs
+}
+
+// envCount - the number in the environment variable, or the default when it is not set
+func envCount(key string, defaultCount int) int {
+	spec := os.Getenv(key)
+	if spec == "" {
+		return defaultCount
+	}
+	count, err := strconv.Atoi(spec)
+	if err != nil || count < 0 {
+		log.Fatal("Error loading ", key, " ", spec)
+	}
+	return count
+}
+
+// envList - the values of a comma separated list in the environment variable
+func envList(key string) []string {
+	var values []string
+	for _, value := range strings.Split(os.Getenv(key), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// MigrateUsers - replace user names with user IDs in the spot store, once, for stores from before users were kept
+// by ID. The file at path is a JSON object of user names to slack user IDs.
+func MigrateUsers(path string) {
+	f, err := os.Open(path)
+	if err != nil {
+		log.Fatal("Error opening user map ", err)
+	}
+	defer f.Close()
+	var ids map[string]string
+	if err := json.NewDecoder(f).Decode(&ids); err != nil {
+		log.Fatal("Error reading user map ", err)
+	}
+	store, err := data.Open()
+	if err != nil {
+		log.Fatal("Error opening spot store ", err)
+	}
+	defer store.Close()
+	changed, err := data.MigrateUserNames(store, ids)
+	if err != nil {
+		log.Fatal("Error migrating users ", err)
+	}
+	log.Printf("Migrated %d spots and records to user IDs", changed)
+}
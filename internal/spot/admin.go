@@ -0,0 +1,177 @@
+package spot
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/jasonholmberg/slashspot/internal/data"
+	"github.com/jasonholmberg/slashspot/internal/util"
+)
+
+// ErrNotAdmin - returned when a user who is not an admin does something only admins can do
+var ErrNotAdmin = errors.New("not an admin")
+
+// AllRegistrations - every current and future registration at the service's location, claimed or not, ordered by
+// date and spot. Only admins can list everyone's registrations.
+func (s *Service) AllRegistrations(admin string) ([]data.Spot, error) {
+	if !s.IsAdmin(admin) {
+		return nil, ErrNotAdmin
+	}
+	spots, err := s.store.ListByDate(s.today(), "")
+	if err != nil {
+		return nil, errors.New("error loading spot data")
+	}
+	var regs []data.Spot
+	for _, spot := range spots {
+		if s.here(spot) {
+			regs = append(regs, spot)
+		}
+	}
+	sortSpots(regs)
+	return regs, nil
+}
+
+// ForceDrop - drop the registration of a spot on a date whoever registered it, even if it has been claimed
+func (s *Service) ForceDrop(id string, openDate time.Time, admin string) (data.Spot, error) {
+	if !s.IsAdmin(admin) {
+		return data.Spot{}, ErrNotAdmin
+	}
+	var dropped data.Spot
+	err := s.store.Update(func(tx data.Tx) error {
+		spot, err := tx.Get(s.spotKey(id, openDate))
+		if err != nil {
+			return err
+		}
+		dropped = spot
+		if err := s.record(tx, data.Change{Kind: data.HistoryDropped, By: admin}, spot); err != nil {
+			return err
+		}
+		return tx.Delete(spot.Key())
+	})
+	if err != nil {
+		return data.Spot{}, fmt.Errorf("drop reg error of ID: %v", id)
+	}
+	log.Printf("Registration Id: %v by %v for date: %v dropped by admin %v", dropped.ID, dropped.RegisteredBy, dropped.OpenDate, admin)
+	return dropped, nil
+}
+
+// ForceRelease - give back a spot claimed today whoever claimed it, along with every part of the day users sharing
+// it claimed
+func (s *Service) ForceRelease(id string, admin string) (data.Spot, error) {
+	if !s.IsAdmin(admin) {
+		return data.Spot{}, ErrNotAdmin
+	}
+	var released data.Spot
+	var claimedBy []string
+	var offered *Event
+	err := s.store.Update(func(tx data.Tx) error {
+		claimedBy = nil
+		spot, err := tx.Get(s.spotKey(id, s.Now()))
+		if err != nil {
+			return err
+		}
+		if !spot.IsClaimed() && !spot.IsShared() {
+			return fmt.Errorf("spot %v is not claimed", id)
+		}
+		var changes []data.Change
+		if spot.IsClaimed() {
+			claimedBy = append(claimedBy, spot.ClaimedBy)
+			changes = append(changes, data.Change{Kind: data.HistoryReleased, User: spot.ClaimedBy, By: admin})
+		}
+		for _, share := range spot.Shares {
+			claimedBy = append(claimedBy, share.User)
+			changes = append(changes, data.Change{Kind: data.HistoryReleased, User: share.User, Window: share.Window.String(), By: admin})
+		}
+		spot.ClaimedBy = ""
+		spot.ClaimedAt = ""
+		spot.Shares = nil
+		released = spot
+		if err := tx.Put(spot); err != nil {
+			return err
+		}
+		for _, c := range changes {
+			if err := s.record(tx, c, spot); err != nil {
+				return err
+			}
+		}
+		offered, err = s.offer(tx, spot)
+		return err
+	})
+	if err != nil {
+		return data.Spot{}, fmt.Errorf("spot %v can not be released: %v", id, err)
+	}
+	log.Printf("Spot %v claimed by %v released by admin %v", id, strings.Join(claimedBy, ", "), admin)
+	s.notify(SpotReleased, released)
+	s.notifyOffered(offered)
+	return released, nil
+}
+
+// Purge - delete every registration at the service's location dated before today, every recurrence there that has
+// ended and the waitlists and draw entries of past dates, returns how many registrations were deleted. Draws are
+// kept so they can still be checked.
+func (s *Service) Purge(admin string) (int, error) {
+	if !s.IsAdmin(admin) {
+		return 0, ErrNotAdmin
+	}
+	today := s.today()
+	yesterday := s.Now().AddDate(0, 0, -1).Format(util.SpotDateFormat)
+	var purged int
+	err := s.store.Update(func(tx data.Tx) error {
+		purged = 0
+		spots, err := tx.ListByDate("", yesterday)
+		if err != nil {
+			return err
+		}
+		for k, spot := range spots {
+			if !s.here(spot) {
+				continue
+			}
+			if err := tx.Delete(k); err != nil {
+				return err
+			}
+			purged++
+		}
+		recs, err := data.ListRecurrences(tx)
+		if err != nil {
+			return err
+		}
+		for _, rec := range recs {
+			if rec.Location == s.location && rec.Until != "" && rec.Until < today {
+				if err := data.DeleteRecurrence(tx, rec.Key()); err != nil {
+					return err
+				}
+			}
+		}
+		wants, err := data.ListWants(tx)
+		if err != nil {
+			return err
+		}
+		for k, want := range wants {
+			if want.Location == s.location && want.Date < today {
+				if err := data.DeleteWant(tx, k); err != nil {
+					return err
+				}
+			}
+		}
+		entries, err := data.ListEntries(tx)
+		if err != nil {
+			return err
+		}
+		for k, entry := range entries {
+			if entry.Location == s.location && entry.Date < today {
+				if err := data.DeleteEntry(tx, k); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, errors.New("error purging spot data")
+	}
+	log.Printf("Purged %d past registrations by admin %v", purged, admin)
+	return purged, nil
+}
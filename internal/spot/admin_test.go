@@ -0,0 +1,119 @@
+package spot
+
+import (
+	"testing"
+
+	"github.com/jasonholmberg/slashspot/internal/data"
+	"github.com/jasonholmberg/slashspot/internal/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestService_AllRegistrations(t *testing.T) {
+	s := newTestService().WithAdmins("boss")
+	registerSpotsForTest(s, testSpots())
+	_, err := s.AllRegistrations("slackuser")
+	assert.Equal(t, ErrNotAdmin, err)
+
+	got, err := s.AllRegistrations("boss")
+	assert.Nil(t, err)
+	var keys []string
+	for _, spot := range got {
+		keys = append(keys, spot.Key())
+	}
+	assert.Equal(t, []string{"B1-2020-01-08", "B2-2020-01-08", "B4-2020-01-08", "B3-2020-01-09"}, keys)
+}
+
+func TestService_ForceDrop(t *testing.T) {
+	s := newTestService().WithAdmins("boss")
+	registerSpotsForTest(s, testSpots())
+	s.Claim("B1", "ponyboy")
+	_, err := s.ForceDrop("B1", testNow(), "slackuser")
+	assert.Equal(t, ErrNotAdmin, err)
+
+	got, err := s.ForceDrop("B1", testNow(), "boss")
+	assert.Nil(t, err)
+	assert.Equal(t, "ponyboy", got.ClaimedBy, "should drop a claimed registration")
+	_, err = s.store.Get("B1-2020-01-08")
+	assert.Equal(t, data.ErrNotFound, err)
+
+	_, err = s.ForceDrop("B1", testNow(), "boss")
+	assert.NotNil(t, err, "should not drop a missing registration")
+}
+
+func TestService_ForceRelease(t *testing.T) {
+	s := newTestService().WithAdmins("boss")
+	registerSpotsForTest(s, testSpots())
+	s.Claim("B1", "ponyboy")
+	_, err := s.ForceRelease("B1", "slackuser")
+	assert.Equal(t, ErrNotAdmin, err)
+
+	got, err := s.ForceRelease("B1", "boss")
+	assert.Nil(t, err)
+	assert.False(t, got.IsClaimed())
+	_, err = s.ForceRelease("B2", "boss")
+	assert.NotNil(t, err, "should not release an open spot")
+}
+
+func TestService_ForceReleaseShared(t *testing.T) {
+	s := newTestService().WithAdmins("boss")
+	s.Register("B1", "slackuser", testNow())
+	s.ClaimWindow("B1", "ponyboy", testNow(), data.Window{To: util.Noon})
+	s.ClaimWindow("B1", "sodapop", testNow(), data.Window{From: util.Noon})
+
+	got, err := s.ForceRelease("B1", "boss")
+	assert.Nil(t, err, "should release a spot shared by parts of the day")
+	assert.False(t, got.IsShared())
+	assert.True(t, got.OpenFor(data.Window{}))
+	changes, _ := data.ListHistory(s.store, "", "")
+	var released [][]string
+	for _, c := range changes {
+		if c.Kind == data.HistoryReleased {
+			released = append(released, []string{c.User, c.Window, c.By})
+		}
+	}
+	assert.Equal(t, [][]string{{"ponyboy", "am", "boss"}, {"sodapop", "pm", "boss"}}, released, "should record a release for each user sharing it")
+}
+
+func TestService_Purge(t *testing.T) {
+	s := newTestService().WithAdmins("boss")
+	registerSpotsForTest(s, testSpots())
+	s.RegisterRecurring("R1", "slackuser", nil, testNow())
+	_, err := s.Purge("slackuser")
+	assert.Equal(t, ErrNotAdmin, err)
+
+	got, err := s.Purge("boss")
+	assert.Nil(t, err)
+	assert.Equal(t, 1, got)
+	spots, _ := s.store.List()
+	assert.Equal(t, 4, len(spots))
+	_, err = data.GetRecurrence(s.store, "R1")
+	assert.Nil(t, err, "should keep a recurrence that ends today")
+
+	s = s.WithClock(util.FixedClock(testNow().AddDate(0, 0, 1)))
+	got, _ = s.Purge("boss")
+	assert.Equal(t, 3, got)
+	_, err = data.GetRecurrence(s.store, "R1")
+	assert.Equal(t, data.ErrNotFound, err, "should purge an ended recurrence")
+}
+
+func TestService_PurgeWaitlistsAndEntries(t *testing.T) {
+	s := newTestService().WithAdmins("boss")
+	tomorrow := testNow().AddDate(0, 0, 1)
+	s.Want("ponyboy", testNow())
+	s.Want("ponyboy", tomorrow)
+	s.store.Update(func(tx data.Tx) error {
+		data.PutEntry(tx, data.Entry{Date: testNow().Format(util.SpotDateFormat), User: "ponyboy"})
+		data.PutEntry(tx, data.Entry{Date: tomorrow.Format(util.SpotDateFormat), User: "ponyboy"})
+		return data.PutEntry(tx, data.Entry{Date: testNow().Format(util.SpotDateFormat), User: "ponyboy", Location: "uptown"})
+	})
+
+	s = s.WithClock(util.FixedClock(tomorrow))
+	_, err := s.Purge("boss")
+	assert.Nil(t, err)
+	wants, _ := data.ListWants(s.store)
+	assert.Equal(t, 1, len(wants), "should purge past waitlists")
+	assert.Contains(t, wants, data.Want{Date: tomorrow.Format(util.SpotDateFormat), User: "ponyboy"}.Key())
+	entries, _ := data.ListEntries(s.store)
+	assert.Equal(t, 2, len(entries), "should purge past draw entries at the location only")
+	assert.Contains(t, entries, data.Entry{Date: tomorrow.Format(util.SpotDateFormat), User: "ponyboy"}.Key())
+}
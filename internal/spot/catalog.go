@@ -0,0 +1,175 @@
+package spot
+
+import (
+	"errors"
+	"log"
+	"sort"
+
+	"github.com/jasonholmberg/slashspot/internal/data"
+)
+
+// ErrUnknownSpot - returned when a spot is not in the catalog
+var ErrUnknownSpot = errors.New("spot is not in the catalog")
+
+// ImportCatalog - replace the catalog of known spots at every location. Registrations already made are kept.
+func (s *Service) ImportCatalog(spots []data.CatalogSpot) error {
+	err := s.store.Update(func(tx data.Tx) error {
+		old, err := data.ListCatalog(tx)
+		if err != nil {
+			return err
+		}
+		for k := range old {
+			if err := data.DeleteCatalogSpot(tx, k); err != nil {
+				return err
+			}
+		}
+		for _, c := range spots {
+			if err := data.PutCatalogSpot(tx, c); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return errors.New("error saving spot catalog")
+	}
+	log.Printf("Imported a catalog of %d spots", len(spots))
+	return nil
+}
+
+// SeedCatalog - import the catalog when the store has none yet, returns true if it was imported. Once seeded the
+// catalog is kept by the admin catalog commands.
+func (s *Service) SeedCatalog(spots []data.CatalogSpot) (bool, error) {
+	known, err := data.ListCatalog(s.store)
+	if err != nil {
+		return false, errors.New("error loading spot catalog")
+	}
+	if len(known) > 0 {
+		return false, nil
+	}
+	return true, s.ImportCatalog(spots)
+}
+
+// AddCatalogSpot - add a spot to the catalog at the service's location, or replace what a spot already in it is
+// like. The holder of a spot already in the catalog is kept. Only admins can change the catalog.
+func (s *Service) AddCatalogSpot(c data.CatalogSpot, admin string) (data.CatalogSpot, error) {
+	if !s.IsAdmin(admin) {
+		return data.CatalogSpot{}, ErrNotAdmin
+	}
+	c.Location = s.location
+	err := s.store.Update(func(tx data.Tx) error {
+		known, err := data.GetCatalogSpot(tx, c.Key())
+		if err == nil {
+			c.Holder = known.Holder
+		} else if err != data.ErrNotFound {
+			return err
+		}
+		return data.PutCatalogSpot(tx, c)
+	})
+	if err != nil {
+		return data.CatalogSpot{}, errors.New("error saving spot catalog")
+	}
+	log.Printf("Catalog spot %v added by admin %v", c.ID, admin)
+	return c, nil
+}
+
+// RetireCatalogSpot - take a spot at the service's location out of the catalog along with any admin's assignment of
+// it. Registrations already made are kept.
+func (s *Service) RetireCatalogSpot(id string, admin string) error {
+	if !s.IsAdmin(admin) {
+		return ErrNotAdmin
+	}
+	key := data.LocationKey(s.location, id)
+	err := s.store.Update(func(tx data.Tx) error {
+		if _, err := data.GetCatalogSpot(tx, key); err != nil {
+			return err
+		}
+		if err := data.DeleteCatalogSpot(tx, key); err != nil {
+			return err
+		}
+		return data.DeleteAssignment(tx, key)
+	})
+	if err == data.ErrNotFound {
+		return ErrUnknownSpot
+	}
+	if err != nil {
+		return errors.New("error saving spot catalog")
+	}
+	log.Printf("Catalog spot %v retired by admin %v", id, admin)
+	return nil
+}
+
+// ReassignCatalogSpot - make the user the holder of a spot at the service's location in the catalog, an empty holder
+// leaves the spot unheld. Any admin's assignment of the spot is removed so the catalog's holder is the holder.
+func (s *Service) ReassignCatalogSpot(id string, holder string, admin string) (data.CatalogSpot, error) {
+	if !s.IsAdmin(admin) {
+		return data.CatalogSpot{}, ErrNotAdmin
+	}
+	key := data.LocationKey(s.location, id)
+	var c data.CatalogSpot
+	err := s.store.Update(func(tx data.Tx) error {
+		var err error
+		if c, err = data.GetCatalogSpot(tx, key); err != nil {
+			return err
+		}
+		c.Holder = holder
+		if err := data.PutCatalogSpot(tx, c); err != nil {
+			return err
+		}
+		return data.DeleteAssignment(tx, key)
+	})
+	if err == data.ErrNotFound {
+		return data.CatalogSpot{}, ErrUnknownSpot
+	}
+	if err != nil {
+		return data.CatalogSpot{}, errors.New("error saving spot catalog")
+	}
+	log.Printf("Catalog spot %v reassigned to %q by admin %v", id, holder, admin)
+	return c, nil
+}
+
+// Catalog - the known spots at the service's location ordered by ID
+func (s *Service) Catalog() ([]data.CatalogSpot, error) {
+	all, err := data.ListCatalog(s.store)
+	if err != nil {
+		return nil, errors.New("error loading spot catalog")
+	}
+	var spots []data.CatalogSpot
+	for _, c := range all {
+		if c.Location == s.location {
+			spots = append(spots, c)
+		}
+	}
+	sort.Slice(spots, func(i, j int) bool {
+		return spots[i].ID < spots[j].ID
+	})
+	return spots, nil
+}
+
+// checkCatalog - make sure a spot is in the catalog at the service's location. Without a catalog for the location
+// any spot is allowed.
+func (s *Service) checkCatalog(r data.Reader, id string) error {
+	known, err := data.ListCatalog(r)
+	if err != nil {
+		return err
+	}
+	if _, ok := known[data.LocationKey(s.location, id)]; ok {
+		return nil
+	}
+	for _, c := range known {
+		if c.Location == s.location {
+			return ErrUnknownSpot
+		}
+	}
+	return nil
+}
+
+// matchesAll - returns true if the catalog spot passes every filter
+func matchesAll(c data.CatalogSpot, filters []string) bool {
+	for _, filter := range filters {
+		if !c.Matches(filter) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,136 @@
+package spot
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jasonholmberg/slashspot/internal/data"
+	"github.com/stretchr/testify/assert"
+)
+
+func testCatalog() []data.CatalogSpot {
+	return []data.CatalogSpot{
+		{ID: "B1", Garage: "north", Level: "1", Attributes: []string{"ev"}},
+		{ID: "B2", Garage: "north", Level: "2", Attributes: []string{"compact"}},
+		{ID: "B3", Garage: "south", Level: "2"},
+		{ID: "B4", Garage: "south", Level: "2", Attributes: []string{"ev", "ada"}},
+	}
+}
+
+func TestService_ImportCatalog(t *testing.T) {
+	s := newTestService()
+	assert.Nil(t, s.ImportCatalog([]data.CatalogSpot{{ID: "Z9"}}))
+	assert.Nil(t, s.ImportCatalog(testCatalog()))
+	got, err := s.Catalog()
+	assert.Nil(t, err)
+	assert.Equal(t, testCatalog(), got, "should replace the old catalog")
+}
+
+func TestService_RegisterWithCatalog(t *testing.T) {
+	s := newTestService()
+	_, err := s.Register("Z9", "slackuser", time.Time{})
+	assert.Nil(t, err, "should register any spot without a catalog")
+
+	s.ImportCatalog(testCatalog())
+	_, err = s.Register("B1", "slackuser", time.Time{})
+	assert.Nil(t, err)
+	_, err = s.Register("Z8", "slackuser", time.Time{})
+	assert.Equal(t, ErrUnknownSpot, err)
+	_, err = s.RegisterRange("Z8", "slackuser", []time.Time{testNow()})
+	assert.Equal(t, ErrUnknownSpot, err)
+	_, err = s.RegisterRecurring("Z8", "slackuser", []time.Weekday{time.Monday}, time.Time{})
+	assert.Equal(t, ErrUnknownSpot, err)
+}
+
+func TestService_FindWithFilters(t *testing.T) {
+	s := newTestService()
+	registerSpotsForTest(s, testSpots())
+	s.ImportCatalog(testCatalog())
+	tests := []struct {
+		name    string
+		filters []string
+		want    []string
+		wantErr bool
+	}{
+		{name: "should find every open spot without filters", want: []string{"B1", "B2", "B4"}},
+		{name: "should find spots with an attribute", filters: []string{"ev"}, want: []string{"B1", "B4"}},
+		{name: "should find spots on a level", filters: []string{"level:2"}, want: []string{"B2", "B4"}},
+		{name: "should find spots matching every filter", filters: []string{"ev", "level:2"}, want: []string{"B4"}},
+		{name: "should find nothing matching", filters: []string{"garage:east"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := s.Find(tt.filters...)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Service.Find() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			var ids []string
+			for _, spot := range got {
+				ids = append(ids, spot.ID)
+			}
+			assert.ElementsMatch(t, tt.want, ids)
+		})
+	}
+}
+
+func TestService_SeedCatalog(t *testing.T) {
+	s := newTestService()
+	seeded, err := s.SeedCatalog(testCatalog())
+	assert.Nil(t, err)
+	assert.True(t, seeded, "should seed an empty catalog")
+
+	seeded, err = s.SeedCatalog([]data.CatalogSpot{{ID: "Z9"}})
+	assert.Nil(t, err)
+	assert.False(t, seeded, "should keep the catalog in the store")
+	got, _ := s.Catalog()
+	assert.Equal(t, testCatalog(), got)
+}
+
+func TestService_AddCatalogSpot(t *testing.T) {
+	s := newTestService().WithAdmins("boss")
+	s.ImportCatalog([]data.CatalogSpot{{ID: "B1", Holder: "slackuser"}})
+	_, err := s.AddCatalogSpot(data.CatalogSpot{ID: "B2"}, "slackuser")
+	assert.Equal(t, ErrNotAdmin, err)
+
+	_, err = s.AddCatalogSpot(data.CatalogSpot{ID: "B2", Level: "2"}, "boss")
+	assert.Nil(t, err)
+	got, err := s.AddCatalogSpot(data.CatalogSpot{ID: "B1", Attributes: []string{"ev"}}, "boss")
+	assert.Nil(t, err)
+	assert.Equal(t, data.CatalogSpot{ID: "B1", Attributes: []string{"ev"}, Holder: "slackuser"}, got, "should keep the holder")
+	catalog, _ := s.Catalog()
+	assert.Equal(t, []data.CatalogSpot{got, {ID: "B2", Level: "2"}}, catalog)
+	_, err = s.Register("B2", "slackuser", time.Time{})
+	assert.Nil(t, err, "should register an added spot")
+}
+
+func TestService_RetireCatalogSpot(t *testing.T) {
+	s := newTestService().WithAdmins("boss")
+	s.ImportCatalog(testCatalog())
+	s.AssignHolder("B1", "ponyboy", "boss")
+	assert.Equal(t, ErrNotAdmin, s.RetireCatalogSpot("B1", "slackuser"))
+	assert.Equal(t, ErrUnknownSpot, s.RetireCatalogSpot("Z9", "boss"))
+
+	assert.Nil(t, s.RetireCatalogSpot("B1", "boss"))
+	_, err := s.Register("B1", "slackuser", time.Time{})
+	assert.Equal(t, ErrUnknownSpot, err, "should not register a retired spot")
+	holder, _ := s.Holder("B1")
+	assert.Equal(t, "", holder, "should remove the assignment")
+}
+
+func TestService_ReassignCatalogSpot(t *testing.T) {
+	s := newTestService().WithAdmins("boss")
+	s.ImportCatalog([]data.CatalogSpot{{ID: "B1", Holder: "slackuser"}})
+	s.AssignHolder("B1", "ponyboy", "boss")
+	_, err := s.ReassignCatalogSpot("B1", "ponyboy", "slackuser")
+	assert.Equal(t, ErrNotAdmin, err)
+	_, err = s.ReassignCatalogSpot("Z9", "ponyboy", "boss")
+	assert.Equal(t, ErrUnknownSpot, err)
+
+	got, err := s.ReassignCatalogSpot("B1", "FredsMom", "boss")
+	assert.Nil(t, err)
+	assert.Equal(t, "FredsMom", got.Holder)
+	holder, _ := s.Holder("B1")
+	assert.Equal(t, "FredsMom", holder, "should replace the assignment")
+	_, err = s.Register("B1", "ponyboy", time.Time{})
+	assert.Equal(t, ErrNotHolder, err)
+}
@@ -0,0 +1,122 @@
+package spot
+
+import (
+	"github.com/jasonholmberg/slashspot/internal/data"
+)
+
+// EventKind - what happened to the spots of an event
+type EventKind string
+
+const (
+	// SpotsRegistered - spots were opened by a registration, a range registration or a recurrence
+	SpotsRegistered EventKind = "registered"
+
+	// SpotClaimed - an open spot was claimed
+	SpotClaimed EventKind = "claimed"
+
+	// SpotReleased - a claimed spot was given back, or a spot's hold ended with no one else waiting, and it is open
+	// again
+	SpotReleased EventKind = "released"
+)
+
+// Event - something that happened to spots. The spots of a registration event are all registrations of one spot
+// made together, so a range registration is a single event.
+type Event struct {
+	Kind  EventKind
+	Spots []data.Spot
+
+	// Users - the users an event is about when it is not about spots, like the losers of a draw
+	Users []string
+
+	// Date - the date an event that is not about spots is for
+	Date string
+
+	// Today - the date it was where the event happened
+	Today string
+}
+
+// Notifier - is told about events after they are saved. Notify should not block, it is called while a command is
+// being answered.
+type Notifier interface {
+	Notify(e Event)
+}
+
+// WithNotifier - a copy of the service sharing its store that tells the notifier about registrations and claims
+func (s *Service) WithNotifier(notifier Notifier) *Service {
+	with := *s
+	with.notifier = notifier
+	return &with
+}
+
+// notify - tell the notifier, if there is one, about the spots
+func (s *Service) notify(kind EventKind, spots ...data.Spot) {
+	if s.notifier == nil || len(spots) == 0 {
+		return
+	}
+	s.notifier.Notify(Event{Kind: kind, Spots: spots, Today: s.today()})
+}
+
+// notifyAll - tell the notifier, if there is one, about the events
+func (s *Service) notifyAll(events []Event) {
+	if s.notifier == nil {
+		return
+	}
+	for _, e := range events {
+		s.notifier.Notify(e)
+	}
+}
+
+// notifyOffered - tell the notifier about a spot given to a user waiting for it, if one was
+func (s *Service) notifyOffered(offered *Event) {
+	if offered != nil {
+		s.notifyAll([]Event{*offered})
+	}
+}
+
+// offeredSpots - the registered spots as they are once given to the users waiting for them in the offered events, so
+// a registration event does not tell about a spot as open when it is already held or claimed
+func offeredSpots(registered []data.Spot, offered ...Event) []data.Spot {
+	given := make(map[string]data.Spot, len(offered))
+	for _, e := range offered {
+		for _, spot := range e.Spots {
+			given[spot.Key()] = spot
+		}
+	}
+	spots := make([]data.Spot, 0, len(registered))
+	for _, spot := range registered {
+		if g, ok := given[spot.Key()]; ok {
+			spot = g
+		}
+		spots = append(spots, spot)
+	}
+	return spots
+}
+
+// Notifiers - tells every notifier about each event
+type Notifiers []Notifier
+
+// Notify - tell every notifier about the event
+func (n Notifiers) Notify(e Event) {
+	for _, notifier := range n {
+		notifier.Notify(e)
+	}
+}
+
+// LocationNotifiers - tells the notifier of the location of each event's spots, keyed by location. Events at a
+// location without its own notifier, and events about no spots, are told to the notifier under the empty location.
+type LocationNotifiers map[string]Notifier
+
+// Notify - tell the notifier of the event's location about the event
+func (n LocationNotifiers) Notify(e Event) {
+	var location string
+	if len(e.Spots) > 0 {
+		location = e.Spots[0].Location
+	}
+	notifier, ok := n[location]
+	if !ok {
+		notifier, ok = n[""]
+	}
+	if ok {
+		notifier.Notify(e)
+	}
+}
@@ -0,0 +1,127 @@
+package spot
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jasonholmberg/slashspot/internal/util"
+	"github.com/stretchr/testify/assert"
+)
+
+// testNotifier - remembers the events it is told about
+type testNotifier struct {
+	events []Event
+}
+
+func (n *testNotifier) Notify(e Event) {
+	n.events = append(n.events, e)
+}
+
+// kinds - the kinds of the events and the spot keys in each
+func (n *testNotifier) kinds() [][]string {
+	var got [][]string
+	for _, e := range n.events {
+		kind := []string{string(e.Kind)}
+		for _, spot := range e.Spots {
+			kind = append(kind, spot.Key())
+		}
+		got = append(got, kind)
+	}
+	return got
+}
+
+func TestService_WithNotifier(t *testing.T) {
+	tomorrow := testNow().AddDate(0, 0, 1)
+	tests := []struct {
+		name string
+		do   func(s *Service)
+		want [][]string
+	}{
+		{
+			name: "should tell about a registration",
+			do:   func(s *Service) { s.Register("B1", "slackuser", testNow()) },
+			want: [][]string{{"registered", formatKey("B1", testNow())}},
+		},
+		{
+			name: "should tell about a range registration once",
+			do: func(s *Service) {
+				s.Register("B1", "slackuser", tomorrow)
+				s.RegisterRange("B1", "slackuser", []time.Time{testNow(), tomorrow, tomorrow.AddDate(0, 0, 1)})
+			},
+			want: [][]string{
+				{"registered", formatKey("B1", tomorrow)},
+				{"registered", formatKey("B1", testNow()), formatKey("B1", tomorrow.AddDate(0, 0, 1))},
+			},
+		},
+		{
+			name: "should not tell about a failed registration",
+			do: func(s *Service) {
+				s.Register("B1", "slackuser", testNow())
+				s.Register("B1", "slackuser", testNow())
+			},
+			want: [][]string{{"registered", formatKey("B1", testNow())}},
+		},
+		{
+			name: "should tell about claims and releases",
+			do: func(s *Service) {
+				s.Register("B1", "slackuser", testNow())
+				s.Claim("B1", "ponyboy")
+				s.Claim("B1", "sodapop")
+				s.Release("B1", "ponyboy")
+			},
+			want: [][]string{
+				{"registered", formatKey("B1", testNow())},
+				{"claimed", formatKey("B1", testNow())},
+				{"released", formatKey("B1", testNow())},
+			},
+		},
+		{
+			name: "should tell about spots opened by a recurrence",
+			do: func(s *Service) {
+				s.RegisterRecurring("B1", "slackuser", []time.Weekday{testNow().Weekday()}, time.Time{})
+				s.Find()
+				s.Find()
+			},
+			want: [][]string{{"registered", formatKey("B1", testNow()), formatKey("B1", testNow().AddDate(0, 0, 7)), formatKey("B1", testNow().AddDate(0, 0, 14))}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n := &testNotifier{}
+			tt.do(newTestService().WithNotifier(n))
+			assert.Equal(t, tt.want, n.kinds())
+			for _, e := range n.events {
+				assert.Equal(t, testNow().Format(util.SpotDateFormat), e.Today)
+			}
+		})
+	}
+}
+
+func TestService_WithNotifierClaimed(t *testing.T) {
+	n := &testNotifier{}
+	s := newTestService().WithNotifier(n)
+	s.Register("B1", "slackuser", testNow())
+	s.Claim("B1", "ponyboy")
+	assert.Equal(t, "", n.events[0].Spots[0].ClaimedBy)
+	assert.Equal(t, "ponyboy", n.events[1].Spots[0].ClaimedBy, "should tell who claimed the spot")
+}
+
+func TestLocationNotifiers_Notify(t *testing.T) {
+	downtown, other := &testNotifier{}, &testNotifier{}
+	n := LocationNotifiers{"downtown": downtown, "": other}
+	s := newTestService().WithNotifier(n)
+	s.At("downtown").Register("12", "slackuser", testNow())
+	s.At("uptown").Register("12", "slackuser", testNow())
+	assert.Equal(t, [][]string{{"registered", "downtown/" + formatKey("12", testNow())}}, downtown.kinds(), "should tell the notifier of the location")
+	assert.Equal(t, [][]string{{"registered", "uptown/" + formatKey("12", testNow())}}, other.kinds(), "should tell the default notifier about other locations")
+}
+
+func TestService_WithNotifierRangeHeld(t *testing.T) {
+	n := &testNotifier{}
+	s := newTestService().WithNotifier(n)
+	tomorrow := testNow().AddDate(0, 0, 1)
+	s.Want("ponyboy", tomorrow)
+	s.RegisterRange("B1", "slackuser", []time.Time{testNow(), tomorrow})
+	assert.Equal(t, "", n.events[0].Spots[0].HeldFor)
+	assert.Equal(t, "ponyboy", n.events[0].Spots[1].HeldFor, "should tell about the spot held for the waitlist as held")
+}
@@ -0,0 +1,71 @@
+package spot
+
+import (
+	"errors"
+
+	"github.com/jasonholmberg/slashspot/internal/data"
+	"github.com/jasonholmberg/slashspot/internal/util"
+)
+
+const (
+	// DefaultClaimHorizon - how many days ahead spots can be claimed when not told otherwise
+	DefaultClaimHorizon = 14
+
+	// DefaultClaimLimit - how many claims for later dates a user can have when not told otherwise
+	DefaultClaimLimit = 3
+)
+
+var (
+	// ErrBeyondHorizon - returned when a spot is claimed for a date too far ahead
+	ErrBeyondHorizon = errors.New("date is beyond the claim horizon")
+
+	// ErrClaimLimit - returned when a user already has as many claims for later dates as they may
+	ErrClaimLimit = errors.New("too many claims for later dates")
+)
+
+// WithFutureClaims - a copy of the service sharing its store where spots can be claimed up to horizon days ahead,
+// and each user can have at most limit claims for dates after today. A horizon of 0 only lets spots be claimed today,
+// a limit of 0 leaves the number of claims for later dates unlimited.
+func (s *Service) WithFutureClaims(horizon int, limit int) *Service {
+	with := *s
+	with.claimHorizon = horizon
+	with.claimLimit = limit
+	return &with
+}
+
+// ClaimHorizon - how many days ahead spots can be claimed
+func (s *Service) ClaimHorizon() int {
+	return s.claimHorizon
+}
+
+// ClaimLimit - how many claims for later dates a user can have, 0 when there is no limit
+func (s *Service) ClaimLimit() int {
+	return s.claimLimit
+}
+
+// horizon - the last date spots can be claimed for
+func (s *Service) horizon() string {
+	return s.Now().AddDate(0, 0, s.claimHorizon).Format(util.SpotDateFormat)
+}
+
+// checkClaimLimit - returns ErrClaimLimit if the user already has as many claims for later dates as they may
+func (s *Service) checkClaimLimit(r data.Reader, user string) error {
+	if s.claimLimit <= 0 {
+		return nil
+	}
+	tomorrow := s.Now().AddDate(0, 0, 1).Format(util.SpotDateFormat)
+	spots, err := r.ListByDate(tomorrow, "")
+	if err != nil {
+		return err
+	}
+	claims := 0
+	for _, spot := range spots {
+		if _, ok := spot.ClaimOf(user); ok {
+			claims++
+		}
+	}
+	if claims >= s.claimLimit {
+		return ErrClaimLimit
+	}
+	return nil
+}
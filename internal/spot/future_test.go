@@ -0,0 +1,73 @@
+package spot
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestService_ClaimOn(t *testing.T) {
+	s := newTestService().WithFutureClaims(7, 2)
+	for days := -1; days <= 8; days++ {
+		s.Register("B1", "slackuser", testNow().AddDate(0, 0, days))
+	}
+	s.Register("B2", "slackuser", testNow().AddDate(0, 0, 3))
+	tests := []struct {
+		name    string
+		days    int
+		want    string
+		wantErr error
+	}{
+		{name: "should claim a spot for a later date", days: 1, want: "B1"},
+		{name: "should claim a spot on the horizon", days: 7, want: "B1"},
+		{name: "should not claim a spot beyond the horizon", days: 8, wantErr: ErrBeyondHorizon},
+		{name: "should not claim more spots for later dates than the limit", days: 2, wantErr: ErrClaimLimit},
+		{name: "should still claim a spot for today", days: 0, want: "B1"},
+		{name: "should not claim a spot for a past date", days: -1, want: NotAvailable},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := s.ClaimOn("B1", "ponyboy", testNow().AddDate(0, 0, tt.days))
+			if tt.wantErr != nil {
+				assert.Equal(t, tt.wantErr, err)
+				return
+			}
+			assert.Equal(t, tt.want, got.ID)
+		})
+	}
+
+	_, err := s.ClaimOn("B2", "sodapop", testNow().AddDate(0, 0, 3))
+	assert.NoError(t, err, "should count the limit per user")
+
+	_, err = s.ReleaseOn("B1", "ponyboy", testNow().AddDate(0, 0, 1))
+	assert.NoError(t, err)
+	_, err = s.ClaimOn("B1", "ponyboy", testNow().AddDate(0, 0, 2))
+	assert.NoError(t, err, "should claim again after releasing a claim for a later date")
+
+	_, err = newTestService().WithFutureClaims(0, 0).ClaimOn("B1", "ponyboy", testNow().AddDate(0, 0, 1))
+	assert.Equal(t, ErrBeyondHorizon, err, "should only claim today with no horizon")
+}
+
+func TestService_WithFutureClaims(t *testing.T) {
+	s := newTestService()
+	assert.Equal(t, DefaultClaimHorizon, s.ClaimHorizon())
+	assert.Equal(t, DefaultClaimLimit, s.ClaimLimit())
+	with := s.WithFutureClaims(3, 0)
+	assert.Equal(t, 3, with.ClaimHorizon())
+	assert.Equal(t, 0, with.ClaimLimit())
+	assert.Equal(t, DefaultClaimHorizon, s.ClaimHorizon(), "should not change the original service")
+}
+
+func TestService_ReleaseOnPastDate(t *testing.T) {
+	clock := &testClock{now: testNow()}
+	s := newTestService().WithClock(clock)
+	s.Register("B1", "slackuser", testNow())
+	s.Claim("B1", "ponyboy")
+
+	clock.now = testNow().AddDate(0, 0, 1)
+	got, err := s.ReleaseOn("B1", "ponyboy", testNow())
+	assert.Error(t, err, "should not release a claim for a past date")
+	assert.Equal(t, NotAvailable, got.ID)
+	spot, _ := s.store.Get(formatKey("B1", testNow()))
+	assert.Equal(t, "ponyboy", spot.ClaimedBy, "should keep the past claim")
+}
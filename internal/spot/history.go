@@ -0,0 +1,197 @@
+package spot
+
+import (
+	"errors"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/jasonholmberg/slashspot/internal/data"
+	"github.com/jasonholmberg/slashspot/internal/util"
+)
+
+type (
+	// Stats - how the spots at a location were used from one date to another. A spot day is a spot registered for a
+	// date, it is claimed if anyone still had all or part of it claimed at the end of the day.
+	Stats struct {
+		From  string
+		To    string
+		Spots []SpotUsage
+		Users []UserUsage
+	}
+
+	// SpotUsage - how a spot was used, ordered by spot in Stats
+	SpotUsage struct {
+		ID       string
+		Location string
+
+		// Shared - the spot days the spot was registered for
+		Shared int
+
+		// Claimed - the spot days the spot was claimed
+		Claimed int
+	}
+
+	// UserUsage - how a user shared and claimed spots, ordered by the most spot days claimed and shared in Stats
+	UserUsage struct {
+		User string
+
+		// Shared - the spot days the user registered
+		Shared int
+
+		// SharedClaimed - the spot days the user registered that someone claimed
+		SharedClaimed int
+
+		// Claimed - the spot days the user claimed
+		Claimed int
+	}
+
+	// spotDay - the state of a spot day while the history is replayed
+	spotDay struct {
+		id       string
+		location string
+		holder   string
+		claims   map[string]bool
+	}
+)
+
+// WithHistoryDays - a copy of the service sharing its store that keeps the history of spots open in the last days,
+// older changes are deleted by Refresh. 0 keeps the whole history. Stats and quotas can not count what is deleted.
+func (s *Service) WithHistoryDays(days int) *Service {
+	with := *s
+	with.historyDays = days
+	return &with
+}
+
+// pruneHistory - delete the history of spots open before the days it is kept for
+func (s *Service) pruneHistory(tx data.Tx) error {
+	if s.historyDays <= 0 {
+		return nil
+	}
+	before := s.Now().AddDate(0, 0, -s.historyDays).Format(util.SpotDateFormat)
+	pruned, err := data.PruneHistory(tx, before)
+	if pruned > 0 {
+		log.Printf(">Pruned %d history changes for dates before %v", pruned, before)
+	}
+	return err
+}
+
+// Shared - the spot days registered by everyone
+func (st Stats) Shared() int {
+	total := 0
+	for _, u := range st.Spots {
+		total += u.Shared
+	}
+	return total
+}
+
+// Claimed - the spot days claimed by everyone
+func (st Stats) Claimed() int {
+	total := 0
+	for _, u := range st.Spots {
+		total += u.Claimed
+	}
+	return total
+}
+
+// User - how the user shared and claimed spots, empty when they did neither
+func (st Stats) User(user string) UserUsage {
+	for _, u := range st.Users {
+		if u.User == user {
+			return u
+		}
+	}
+	return UserUsage{User: user}
+}
+
+// Utilization - the share of the spot days that were claimed, 0 when none were registered
+func Utilization(claimed int, shared int) float64 {
+	if shared == 0 {
+		return 0
+	}
+	return float64(claimed) / float64(shared)
+}
+
+// record - add the change to the history of the spot, the change's spot, date, holder and time are filled in
+func (s *Service) record(tx data.Tx, c data.Change, spot data.Spot) error {
+	c.ID = spot.ID
+	c.Location = spot.Location
+	c.Date = spot.OpenDate
+	c.Holder = spot.RegisteredBy
+	c.At = s.Now().Format(time.RFC3339)
+	_, err := data.AppendChange(tx, c)
+	return err
+}
+
+// Stats - how the spots at the service's location were used from one date to another, inclusive. The history of
+// registrations, claims, releases and drops is replayed, so spots cleaned up since are counted.
+func (s *Service) Stats(from time.Time, to time.Time) (Stats, error) {
+	st := Stats{From: from.Format(util.SpotDateFormat), To: to.Format(util.SpotDateFormat)}
+	changes, err := data.ListHistory(s.store, st.From, st.To)
+	if err != nil {
+		return Stats{}, errors.New("error loading spot data")
+	}
+	days := make(map[string]*spotDay)
+	for _, c := range changes {
+		if c.Location != s.location {
+			continue
+		}
+		key := data.Spot{ID: c.ID, OpenDate: c.Date, Location: c.Location}.Key()
+		day, ok := days[key]
+		// A spot registered before the history was kept is first seen when it is claimed or released
+		if !ok || c.Kind == data.HistoryRegistered {
+			day = &spotDay{id: c.ID, location: c.Location, holder: c.Holder, claims: make(map[string]bool)}
+			days[key] = day
+		}
+		switch c.Kind {
+		case data.HistoryClaimed:
+			day.claims[c.User] = true
+		case data.HistoryReleased:
+			delete(day.claims, c.User)
+		case data.HistoryDropped:
+			delete(days, key)
+		}
+	}
+	spots := make(map[string]*SpotUsage)
+	users := make(map[string]*UserUsage)
+	user := func(id string) *UserUsage {
+		if _, ok := users[id]; !ok {
+			users[id] = &UserUsage{User: id}
+		}
+		return users[id]
+	}
+	for _, day := range days {
+		if _, ok := spots[day.id]; !ok {
+			spots[day.id] = &SpotUsage{ID: day.id, Location: day.location}
+		}
+		spots[day.id].Shared++
+		user(day.holder).Shared++
+		if len(day.claims) > 0 {
+			spots[day.id].Claimed++
+			user(day.holder).SharedClaimed++
+		}
+		for claimant := range day.claims {
+			user(claimant).Claimed++
+		}
+	}
+	for _, u := range spots {
+		st.Spots = append(st.Spots, *u)
+	}
+	sort.Slice(st.Spots, func(i, j int) bool {
+		return st.Spots[i].ID < st.Spots[j].ID
+	})
+	for _, u := range users {
+		st.Users = append(st.Users, *u)
+	}
+	sort.Slice(st.Users, func(i, j int) bool {
+		a, b := st.Users[i], st.Users[j]
+		if a.Claimed != b.Claimed {
+			return a.Claimed > b.Claimed
+		}
+		if a.Shared != b.Shared {
+			return a.Shared > b.Shared
+		}
+		return a.User < b.User
+	})
+	return st, nil
+}
@@ -0,0 +1,89 @@
+package spot
+
+import (
+	"testing"
+
+	"github.com/jasonholmberg/slashspot/internal/data"
+	"github.com/jasonholmberg/slashspot/internal/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestService_History(t *testing.T) {
+	s := newTestService().WithAdmins("boss")
+	tomorrow := testNow().AddDate(0, 0, 1)
+	s.Register("B1", "slackuser", testNow())
+	s.Register("B2", "slackuser", testNow())
+	s.Claim("B1", "ponyboy")
+	s.Release("B1", "ponyboy")
+	s.DropRegistration("B2", "slackuser")
+	s.Register("B1", "slackuser", tomorrow)
+	s.ForceDrop("B1", tomorrow, "boss")
+
+	changes, err := data.ListHistory(s.store, "", "")
+	assert.Nil(t, err)
+	var got [][]string
+	for _, c := range changes {
+		got = append(got, []string{c.Kind, c.ID, c.Date, c.Holder, c.User, c.By})
+	}
+	today, later := testNow().Format(util.SpotDateFormat), tomorrow.Format(util.SpotDateFormat)
+	assert.Equal(t, [][]string{
+		{"registered", "B1", today, "slackuser", "", ""},
+		{"registered", "B2", today, "slackuser", "", ""},
+		{"claimed", "B1", today, "slackuser", "ponyboy", ""},
+		{"released", "B1", today, "slackuser", "ponyboy", ""},
+		{"dropped", "B2", today, "slackuser", "", ""},
+		{"registered", "B1", later, "slackuser", "", ""},
+		{"dropped", "B1", later, "slackuser", "", "boss"},
+	}, got, "should keep every registration, claim, release and drop")
+}
+
+func TestService_WithHistoryDays(t *testing.T) {
+	clock := &testClock{now: testNow()}
+	s := newTestService().WithClock(clock)
+	s.Register("B1", "slackuser", testNow())
+	s.Register("B1", "slackuser", testNow().AddDate(0, 0, 2))
+	clock.now = testNow().AddDate(0, 0, 3)
+
+	assert.Nil(t, s.Refresh())
+	changes, _ := data.ListHistory(s.store, "", "")
+	assert.Equal(t, 2, len(changes), "should keep the whole history by default")
+
+	assert.Nil(t, s.WithHistoryDays(2).Refresh())
+	changes, _ = data.ListHistory(s.store, "", "")
+	assert.Equal(t, 1, len(changes), "should delete the history older than the days it is kept for")
+	assert.Equal(t, testNow().AddDate(0, 0, 2).Format(util.SpotDateFormat), changes[0].Date)
+}
+
+func TestService_Stats(t *testing.T) {
+	s := newTestService()
+	tomorrow := testNow().AddDate(0, 0, 1)
+	s.Register("B1", "slackuser", testNow())
+	s.Register("B1", "slackuser", tomorrow)
+	s.Register("B2", "sodapop", testNow())
+	s.Register("B3", "sodapop", testNow())
+	s.Claim("B1", "ponyboy")
+	s.ClaimOn("B1", "darry", tomorrow)
+	s.Claim("B2", "ponyboy")
+	s.Release("B2", "ponyboy")
+	s.DropRegistration("B3", "sodapop")
+	s.At("uptown").Register("B1", "slackuser", testNow())
+
+	got, err := s.Stats(testNow(), tomorrow)
+	assert.Nil(t, err)
+	assert.Equal(t, []SpotUsage{{ID: "B1", Shared: 2, Claimed: 2}, {ID: "B2", Shared: 1}}, got.Spots, "should count the spot days of the location")
+	assert.Equal(t, 3, got.Shared())
+	assert.Equal(t, 2, got.Claimed())
+	assert.Equal(t, UserUsage{User: "slackuser", Shared: 2, SharedClaimed: 2}, got.User("slackuser"))
+	assert.Equal(t, UserUsage{User: "sodapop", Shared: 1}, got.User("sodapop"), "should not count dropped registrations")
+	assert.Equal(t, UserUsage{User: "ponyboy", Claimed: 1}, got.User("ponyboy"), "should not count released claims")
+	var order []string
+	for _, u := range got.Users {
+		order = append(order, u.User)
+	}
+	assert.Equal(t, []string{"darry", "ponyboy", "slackuser", "sodapop"}, order, "should order users by the spot days they claimed, then shared")
+
+	got, _ = s.Stats(tomorrow, tomorrow)
+	assert.Equal(t, []SpotUsage{{ID: "B1", Shared: 1, Claimed: 1}}, got.Spots, "should only count the dates asked for")
+	assert.Equal(t, 0.5, Utilization(1, 2))
+	assert.Equal(t, 0.0, Utilization(0, 0))
+}
@@ -0,0 +1,122 @@
+package spot
+
+import (
+	"errors"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/jasonholmberg/slashspot/internal/data"
+)
+
+// ErrNotHolder - returned when a user registers a spot someone else holds
+var ErrNotHolder = errors.New("spot is held by someone else")
+
+// Holder - who holds a spot, an admin's assignment takes the place of the catalog. Empty when no one does.
+func (s *Service) Holder(id string) (string, error) {
+	holder, err := s.holderOf(s.store, id)
+	if err != nil {
+		return "", errors.New("error loading spot data")
+	}
+	return holder, nil
+}
+
+// AssignHolder - make the user the holder of a spot, an empty holder leaves the spot unheld. Only admins can
+// assign spots.
+func (s *Service) AssignHolder(id string, holder string, admin string) (data.Assignment, error) {
+	if !s.IsAdmin(admin) {
+		return data.Assignment{}, ErrNotAdmin
+	}
+	a := data.Assignment{
+		ID:         id,
+		Holder:     holder,
+		AssignedBy: admin,
+		AssignedAt: s.Now().Format(time.RFC3339),
+		Location:   s.location,
+	}
+	err := s.store.Update(func(tx data.Tx) error {
+		if err := s.checkCatalog(tx, id); err != nil {
+			return err
+		}
+		return data.PutAssignment(tx, a)
+	})
+	if err == ErrUnknownSpot {
+		return data.Assignment{}, err
+	}
+	if err != nil {
+		return data.Assignment{}, errors.New("error saving spot data")
+	}
+	log.Printf("Spot %v assigned to %q by admin %v", id, holder, admin)
+	return a, nil
+}
+
+// holderOf - who holds a spot at the service's location, empty when no one does
+func (s *Service) holderOf(r data.Reader, id string) (string, error) {
+	key := data.LocationKey(s.location, id)
+	a, err := data.GetAssignment(r, key)
+	if err == nil {
+		return a.Holder, nil
+	}
+	if err != data.ErrNotFound {
+		return "", err
+	}
+	c, err := data.GetCatalogSpot(r, key)
+	if err == data.ErrNotFound {
+		return "", nil
+	}
+	return c.Holder, err
+}
+
+// checkSpot - make sure the user can register a spot. The spot must be in the catalog, if there is one, and a spot
+// with a holder can only be registered by the holder or an admin.
+func (s *Service) checkSpot(r data.Reader, id string, user string) error {
+	if err := s.checkCatalog(r, id); err != nil {
+		return err
+	}
+	if s.IsAdmin(user) {
+		return nil
+	}
+	holder, err := s.holderOf(r, id)
+	if err != nil {
+		return err
+	}
+	if holder != "" && holder != user {
+		return ErrNotHolder
+	}
+	return nil
+}
+
+// RegistrableSpots - the spots in the catalog at the service's location the user holds ordered by ID, by the catalog
+// or an admin's assignment. Every spot for admins. Empty when there is no catalog or the user holds no spot.
+func (s *Service) RegistrableSpots(user string) ([]string, error) {
+	var ids []string
+	err := s.store.View(func(r data.Reader) error {
+		known, err := data.ListCatalog(r)
+		if err != nil {
+			return err
+		}
+		assigned, err := data.ListAssignments(r)
+		if err != nil {
+			return err
+		}
+		admin := s.IsAdmin(user)
+		for k, c := range known {
+			if c.Location != s.location {
+				continue
+			}
+			holder := c.Holder
+			if a, ok := assigned[k]; ok {
+				holder = a.Holder
+			}
+			if admin || holder == user {
+				ids = append(ids, c.ID)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, errors.New("error loading spot data")
+	}
+	sort.Strings(ids)
+	return ids, nil
+}
@@ -0,0 +1,99 @@
+package spot
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jasonholmberg/slashspot/internal/data"
+	"github.com/jasonholmberg/slashspot/internal/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestService_Holder(t *testing.T) {
+	s := newTestService().WithAdmins("boss")
+	s.ImportCatalog([]data.CatalogSpot{{ID: "B1", Holder: "slackuser"}, {ID: "B2"}})
+
+	got, _ := s.Holder("B1")
+	assert.Equal(t, "slackuser", got, "should hold a spot by the catalog")
+	got, _ = s.Holder("B2")
+	assert.Equal(t, "", got, "should not hold a spot without a holder")
+
+	_, err := s.AssignHolder("B2", "pparker", "slackuser")
+	assert.NotNil(t, err, "should only let admins assign spots")
+	_, err = s.AssignHolder("Z9", "pparker", "boss")
+	assert.Equal(t, ErrUnknownSpot, err)
+
+	a, err := s.AssignHolder("B1", "pparker", "boss")
+	assert.Nil(t, err)
+	assert.Equal(t, data.Assignment{ID: "B1", Holder: "pparker", AssignedBy: "boss", AssignedAt: testNow().Format(time.RFC3339)}, a)
+	got, _ = s.Holder("B1")
+	assert.Equal(t, "pparker", got, "should hold a spot by assignment over the catalog")
+
+	s.AssignHolder("B1", "", "boss")
+	got, _ = s.Holder("B1")
+	assert.Equal(t, "", got, "should unassign a spot")
+}
+
+func TestService_RegisterHeldSpot(t *testing.T) {
+	s := newTestService().WithAdmins("boss")
+	s.ImportCatalog([]data.CatalogSpot{{ID: "B1", Holder: "slackuser"}, {ID: "B2"}})
+	tests := []struct {
+		name    string
+		id      string
+		user    string
+		wantErr error
+	}{
+		{name: "should let the holder register", id: "B1", user: "slackuser"},
+		{name: "should not let anyone else register", id: "B1", user: "pparker", wantErr: ErrNotHolder},
+		{name: "should let an admin register", id: "B1", user: "boss"},
+		{name: "should let anyone register a spot without a holder", id: "B2", user: "pparker"},
+	}
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := s.Register(tt.id, tt.user, testNow().AddDate(0, 0, i))
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+	_, err := s.RegisterRange("B1", "pparker", []time.Time{testNow()})
+	assert.Equal(t, ErrNotHolder, err)
+	_, err = s.RegisterRecurring("B1", "pparker", []time.Weekday{time.Monday}, time.Time{})
+	assert.Equal(t, ErrNotHolder, err)
+}
+
+func TestService_RecurrenceOfHeldSpot(t *testing.T) {
+	s := newTestService().WithAdmins("boss")
+	s.ImportCatalog([]data.CatalogSpot{{ID: "B1", Holder: "slackuser"}})
+	s.RegisterRecurring("B1", "slackuser", []time.Weekday{testNow().Weekday()}, time.Time{})
+	s.AssignHolder("B1", "pparker", "boss")
+
+	later := s.WithClock(util.FixedClock(testNow().AddDate(0, 0, 21)))
+	_, err := later.Find()
+	assert.NotNil(t, err, "should not register a recurrence for a spot its user no longer holds")
+}
+
+func TestService_RegistrableSpots(t *testing.T) {
+	s := newTestService().WithAdmins("boss")
+	got, err := s.RegistrableSpots("pparker")
+	assert.Nil(t, err)
+	assert.Empty(t, got, "should not list spots without a catalog")
+
+	s.ImportCatalog([]data.CatalogSpot{{ID: "B3", Holder: "slackuser"}, {ID: "B2"}, {ID: "B1", Holder: "pparker"}})
+	s.AssignHolder("B3", "mjane", "boss")
+	tests := []struct {
+		name string
+		user string
+		want []string
+	}{
+		{name: "should list only the spots the user holds", user: "pparker", want: []string{"B1"}},
+		{name: "should list spots held by assignment", user: "mjane", want: []string{"B3"}},
+		{name: "should not list spots no longer held", user: "slackuser"},
+		{name: "should list every spot for admins", user: "boss", want: []string{"B1", "B2", "B3"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := s.RegistrableSpots(tt.user)
+			assert.Nil(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package spot
+
+import (
+	"errors"
+	"time"
+
+	"github.com/jasonholmberg/slashspot/internal/data"
+)
+
+// At - a copy of the service sharing its store that finds, claims and registers the spots at the location. Spot IDs
+// are only unique within a location, so every spot, catalog spot, waitlist and draw belongs to one. A user's own
+// registrations and claims are listed and counted towards their limits wherever they are.
+func (s *Service) At(location string) *Service {
+	at := *s
+	at.location = location
+	return &at
+}
+
+// Where - the location the service finds, claims and registers spots at, empty for no location
+func (s *Service) Where() string {
+	return s.location
+}
+
+// DefaultLocation - the location the user finds, claims and registers spots at unless they say otherwise, empty when
+// they have not picked one
+func (s *Service) DefaultLocation(id string) string {
+	user, err := data.GetUser(s.store, id)
+	if err != nil {
+		return ""
+	}
+	return user.Location
+}
+
+// SetDefaultLocation - make the location the one the user finds, claims and registers spots at unless they say
+// otherwise
+func (s *Service) SetDefaultLocation(id string, location string) error {
+	err := s.store.Update(func(tx data.Tx) error {
+		user, err := data.GetUser(tx, id)
+		if err == data.ErrNotFound {
+			user = data.User{ID: id}
+		} else if err != nil {
+			return err
+		}
+		user.Location = location
+		return data.PutUser(tx, user)
+	})
+	if err != nil {
+		return errors.New("error saving spot data")
+	}
+	return nil
+}
+
+// spotKey - the key of a spot at the service's location on the date
+func (s *Service) spotKey(id string, date time.Time) string {
+	return data.LocationKey(s.location, formatKey(id, date))
+}
+
+// here - returns true if the spot is at the service's location
+func (s *Service) here(spot data.Spot) bool {
+	return spot.Location == s.location
+}
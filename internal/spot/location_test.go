@@ -0,0 +1,82 @@
+package spot
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jasonholmberg/slashspot/internal/data"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestService_At(t *testing.T) {
+	s := newTestService()
+	downtown, uptown := s.At("downtown"), s.At("uptown")
+	assert.Equal(t, "downtown", downtown.Where())
+	assert.Equal(t, "", s.Where(), "should leave the service it was copied from at no location")
+
+	_, err := downtown.Register("12", "slackuser", testNow())
+	assert.Nil(t, err)
+	_, err = uptown.Register("12", "sodapop", testNow())
+	assert.Nil(t, err, "should register the same spot ID at another location")
+
+	found, err := downtown.Find()
+	assert.Nil(t, err)
+	assert.Len(t, found, 1, "should only find the spots at the location")
+	for _, spot := range found {
+		assert.Equal(t, "downtown", spot.Location)
+	}
+	_, err = s.Find()
+	assert.NotNil(t, err, "should not find spots at other locations")
+
+	claimed, err := uptown.Claim("12", "ponyboy")
+	assert.Nil(t, err)
+	assert.Equal(t, "sodapop", claimed.RegisteredBy, "should claim the spot at the location")
+	_, err = downtown.Claim("12", "ponyboy")
+	assert.Nil(t, err, "should leave the spot at the other location open")
+
+	regs, _ := s.Registrations("slackuser")
+	assert.Len(t, regs, 1, "should list the user's registrations at every location")
+	admin := downtown.WithAdmins("U1")
+	all, _ := admin.AllRegistrations("U1")
+	assert.Len(t, all, 1, "should only list the registrations at the location for admins")
+}
+
+func TestService_AtCatalog(t *testing.T) {
+	s := newTestService()
+	s.ImportCatalog([]data.CatalogSpot{
+		{ID: "12", Location: "downtown", Holder: "slackuser"},
+		{ID: "12", Location: "uptown"},
+	})
+	_, err := s.At("downtown").Register("12", "sodapop", time.Time{})
+	assert.Equal(t, ErrNotHolder, err, "should check the holder at the location")
+	_, err = s.At("uptown").Register("12", "sodapop", time.Time{})
+	assert.Nil(t, err, "should not check the holder at another location")
+	_, err = s.At("uptown").Register("13", "sodapop", time.Time{})
+	assert.Equal(t, ErrUnknownSpot, err)
+	_, err = s.At("harbor").Register("13", "sodapop", time.Time{})
+	assert.Nil(t, err, "should register any spot at a location without a catalog")
+	got, _ := s.At("uptown").Catalog()
+	assert.Equal(t, []data.CatalogSpot{{ID: "12", Location: "uptown"}}, got)
+}
+
+func TestService_AtWaitlist(t *testing.T) {
+	s := newTestService()
+	s.At("downtown").Want("ponyboy", testNow())
+	spot, _ := s.At("uptown").Register("12", "slackuser", testNow())
+	assert.Equal(t, "", spot.HeldFor)
+	got, _ := s.store.Get(spot.Key())
+	assert.Equal(t, "", got.HeldFor, "should not hold a spot for users waiting at another location")
+	spot, _ = s.At("downtown").Register("12", "slackuser", testNow())
+	got, _ = s.store.Get(spot.Key())
+	assert.Equal(t, "ponyboy", got.HeldFor, "should hold a spot for users waiting at the location")
+}
+
+func TestService_DefaultLocation(t *testing.T) {
+	s := newTestService()
+	assert.Equal(t, "", s.DefaultLocation("U1"))
+	assert.Nil(t, s.SetDefaultLocation("U1", "downtown"))
+	assert.Equal(t, "downtown", s.DefaultLocation("U1"))
+	s.RememberUser(data.User{ID: "U1", Name: "slackuser"})
+	assert.Equal(t, "downtown", s.DefaultLocation("U1"), "should keep the default location when the user is seen again")
+	assert.Equal(t, "slackuser", s.UserName("U1"))
+}
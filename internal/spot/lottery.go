@@ -0,0 +1,322 @@
+package spot
+
+import (
+	"errors"
+	"log"
+	"math/rand"
+	"sort"
+	"time"
+
+	"github.com/jasonholmberg/slashspot/internal/data"
+	"github.com/jasonholmberg/slashspot/internal/util"
+)
+
+const (
+	// LotteryWon - a user won a spot in a draw
+	LotteryWon EventKind = "won"
+
+	// LotteryLost - users who entered a draw did not win a spot
+	LotteryLost EventKind = "lost"
+
+	// recentWinDays - how many days back a win makes a user less likely to win a weighted draw
+	recentWinDays = 30
+)
+
+var (
+	// ErrLotteryOff - returned when the lottery is used while spots are first come first served
+	ErrLotteryOff = errors.New("the lottery is off")
+
+	// ErrAlreadyEntered - returned when a user is already in the draw of a date
+	ErrAlreadyEntered = errors.New("already entered in the draw")
+
+	// ErrNotEntered - returned when a user is not in the draw of a date
+	ErrNotEntered = errors.New("not entered in the draw")
+
+	// ErrDrawClosed - returned when the draw of a date is past its cutoff or already drawn
+	ErrDrawClosed = errors.New("the draw is closed")
+
+	// ErrDrawPending - returned when a spot is claimed before its date's draw
+	ErrDrawPending = errors.New("spots are given out by a draw")
+)
+
+// WithLottery - a copy of the service sharing its store that gives out the spots of a date by a draw. Users enter
+// until the cutoff, the time after the start of the date in the service's location, and spots can not be claimed
+// until the draw is made. Copies in other locations keep that cutoff, so every user's draw closes when it is made.
+// Spots left over after the draw are first come first served. A weighted draw makes users who won in the last 30 days
+// less likely to win.
+func (s *Service) WithLottery(cutoff time.Duration, weighted bool) *Service {
+	with := *s
+	with.lottery = true
+	with.cutoff = cutoff
+	with.weighted = weighted
+	with.drawLoc = s.loc
+	return &with
+}
+
+// Lottery - returns true if spots are given out by a draw
+func (s *Service) Lottery() bool {
+	return s.lottery
+}
+
+// Cutoff - when entries for the draw of the date close, in the service's location
+func (s *Service) Cutoff(date time.Time) time.Time {
+	day, _ := util.ParseTime(date.Format(util.SpotDateFormat), s.drawLocation())
+	return day.Add(s.cutoff).In(s.loc)
+}
+
+// drawLocation - the location the days of draws start and end in
+func (s *Service) drawLocation() *time.Location {
+	if s.drawLoc == nil {
+		return s.loc
+	}
+	return s.drawLoc
+}
+
+// DrawPending - returns true if the spots of the date at the service's location are waiting for a draw
+func (s *Service) DrawPending(date time.Time) bool {
+	pending, err := s.drawPending(s.store, s.location, date.Format(util.SpotDateFormat))
+	return err == nil && pending
+}
+
+// drawPending - returns true if the spots of the date at the location are waiting for a draw
+func (s *Service) drawPending(r data.Reader, location string, date string) (bool, error) {
+	if !s.lottery {
+		return false, nil
+	}
+	_, err := data.GetDraw(r, data.LocationKey(location, date))
+	if err == data.ErrNotFound {
+		return true, nil
+	}
+	return false, err
+}
+
+// Enter - enter the user in the draw for the spots of the date at the service's location
+func (s *Service) Enter(user string, date time.Time) error {
+	if !s.lottery {
+		return ErrLotteryOff
+	}
+	if !s.Now().Before(s.Cutoff(date)) {
+		return ErrDrawClosed
+	}
+	entry := data.Entry{
+		Date:      date.Format(util.SpotDateFormat),
+		User:      user,
+		EnteredAt: s.Now().Format(time.RFC3339),
+		Location:  s.location,
+	}
+	err := s.store.Update(func(tx data.Tx) error {
+		if pending, err := s.drawPending(tx, s.location, entry.Date); err != nil || !pending {
+			if err != nil {
+				return err
+			}
+			return ErrDrawClosed
+		}
+		if _, err := data.GetEntry(tx, entry.Key()); err == nil {
+			return ErrAlreadyEntered
+		}
+		return data.PutEntry(tx, entry)
+	})
+	if err == ErrDrawClosed || err == ErrAlreadyEntered {
+		return err
+	}
+	if err != nil {
+		return errors.New("error saving spot data")
+	}
+	log.Printf("%v entered the draw for date: %v", user, entry.Date)
+	return nil
+}
+
+// CancelEntry - take the user out of the draw of the date at the service's location
+func (s *Service) CancelEntry(user string, date time.Time) error {
+	key := data.Entry{Date: date.Format(util.SpotDateFormat), User: user, Location: s.location}.Key()
+	err := s.store.Update(func(tx data.Tx) error {
+		if _, err := data.GetEntry(tx, key); err != nil {
+			return err
+		}
+		return data.DeleteEntry(tx, key)
+	})
+	if err == data.ErrNotFound {
+		return ErrNotEntered
+	}
+	if err != nil {
+		return errors.New("error saving spot data")
+	}
+	return nil
+}
+
+// DrawOf - the draw made for the date at the service's location
+func (s *Service) DrawOf(date time.Time) (data.Draw, error) {
+	return data.GetDraw(s.store, data.LocationKey(s.location, date.Format(util.SpotDateFormat)))
+}
+
+// DrawDue - make today's draw at the service's location once its cutoff has passed, unless it has been made
+func (s *Service) DrawDue() error {
+	today := s.Now().In(s.drawLocation())
+	if !s.lottery || today.Before(s.Cutoff(today)) {
+		return nil
+	}
+	if _, err := s.DrawOf(today); err != data.ErrNotFound {
+		return err
+	}
+	_, err := s.Draw(today)
+	return err
+}
+
+// Draw - give out the open spots of the date at the service's location to the users who entered its draw at random,
+// and tell the winners and losers. The seed is kept with the draw so it can be checked.
+func (s *Service) Draw(date time.Time) (data.Draw, error) {
+	return s.draw(date.Format(util.SpotDateFormat), s.Now().UnixNano())
+}
+
+func (s *Service) draw(date string, seed int64) (data.Draw, error) {
+	if !s.lottery {
+		return data.Draw{}, ErrLotteryOff
+	}
+	var draw data.Draw
+	var events []Event
+	err := s.store.Update(func(tx data.Tx) error {
+		draw = data.Draw{Date: date, Seed: seed, Weighted: s.weighted, DrawnAt: s.Now().Format(time.RFC3339), Location: s.location}
+		var err error
+		// Recurring spots opening today are registered first so they are drawn too
+		if events, err = s.refresh(tx); err != nil {
+			return err
+		}
+		if pending, err := s.drawPending(tx, s.location, date); err != nil || !pending {
+			if err != nil {
+				return err
+			}
+			return ErrDrawClosed
+		}
+		all, err := data.ListEntries(tx)
+		if err != nil {
+			return err
+		}
+		for k, e := range all {
+			if e.Location != s.location || e.Date != date {
+				continue
+			}
+			draw.Entrants = append(draw.Entrants, e.User)
+			if err := data.DeleteEntry(tx, k); err != nil {
+				return err
+			}
+		}
+		sort.Strings(draw.Entrants)
+		weights, err := s.drawWeights(tx, date, draw.Entrants)
+		if err != nil {
+			return err
+		}
+		order := drawOrder(rand.New(rand.NewSource(seed)), draw.Entrants, weights)
+
+		spots, err := tx.ListByDate(date, date)
+		if err != nil {
+			return err
+		}
+		keys := make([]string, 0, len(spots))
+		for k, spot := range spots {
+			if s.here(spot) && !spot.IsClaimed() && !spot.IsShared() {
+				keys = append(keys, k)
+			}
+		}
+		sort.Strings(keys)
+		for i, k := range keys {
+			if i >= len(order) {
+				break
+			}
+			spot := spots[k]
+			spot.ClaimedBy = order[i]
+			spot.ClaimedAt = s.Now().Format(time.RFC3339)
+			if err := tx.Put(spot); err != nil {
+				return err
+			}
+			if err := s.record(tx, data.Change{Kind: data.HistoryClaimed, User: spot.ClaimedBy}, spot); err != nil {
+				return err
+			}
+			draw.Wins = append(draw.Wins, data.Win{Spot: spot.ID, User: spot.ClaimedBy})
+			events = append(events, Event{Kind: LotteryWon, Spots: []data.Spot{spot}, Today: s.today()})
+		}
+		var losers []string
+		for _, user := range draw.Entrants {
+			if !draw.Winner(user) {
+				losers = append(losers, user)
+			}
+		}
+		if len(losers) > 0 {
+			events = append(events, Event{Kind: LotteryLost, Users: losers, Date: date, Today: s.today()})
+		}
+		if err := data.PutDraw(tx, draw); err != nil {
+			return err
+		}
+		// Spots left over go to the users waiting for them
+		offered, err := s.offerOpenSpots(tx, date)
+		if err != nil {
+			return err
+		}
+		events = append(events, offered...)
+		return nil
+	})
+	if err == ErrDrawClosed {
+		return data.Draw{}, err
+	}
+	if err != nil {
+		return data.Draw{}, errors.New("error saving spot data")
+	}
+	log.Printf("Drew %d spots among %d users for date: %v with seed %d", len(draw.Wins), len(draw.Entrants), date, seed)
+	s.notifyAll(events)
+	return draw, nil
+}
+
+// drawWeights - how likely each user is to win, users who won at the service's location in the last days are less
+// likely in a weighted draw
+func (s *Service) drawWeights(r data.Reader, date string, users []string) ([]float64, error) {
+	weights := make([]float64, len(users))
+	for i := range weights {
+		weights[i] = 1
+	}
+	if !s.weighted {
+		return weights, nil
+	}
+	day, err := time.Parse(util.SpotDateFormat, date)
+	if err != nil {
+		return nil, err
+	}
+	since := day.AddDate(0, 0, -recentWinDays).Format(util.SpotDateFormat)
+	past, err := data.ListDraws(r)
+	if err != nil {
+		return nil, err
+	}
+	for i, user := range users {
+		wins := 0
+		for _, d := range past {
+			if d.Location == s.location && d.Date >= since && d.Date < date && d.Winner(user) {
+				wins++
+			}
+		}
+		weights[i] = 1 / float64(1+wins)
+	}
+	return weights, nil
+}
+
+// drawOrder - the users in the order they were drawn, each pick is made from the users left by their weights
+func drawOrder(rng *rand.Rand, users []string, weights []float64) []string {
+	users = append([]string(nil), users...)
+	weights = append([]float64(nil), weights...)
+	var order []string
+	for len(users) > 0 {
+		total := 0.0
+		for _, w := range weights {
+			total += w
+		}
+		pick := rng.Float64() * total
+		i := 0
+		for ; i < len(users)-1; i++ {
+			if pick < weights[i] {
+				break
+			}
+			pick -= weights[i]
+		}
+		order = append(order, users[i])
+		users = append(users[:i], users[i+1:]...)
+		weights = append(weights[:i], weights[i+1:]...)
+	}
+	return order
+}
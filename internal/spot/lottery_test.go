@@ -0,0 +1,172 @@
+package spot
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/jasonholmberg/slashspot/internal/data"
+	"github.com/jasonholmberg/slashspot/internal/util"
+	"github.com/stretchr/testify/assert"
+)
+
+// cutoffForTest - draws close at 10:00, after the test clock's 9:30
+const cutoffForTest = 10 * time.Hour
+
+func TestService_Enter(t *testing.T) {
+	assert.Equal(t, ErrLotteryOff, newTestService().Enter("ponyboy", testNow()))
+
+	s := newTestService().WithLottery(cutoffForTest, false)
+	assert.Nil(t, s.Enter("ponyboy", testNow()))
+	assert.Equal(t, ErrAlreadyEntered, s.Enter("ponyboy", testNow()))
+	assert.Nil(t, s.CancelEntry("ponyboy", testNow()))
+	assert.Equal(t, ErrNotEntered, s.CancelEntry("ponyboy", testNow()))
+
+	late := newTestService().WithClock(util.FixedClock(testNow().Add(time.Hour))).WithLottery(cutoffForTest, false)
+	assert.Equal(t, ErrDrawClosed, late.Enter("ponyboy", testNow()), "should not enter after the cutoff")
+	assert.Nil(t, late.Enter("ponyboy", testNow().AddDate(0, 0, 1)), "should enter a later draw")
+}
+
+func TestService_CutoffIn(t *testing.T) {
+	s := newTestService().WithLottery(cutoffForTest, false)
+	utc := s.In(time.UTC)
+	assert.True(t, s.Cutoff(testNow()).Equal(utc.Cutoff(testNow())), "should close the draw at the same time in every location")
+	assert.Equal(t, time.UTC, utc.Cutoff(testNow()).Location())
+	assert.Nil(t, utc.Enter("ponyboy", testNow()), "should enter until the draw is made")
+	assert.True(t, utc.DrawPending(testNow()))
+	assert.False(t, newTestService().DrawPending(testNow()), "should not wait for a draw without the lottery")
+}
+
+func TestService_Draw(t *testing.T) {
+	n := &testNotifier{}
+	s := newTestService().WithLottery(cutoffForTest, false).WithNotifier(n)
+	s.Register("B1", "slackuser", testNow())
+	s.Register("B2", "slackuser", testNow())
+	for _, user := range []string{"ponyboy", "sodapop", "darry"} {
+		s.Enter(user, testNow())
+	}
+	_, err := s.Claim("B1", "ponyboy")
+	assert.Equal(t, ErrDrawPending, err, "should not claim before the draw")
+
+	draw, err := s.draw(testNow().Format(util.SpotDateFormat), 42)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(42), draw.Seed)
+	assert.Equal(t, []string{"darry", "ponyboy", "sodapop"}, draw.Entrants)
+	assert.Len(t, draw.Wins, 2)
+	for _, win := range draw.Wins {
+		spot, _ := s.store.Get(formatKey(win.Spot, testNow()))
+		assert.Equal(t, win.User, spot.ClaimedBy, "should claim the spots for the winners")
+	}
+	kept, _ := s.DrawOf(testNow())
+	assert.Equal(t, draw, kept, "should keep the draw")
+	entries, _ := data.ListEntries(s.store)
+	assert.Empty(t, entries)
+
+	last := n.events[len(n.events)-1]
+	assert.Equal(t, LotteryLost, last.Kind)
+	assert.Len(t, last.Users, 1)
+	assert.False(t, draw.Winner(last.Users[0]), "should tell the loser")
+
+	_, err = s.Draw(testNow())
+	assert.Equal(t, ErrDrawClosed, err, "should only draw once")
+}
+
+func TestService_DrawIsRepeatable(t *testing.T) {
+	draws := make([]data.Draw, 2)
+	for i := range draws {
+		s := newTestService().WithLottery(cutoffForTest, false)
+		s.Register("B1", "slackuser", testNow())
+		for _, user := range []string{"ponyboy", "sodapop", "darry", "johnny"} {
+			s.Enter(user, testNow())
+		}
+		draws[i], _ = s.draw(testNow().Format(util.SpotDateFormat), 7)
+	}
+	assert.Equal(t, draws[0].Wins, draws[1].Wins, "should draw the same winners from the same seed")
+}
+
+func TestService_DrawLeftoversAreOpen(t *testing.T) {
+	s := newTestService().WithLottery(cutoffForTest, false)
+	s.Register("B1", "slackuser", testNow())
+	s.Register("B2", "slackuser", testNow())
+	s.Enter("ponyboy", testNow())
+	s.Want("sodapop", testNow())
+	s.Draw(testNow())
+
+	b2, _ := s.store.Get(formatKey("B2", testNow()))
+	assert.Equal(t, "sodapop", b2.HeldFor, "should give a spot left over to the waitlist")
+}
+
+func TestService_DrawDue(t *testing.T) {
+	s := newTestService().WithLottery(cutoffForTest, false)
+	s.Register("B1", "slackuser", testNow())
+	s.Enter("ponyboy", testNow())
+	assert.Nil(t, s.DrawDue())
+	_, err := s.DrawOf(testNow())
+	assert.Equal(t, data.ErrNotFound, err, "should not draw before the cutoff")
+
+	after := s.WithClock(util.FixedClock(testNow().Add(time.Hour)))
+	assert.Nil(t, after.DrawDue())
+	draw, err := after.DrawOf(testNow())
+	assert.Nil(t, err)
+	assert.Equal(t, []data.Win{{Spot: "B1", User: "ponyboy"}}, draw.Wins)
+	assert.Nil(t, after.DrawDue(), "should not draw twice")
+}
+
+func TestService_DrawRecurring(t *testing.T) {
+	s := newTestService().WithLottery(cutoffForTest, false)
+	s.RegisterRecurring("B1", "slackuser", []time.Weekday{testNow().Weekday()}, time.Time{})
+	s.Enter("ponyboy", testNow())
+
+	after := s.WithClock(util.FixedClock(testNow().Add(time.Hour)))
+	assert.Nil(t, after.DrawDue())
+	draw, _ := after.DrawOf(testNow())
+	assert.Equal(t, []data.Win{{Spot: "B1", User: "ponyboy"}}, draw.Wins, "should draw the recurring spots of the date")
+}
+
+func TestService_drawWeights(t *testing.T) {
+	s := newTestService().WithLottery(cutoffForTest, true)
+	s.store.Update(func(tx data.Tx) error {
+		data.PutDraw(tx, data.Draw{Date: testNow().AddDate(0, 0, -1).Format(util.SpotDateFormat), Wins: []data.Win{{Spot: "B1", User: "ponyboy"}}})
+		data.PutDraw(tx, data.Draw{Date: testNow().AddDate(0, 0, -2).Format(util.SpotDateFormat), Wins: []data.Win{{Spot: "B1", User: "ponyboy"}}})
+		return data.PutDraw(tx, data.Draw{Date: testNow().AddDate(0, 0, -40).Format(util.SpotDateFormat), Wins: []data.Win{{Spot: "B1", User: "sodapop"}}})
+	})
+	got, err := s.drawWeights(s.store, testNow().Format(util.SpotDateFormat), []string{"ponyboy", "sodapop"})
+	assert.Nil(t, err)
+	assert.Equal(t, []float64{1.0 / 3, 1}, got, "should only count recent wins")
+
+	got, _ = s.WithLottery(cutoffForTest, false).drawWeights(s.store, testNow().Format(util.SpotDateFormat), []string{"ponyboy", "sodapop"})
+	assert.Equal(t, []float64{1, 1}, got)
+}
+
+func Test_drawOrder(t *testing.T) {
+	users := []string{"ponyboy", "sodapop", "darry"}
+	got := drawOrder(rand.New(rand.NewSource(1)), users, []float64{1, 1, 1})
+	assert.ElementsMatch(t, users, got, "should draw every user once")
+	assert.Equal(t, []string{"ponyboy", "sodapop", "darry"}, users, "should not change the users")
+
+	first := map[string]int{}
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 1000; i++ {
+		first[drawOrder(rng, users, []float64{0.01, 1, 0.01})[0]]++
+	}
+	assert.True(t, first["sodapop"] > 900, "should draw heavier users first more often")
+}
+
+func TestService_DrawAt(t *testing.T) {
+	s := newTestService().WithLottery(cutoffForTest, false)
+	downtown, uptown := s.At("downtown"), s.At("uptown")
+	downtown.Register("12", "slackuser", testNow())
+	uptown.Register("12", "slackuser", testNow())
+	downtown.Enter("ponyboy", testNow())
+	uptown.Enter("sodapop", testNow())
+
+	draw, err := downtown.draw(testNow().Format(util.SpotDateFormat), 42)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"ponyboy"}, draw.Entrants, "should only draw among the entrants at the location")
+	_, err = uptown.DrawOf(testNow())
+	assert.Equal(t, data.ErrNotFound, err, "should draw each location on its own")
+	_, err = uptown.Claim("12", "darry")
+	assert.Equal(t, ErrDrawPending, err)
+	spot, _ := s.store.Get(data.LocationKey("uptown", formatKey("12", testNow())))
+	assert.Equal(t, "", spot.ClaimedBy, "should not give out spots at other locations")
+}
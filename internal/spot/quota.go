@@ -0,0 +1,154 @@
+package spot
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/jasonholmberg/slashspot/internal/data"
+	"github.com/jasonholmberg/slashspot/internal/util"
+)
+
+// QuotaRule - a rule that limits how many spots a user can claim
+type QuotaRule string
+
+const (
+	// QuotaPerWeek - the most spots a user can claim for the dates of one week, Monday to Sunday
+	QuotaPerWeek QuotaRule = "per-week"
+
+	// QuotaConcurrent - the most spots a user can have claimed for today and later dates at once
+	QuotaConcurrent QuotaRule = "concurrent"
+
+	// QuotaCooldown - how long a user waits after claiming a spot before claiming another
+	QuotaCooldown QuotaRule = "cooldown"
+)
+
+// Quota - the rules that keep a few users from claiming every spot, a rule left at 0 does not limit claims
+type Quota struct {
+	PerWeek    int
+	Concurrent int
+	Cooldown   time.Duration
+}
+
+// QuotaError - returned when a claim breaks a quota rule. Until is when the user can claim again.
+type QuotaError struct {
+	Rule  QuotaRule
+	Quota Quota
+	Until time.Time
+}
+
+func (e QuotaError) Error() string {
+	return fmt.Sprintf("%v quota reached until %v", e.Rule, e.Until.Format(time.RFC3339))
+}
+
+// WithQuota - a copy of the service sharing its store that limits the spots users can claim by the quota. The
+// waitlist passes over users the quota holds back, spots won in a draw are never held back by it, but both count
+// towards it.
+func (s *Service) WithQuota(quota Quota) *Service {
+	with := *s
+	with.quota = quota
+	return &with
+}
+
+// Quota - the rules that limit how many spots a user can claim
+func (s *Service) Quota() Quota {
+	return s.quota
+}
+
+// checkQuota - returns a QuotaError for the first rule the user would break by claiming a spot for the date
+func (s *Service) checkQuota(r data.Reader, user string, date string) error {
+	q := s.quota
+	if q.PerWeek <= 0 && q.Concurrent <= 0 && q.Cooldown <= 0 {
+		return nil
+	}
+	day, err := util.ParseTime(date, s.loc)
+	if err != nil {
+		return err
+	}
+	now := s.Now()
+	today := s.today()
+	monday := day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+	sunday := monday.AddDate(0, 0, 6).Format(util.SpotDateFormat)
+	// A claim is never for a date before the day it was made, so claims made since the cooldown began are for
+	// dates from then on
+	from := monday.Format(util.SpotDateFormat)
+	if since := now.Add(-q.Cooldown).Format(util.SpotDateFormat); since < from {
+		from = since
+	}
+	if today < from {
+		from = today
+	}
+	// No claim is for a date after the horizon, unless the week of the date goes past it
+	to := s.horizon()
+	if sunday > to {
+		to = sunday
+	}
+	claims, err := s.claimsOf(r, user, from, to)
+	if err != nil {
+		return err
+	}
+	week, current := 0, 0
+	var earliest string
+	var last time.Time
+	for _, c := range claims {
+		if c.date >= monday.Format(util.SpotDateFormat) && c.date <= sunday {
+			week++
+		}
+		if c.date >= today {
+			current++
+			if earliest == "" || c.date < earliest {
+				earliest = c.date
+			}
+		}
+		if at, err := time.Parse(time.RFC3339, c.at); err == nil && at.After(last) {
+			last = at
+		}
+	}
+	if q.PerWeek > 0 && week >= q.PerWeek {
+		return QuotaError{Rule: QuotaPerWeek, Quota: q, Until: monday.AddDate(0, 0, 7)}
+	}
+	if q.Concurrent > 0 && current >= q.Concurrent {
+		until, _ := util.ParseTime(earliest, s.loc)
+		return QuotaError{Rule: QuotaConcurrent, Quota: q, Until: until.AddDate(0, 0, 1)}
+	}
+	if q.Cooldown > 0 && !last.IsZero() && now.Before(last.Add(q.Cooldown)) {
+		return QuotaError{Rule: QuotaCooldown, Quota: q, Until: last.Add(q.Cooldown).In(s.loc)}
+	}
+	return nil
+}
+
+// claim - a spot day a user claimed all or part of, and when
+type claim struct {
+	date string
+	at   string
+}
+
+// claimsOf - the claims the user still has on spots open from one date to another, inclusive, at every location.
+// Past spots are cleaned up, so their claims are found in the history, while the store has the claims made before
+// the history was kept. Only the history of the dates is read.
+func (s *Service) claimsOf(r data.Reader, user string, from string, to string) (map[string]claim, error) {
+	claims := make(map[string]claim)
+	changes, err := data.ListHistory(r, from, to)
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range changes {
+		key := data.Spot{ID: c.ID, OpenDate: c.Date, Location: c.Location}.Key()
+		switch {
+		case c.Kind == data.HistoryClaimed && c.User == user:
+			claims[key] = claim{date: c.Date, at: c.At}
+		case c.Kind == data.HistoryReleased && c.User == user, c.Kind == data.HistoryDropped:
+			delete(claims, key)
+		}
+	}
+	spots, err := r.ListByDate(from, to)
+	if err != nil {
+		return nil, err
+	}
+	for k, spot := range spots {
+		delete(claims, k)
+		if claimedAt, ok := spot.ClaimOf(user); ok {
+			claims[k] = claim{date: spot.OpenDate, at: claimedAt}
+		}
+	}
+	return claims, nil
+}
@@ -0,0 +1,114 @@
+package spot
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jasonholmberg/slashspot/internal/data"
+	"github.com/jasonholmberg/slashspot/internal/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestService_checkQuota(t *testing.T) {
+	midnight := time.Date(2020, 1, 8, 0, 0, 0, 0, testLocation)
+	tests := []struct {
+		name    string
+		quota   Quota
+		claims  []int
+		days    int
+		wantErr error
+	}{
+		{
+			name:   "should claim with no quota",
+			quota:  Quota{},
+			claims: []int{0, 1, 2},
+			days:   3,
+		},
+		{
+			name:    "should not claim more spots in a week than the quota",
+			quota:   Quota{PerWeek: 2},
+			claims:  []int{0, 1},
+			days:    2,
+			wantErr: QuotaError{Rule: QuotaPerWeek, Quota: Quota{PerWeek: 2}, Until: midnight.AddDate(0, 0, 5)},
+		},
+		{
+			name:   "should claim in the next week",
+			quota:  Quota{PerWeek: 2},
+			claims: []int{0, 1},
+			days:   5,
+		},
+		{
+			name:    "should not have more spots claimed at once than the quota",
+			quota:   Quota{Concurrent: 2},
+			claims:  []int{0, 3},
+			days:    5,
+			wantErr: QuotaError{Rule: QuotaConcurrent, Quota: Quota{Concurrent: 2}, Until: midnight.AddDate(0, 0, 1)},
+		},
+		{
+			name:    "should wait out the cooldown",
+			quota:   Quota{Cooldown: time.Hour},
+			claims:  []int{1},
+			days:    0,
+			wantErr: QuotaError{Rule: QuotaCooldown, Quota: Quota{Cooldown: time.Hour}, Until: testNow().Add(time.Hour)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestService().WithQuota(tt.quota)
+			for days := 0; days <= 5; days++ {
+				s.Register("B1", "slackuser", testNow().AddDate(0, 0, days))
+			}
+			for _, days := range tt.claims {
+				_, err := s.WithQuota(Quota{}).ClaimOn("B1", "ponyboy", testNow().AddDate(0, 0, days))
+				assert.NoError(t, err)
+			}
+			_, err := s.ClaimOn("B1", "ponyboy", testNow().AddDate(0, 0, tt.days))
+			if tt.wantErr == nil {
+				assert.NoError(t, err)
+				return
+			}
+			assert.True(t, tt.wantErr.(QuotaError).Until.Equal(err.(QuotaError).Until), "should say when the user can claim again")
+			assert.Equal(t, tt.wantErr.(QuotaError).Rule, err.(QuotaError).Rule)
+		})
+	}
+
+	s := newTestService().WithQuota(Quota{PerWeek: 1})
+	s.Register("B1", "slackuser", testNow())
+	s.Register("B2", "slackuser", testNow())
+	s.ClaimOn("B1", "ponyboy", testNow())
+	_, err := s.ClaimOn("B2", "sodapop", testNow())
+	assert.NoError(t, err, "should count claims per user")
+}
+
+func TestService_checkQuotaAfterCleanup(t *testing.T) {
+	monday := time.Date(2020, 1, 6, 9, 30, 0, 0, testLocation)
+	s := newTestService().WithClock(util.FixedClock(monday)).WithQuota(Quota{PerWeek: 2, Cooldown: time.Hour})
+	for days := 0; days <= 3; days++ {
+		s.Register("B1", "slackuser", monday.AddDate(0, 0, days))
+	}
+	for days := 0; days <= 1; days++ {
+		at := s.WithClock(util.FixedClock(monday.AddDate(0, 0, days)))
+		_, err := at.Claim("B1", "ponyboy")
+		assert.NoError(t, err)
+	}
+
+	thursday := s.WithClock(util.FixedClock(monday.AddDate(0, 0, 3)))
+	thursday.Refresh()
+	_, err := thursday.store.Get(data.Spot{ID: "B1", OpenDate: "2020-01-06"}.Key())
+	assert.Equal(t, data.ErrNotFound, err, "should have cleaned up Monday's spot")
+	_, err = thursday.Claim("B1", "ponyboy")
+	quotaErr, _ := err.(QuotaError)
+	assert.Equal(t, QuotaPerWeek, quotaErr.Rule, "should count claims of spots cleaned up since")
+}
+
+func TestService_checkQuotaReadsWindow(t *testing.T) {
+	monday := time.Date(2020, 1, 6, 9, 30, 0, 0, testLocation)
+	s := newTestService().WithClock(util.FixedClock(monday)).WithQuota(Quota{PerWeek: 2})
+	// history outside the quota's dates is never read, so even a broken change there does not matter
+	for _, key := range []string{"2019-12-01/000000000001", "2020-02-01/000000000002"} {
+		assert.NoError(t, s.store.PutRecord("history", key, "not a change"))
+	}
+	s.Register("B1", "slackuser", monday)
+	_, err := s.Claim("B1", "ponyboy")
+	assert.NoError(t, err, "should only read the history of the quota's dates")
+}
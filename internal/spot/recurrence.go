@@ -0,0 +1,228 @@
+package spot
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/jasonholmberg/slashspot/internal/data"
+	"github.com/jasonholmberg/slashspot/internal/util"
+)
+
+// RegisterRecurring - register a spot on the same weekdays every week until a date, a zero until never ends.
+// Registering again replaces the user's rule for the spot. If someone else has a rule for the spot it is
+// returned along with an error. The spot must be one the user can register, see Register. The spot is registered
+// right away on the dates the rule opens it up to the claim horizon, and on later dates as they come within it.
+func (s *Service) RegisterRecurring(id string, user string, weekdays []time.Weekday, until time.Time) (data.Recurrence, error) {
+	rec := data.Recurrence{
+		ID:           id,
+		Weekdays:     weekdays,
+		RegDate:      s.today(),
+		RegisteredBy: user,
+		Location:     s.location,
+	}
+	if !until.IsZero() {
+		rec.Until = until.Format(util.SpotDateFormat)
+	}
+	var existing data.Recurrence
+	var events []Event
+	err := s.store.Update(func(tx data.Tx) error {
+		events = nil
+		if err := s.checkSpot(tx, id, user); err != nil {
+			return err
+		}
+		old, err := data.GetRecurrence(tx, rec.Key())
+		if err == nil && old.RegisteredBy != user {
+			existing = old
+			return fmt.Errorf("spot %v already recurs", id)
+		}
+		if err != nil && err != data.ErrNotFound {
+			return errors.New("error loading spot data")
+		}
+		if err := data.PutRecurrence(tx, rec); err != nil {
+			return err
+		}
+		events, err = s.expandRecurrence(tx, rec)
+		return err
+	})
+	if err != nil {
+		return existing, err
+	}
+	log.Printf("Registered Id: %v by %v every %v", rec.ID, rec.RegisteredBy, util.FormatWeekdays(rec.Weekdays))
+	s.notifyAll(events)
+	return rec, nil
+}
+
+// Recurrences - the recurrences registered by the user at every location ordered by location and spot
+func (s *Service) Recurrences(user string) ([]data.Recurrence, error) {
+	all, err := data.ListRecurrences(s.store)
+	if err != nil {
+		return nil, errors.New("error loading spot data")
+	}
+	var recs []data.Recurrence
+	for _, rec := range all {
+		if rec.RegisteredBy == user {
+			recs = append(recs, rec)
+		}
+	}
+	sort.Slice(recs, func(i, j int) bool {
+		if recs[i].Location != recs[j].Location {
+			return recs[i].Location < recs[j].Location
+		}
+		return recs[i].ID < recs[j].ID
+	})
+	return recs, nil
+}
+
+// CancelRecurrence - cancel the recurrence of a spot at the service's location. The open registrations it made for
+// later dates are dropped, today's and those claimed or held for someone are kept.
+func (s *Service) CancelRecurrence(id string, user string) error {
+	key := data.LocationKey(s.location, id)
+	err := s.store.Update(func(tx data.Tx) error {
+		rec, err := data.GetRecurrence(tx, key)
+		if err != nil {
+			return err
+		}
+		if rec.RegisteredBy != user {
+			return data.ErrNotFound
+		}
+		if err := data.DeleteRecurrence(tx, key); err != nil {
+			return err
+		}
+		spots, err := tx.ListByDate(nextDate(s.today()), "")
+		if err != nil {
+			return err
+		}
+		for k, spot := range spots {
+			if !s.here(spot) || spot.ID != id || spot.RegisteredBy != user || !rec.OpensOn(spot.OpenDate) {
+				continue
+			}
+			if spot.IsClaimed() || spot.IsShared() || spot.HeldFor != "" {
+				continue
+			}
+			if err := s.record(tx, data.Change{Kind: data.HistoryDropped}, spot); err != nil {
+				return err
+			}
+			if err := tx.Delete(k); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("cancel recurrence error of ID: %v", id)
+	}
+	return nil
+}
+
+// expandRecurrences - register the spots of every rule on the dates it opens from today to the claim horizon, so
+// they can be found, claimed ahead and waited for. Each date is expanded once, so a registration dropped by the holder
+// stays dropped. Rules that have ended are cleaned up. A spot registered is given to the first user waiting for it.
+// Returns the events of the spots registered.
+func (s *Service) expandRecurrences(tx data.Tx) ([]Event, error) {
+	today := s.today()
+	recs, err := data.ListRecurrences(tx)
+	if err != nil {
+		return nil, err
+	}
+	var events []Event
+	for _, rec := range recs {
+		if rec.Until != "" && rec.Until < today {
+			log.Printf(">Cleaning up ended recurrence Id: %v, registered by %v", rec.ID, rec.RegisteredBy)
+			if err := data.DeleteRecurrence(tx, rec.Key()); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		expanded, err := s.expandRecurrence(tx, rec)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, expanded...)
+	}
+	return events, nil
+}
+
+// expandRecurrence - register the spot of the rule on the dates it opens from today, or the day after it was last
+// expanded, to the claim horizon. The registrations are one event, like a range registration.
+func (s *Service) expandRecurrence(tx data.Tx, rec data.Recurrence) ([]Event, error) {
+	today, last := s.today(), s.horizon()
+	if rec.Expanded >= last {
+		return nil, nil
+	}
+	day, err := time.Parse(util.SpotDateFormat, today)
+	if err != nil {
+		return nil, err
+	}
+	if rec.Expanded >= today {
+		expanded, err := time.Parse(util.SpotDateFormat, rec.Expanded)
+		if err != nil {
+			return nil, err
+		}
+		day = expanded.AddDate(0, 0, 1)
+	}
+	var registered []data.Spot
+	var offered []Event
+	for date := day.Format(util.SpotDateFormat); date <= last; date = nextDate(date) {
+		if !rec.OpensOn(date) {
+			continue
+		}
+		newSpot, err := s.registerRecurrence(tx, rec, date)
+		if err != nil {
+			return nil, err
+		}
+		if newSpot.ID == "" {
+			continue
+		}
+		registered = append(registered, newSpot)
+		e, err := s.offer(tx, newSpot)
+		if err != nil {
+			return nil, err
+		}
+		if e != nil {
+			offered = append(offered, *e)
+		}
+	}
+	rec.Expanded = last
+	if err := data.PutRecurrence(tx, rec); err != nil {
+		return nil, err
+	}
+	if len(registered) == 0 {
+		return offered, nil
+	}
+	return append([]Event{{Kind: SpotsRegistered, Spots: offeredSpots(registered, offered...), Today: today}}, offered...), nil
+}
+
+// nextDate - the date after the date, both in the spot date format
+func nextDate(date string) string {
+	d, _ := time.Parse(util.SpotDateFormat, date)
+	return d.AddDate(0, 0, 1).Format(util.SpotDateFormat)
+}
+
+// registerRecurrence - register the spot of a rule for the date unless it is already registered, returns the new
+// registration or an empty spot when there is none. A rule for a spot its user can no longer register is skipped.
+// The spot is registered at the rule's location whatever the service's location is.
+func (s *Service) registerRecurrence(tx data.Tx, rec data.Recurrence, date string) (data.Spot, error) {
+	s = s.At(rec.Location)
+	err := s.checkSpot(tx, rec.ID, rec.RegisteredBy)
+	if err == ErrUnknownSpot || err == ErrNotHolder {
+		log.Printf(">Skipping recurrence Id: %v, registered by %v: %v", rec.ID, rec.RegisteredBy, err)
+		return data.Spot{}, nil
+	}
+	if err != nil {
+		return data.Spot{}, err
+	}
+	openDate, _ := time.Parse(util.SpotDateFormat, date)
+	newSpot := s.NewSpot(rec.ID, rec.RegisteredBy, openDate)
+	_, err = tx.Get(newSpot.Key())
+	if err != data.ErrNotFound {
+		return data.Spot{}, err
+	}
+	log.Printf("Registered Id: %v by %v for date: %v from recurrence", newSpot.ID, newSpot.RegisteredBy, newSpot.OpenDate)
+	if err := tx.Put(newSpot); err != nil {
+		return data.Spot{}, err
+	}
+	return newSpot, s.record(tx, data.Change{Kind: data.HistoryRegistered}, newSpot)
+}
@@ -0,0 +1,191 @@
+package spot
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jasonholmberg/slashspot/internal/data"
+	"github.com/jasonholmberg/slashspot/internal/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func today() time.Time {
+	t, _ := time.Parse(util.SpotDateFormat, util.Today(testNow()))
+	return t
+}
+
+func TestService_RegisterRecurring(t *testing.T) {
+	type args struct {
+		id       string
+		user     string
+		weekdays []time.Weekday
+		until    time.Time
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    data.Recurrence
+		wantErr bool
+	}{
+		{
+			name: "should register a recurrence",
+			args: args{
+				id:       "B11",
+				user:     "pparker",
+				weekdays: []time.Weekday{time.Friday},
+			},
+			want: data.Recurrence{
+				ID:           "B11",
+				Weekdays:     []time.Weekday{time.Friday},
+				RegDate:      testNow().Format(util.SpotDateFormat),
+				RegisteredBy: "pparker",
+			},
+		},
+		{
+			name: "should replace the user's recurrence",
+			args: args{
+				id:       "B1",
+				user:     "slackuser",
+				weekdays: []time.Weekday{time.Monday, time.Wednesday},
+				until:    today().AddDate(0, 1, 0),
+			},
+			want: data.Recurrence{
+				ID:           "B1",
+				Weekdays:     []time.Weekday{time.Monday, time.Wednesday},
+				Until:        today().AddDate(0, 1, 0).Format(util.SpotDateFormat),
+				RegDate:      testNow().Format(util.SpotDateFormat),
+				RegisteredBy: "slackuser",
+			},
+		},
+		{
+			name: "should not register someone else's recurring spot",
+			args: args{
+				id:       "B1",
+				user:     "pparker",
+				weekdays: []time.Weekday{time.Monday},
+			},
+			want: data.Recurrence{
+				ID:           "B1",
+				Weekdays:     []time.Weekday{time.Friday},
+				RegDate:      testNow().Format(util.SpotDateFormat),
+				RegisteredBy: "slackuser",
+				Expanded:     testNow().AddDate(0, 0, DefaultClaimHorizon).Format(util.SpotDateFormat),
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestService()
+			s.RegisterRecurring("B1", "slackuser", []time.Weekday{time.Friday}, time.Time{})
+			got, err := s.RegisterRecurring(tt.args.id, tt.args.user, tt.args.weekdays, tt.args.until)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Service.RegisterRecurring() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestService_FindExpandsRecurrences(t *testing.T) {
+	s := newTestService()
+	s.RegisterRecurring("R1", "slackuser", []time.Weekday{today().Weekday()}, time.Time{})
+	s.RegisterRecurring("R2", "slackuser", []time.Weekday{today().AddDate(0, 0, 1).Weekday()}, time.Time{})
+	got, err := s.Find()
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(got))
+	assert.Contains(t, got, formatKey("R1", today()))
+
+	assert.Nil(t, s.DropRegistration("R1", "slackuser"))
+	_, err = s.Find()
+	assert.NotNil(t, err, "a dropped registration should stay dropped")
+}
+
+func TestService_ClaimExpandsRecurrences(t *testing.T) {
+	s := newTestService()
+	s.RegisterRecurring("R1", "slackuser", []time.Weekday{today().Weekday()}, time.Time{})
+	got, err := s.Claim("R1", "Captain Fantastic")
+	assert.Nil(t, err)
+	assert.Equal(t, "slackuser", got.RegisteredBy)
+}
+
+func TestService_EndedRecurrence(t *testing.T) {
+	s := newTestService()
+	data.PutRecurrence(s.store, data.Recurrence{
+		ID:           "R1",
+		Weekdays:     []time.Weekday{today().Weekday()},
+		Until:        today().AddDate(0, 0, -1).Format(util.SpotDateFormat),
+		RegDate:      today().AddDate(0, 0, -14).Format(util.SpotDateFormat),
+		RegisteredBy: "slackuser",
+	})
+	assert.Nil(t, s.Refresh())
+	_, err := s.Find()
+	assert.NotNil(t, err)
+	recs, _ := s.Recurrences("slackuser")
+	assert.Equal(t, 0, len(recs), "should clean up an ended recurrence")
+}
+
+func TestService_RecurrenceUpToHorizon(t *testing.T) {
+	s := newTestService().WithFutureClaims(7, 0)
+	s.RegisterRecurring("R1", "slackuser", []time.Weekday{time.Monday, time.Friday}, time.Time{})
+	regs, err := s.Registrations("slackuser")
+	assert.Nil(t, err)
+	var dates []string
+	for _, reg := range regs {
+		dates = append(dates, reg.OpenDate)
+	}
+	assert.Equal(t, []string{"2020-01-10", "2020-01-13"}, dates, "should register the dates up to the claim horizon")
+	_, err = s.ClaimOn("R1", "ponyboy", today().AddDate(0, 0, 2))
+	assert.Nil(t, err, "should claim a recurring spot ahead")
+
+	assert.Nil(t, s.DropRegistration("R1", "slackuser"))
+	later := s.WithClock(util.FixedClock(testNow().AddDate(0, 0, 3)))
+	later.Refresh()
+	regs, _ = later.Registrations("slackuser")
+	dates = nil
+	for _, reg := range regs {
+		dates = append(dates, reg.OpenDate)
+	}
+	assert.Equal(t, []string{"2020-01-17"}, dates, "should register dates as they come within the horizon, and keep dropped ones dropped")
+
+	assert.Nil(t, later.CancelRecurrence("R1", "slackuser"))
+	regs, _ = later.Registrations("slackuser")
+	assert.Empty(t, regs, "should drop the open registrations the rule made ahead")
+}
+
+func TestService_CancelRecurrence(t *testing.T) {
+	tests := []struct {
+		name    string
+		id      string
+		user    string
+		wantErr bool
+	}{
+		{
+			name: "should cancel",
+			id:   "R1",
+			user: "slackuser",
+		},
+		{
+			name:    "should not cancel someone else's recurrence",
+			id:      "R1",
+			user:    "pparker",
+			wantErr: true,
+		},
+		{
+			name:    "should not cancel a missing recurrence",
+			id:      "R2",
+			user:    "slackuser",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestService()
+			s.RegisterRecurring("R1", "slackuser", []time.Weekday{time.Friday}, time.Time{})
+			if err := s.CancelRecurrence(tt.id, tt.user); (err != nil) != tt.wantErr {
+				t.Errorf("Service.CancelRecurrence() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
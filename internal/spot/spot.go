@@ -4,7 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"log"
-	"sync"
+	"sort"
 	"time"
 
 	"github.com/jasonholmberg/slashspot/internal/data"
@@ -16,11 +16,103 @@ const (
 	NotAvailable = "N/A"
 )
 
-var lock sync.Mutex
+// RangeResult - the outcome of registering a spot for many dates
+type RangeResult struct {
+	// Registered - the new registrations
+	Registered []data.Spot
 
-// NewSpot - A Spot constructor
-func NewSpot(ID string, registeredBy string, openDate time.Time) data.Spot {
-	now := time.Now()
+	// AlreadyRegistered - the existing registrations for dates that were taken
+	AlreadyRegistered []data.Spot
+}
+
+// Service - finds, claims and registers spots kept in a store. Days start and end in the service's location.
+type Service struct {
+	store    data.Store
+	loc      *time.Location
+	clock    util.Clock
+	admins   map[string]bool
+	notifier Notifier
+
+	waitlistMode WaitlistMode
+	holdFor      time.Duration
+
+	lottery  bool
+	cutoff   time.Duration
+	weighted bool
+	drawLoc  *time.Location
+
+	claimHorizon int
+	claimLimit   int
+	quota        Quota
+
+	historyDays int
+
+	location string
+}
+
+// NewService - A Service constructor, the service works in UTC by the system clock until given
+// another location with In or another clock with WithClock. Spots are held for users waiting for them for
+// DefaultHoldFor until told otherwise with WithWaitlist. Spots can be claimed DefaultClaimHorizon days ahead, with
+// DefaultClaimLimit claims for later dates per user, until told otherwise with WithFutureClaims.
+func NewService(store data.Store) *Service {
+	return &Service{
+		store:        store,
+		loc:          time.UTC,
+		clock:        util.SystemClock{},
+		waitlistMode: WaitlistHold,
+		holdFor:      DefaultHoldFor,
+		claimHorizon: DefaultClaimHorizon,
+		claimLimit:   DefaultClaimLimit,
+	}
+}
+
+// In - a copy of the service sharing its store that works in the location
+func (s *Service) In(loc *time.Location) *Service {
+	in := *s
+	in.loc = loc
+	return &in
+}
+
+// WithClock - a copy of the service sharing its store that tells the time by the clock
+func (s *Service) WithClock(clock util.Clock) *Service {
+	with := *s
+	with.clock = clock
+	return &with
+}
+
+// WithAdmins - a copy of the service sharing its store where the users are admins. Admins can register any spot.
+func (s *Service) WithAdmins(users ...string) *Service {
+	with := *s
+	with.admins = make(map[string]bool, len(users))
+	for _, user := range users {
+		with.admins[user] = true
+	}
+	return &with
+}
+
+// IsAdmin - returns true if the user is an admin
+func (s *Service) IsAdmin(user string) bool {
+	return s.admins[user]
+}
+
+// Location - the location the service works in
+func (s *Service) Location() *time.Location {
+	return s.loc
+}
+
+// Now - the current time by the service's clock in its location
+func (s *Service) Now() time.Time {
+	return s.clock.Now().In(s.loc)
+}
+
+// today - today's date in the service's location
+func (s *Service) today() string {
+	return util.Today(s.Now())
+}
+
+// NewSpot - A Spot constructor, the reg date is today in the service's location
+func (s *Service) NewSpot(ID string, registeredBy string, openDate time.Time) data.Spot {
+	now := s.Now()
 	if openDate.IsZero() {
 		openDate = now
 	}
@@ -29,33 +121,105 @@ func NewSpot(ID string, registeredBy string, openDate time.Time) data.Spot {
 		OpenDate:     openDate.Format(util.SpotDateFormat),
 		RegDate:      now.Format(util.SpotDateFormat),
 		RegisteredBy: registeredBy,
+		Location:     s.location,
 	}
 }
 
+// formatKey - the key of a spot at no location on the date
 func formatKey(id string, date time.Time) string {
 	return fmt.Sprintf("%v-%s", id, date.Format(util.SpotDateFormat))
 }
 
-// Find - fins all available spots
-func Find() (map[string]data.Spot, error) {
-	openSpots := make(map[string]data.Spot)
-	store, err := data.Load()
+// refresh - bring the store up to date before looking at today's spots. Holds that are over are ended and today's
+// spots are registered for recurrences. Returns the events to tell about once saved.
+func (s *Service) refresh(tx data.Tx) ([]Event, error) {
+	events, err := s.expireHolds(tx)
 	if err != nil {
-		return openSpots, errors.New("error loading spot data")
-	}
-	log.Println("Finding open spots for today")
-	for k, spot := range store {
-		if util.BeforeNow(spot.OpenDate) {
-			log.Printf(">Cleaning up old registration Id: %v, registered by %v for date: %v", spot.ID, spot.RegisteredBy, spot.OpenDate)
-			log.Println()
-			data.Drop(spot)
-			continue
+		return nil, err
+	}
+	expanded, err := s.expandRecurrences(tx)
+	if err != nil {
+		return nil, err
+	}
+	return append(events, expanded...), nil
+}
+
+// Refresh - bring the store up to date at every location. Holds that are over are ended, spots are registered for
+// recurrences up to the claim horizon and old registrations are cleaned up. Run it regularly, finding spots does not.
+func (s *Service) Refresh() error {
+	var events []Event
+	err := s.store.Update(func(tx data.Tx) error {
+		var err error
+		if events, err = s.refresh(tx); err != nil {
+			return err
 		}
-		if util.AfterNow(spot.OpenDate) {
-			log.Printf(">Skipping Id: %v, registered by %v for date: %v", spot.ID, spot.RegisteredBy, spot.OpenDate)
+		return s.cleanup(tx)
+	})
+	if err != nil {
+		return errors.New("error saving spot data")
+	}
+	s.notifyAll(events)
+	return nil
+}
+
+// cleanup - delete the registrations that are more than a day old, and the history older than it is kept for. A date
+// is only over everywhere once it is over in the earliest timezone, so yesterday's registrations are kept.
+func (s *Service) cleanup(tx data.Tx) error {
+	if err := s.pruneHistory(tx); err != nil {
+		return err
+	}
+	yesterday := s.Now().AddDate(0, 0, -1).Format(util.SpotDateFormat)
+	spots, err := tx.ListByDate("", yesterday)
+	if err != nil {
+		return err
+	}
+	for k, spot := range spots {
+		if spot.OpenDate >= yesterday {
 			continue
 		}
-		openSpots[k] = spot
+		log.Printf(">Cleaning up old registration Id: %v, registered by %v for date: %v", spot.ID, spot.RegisteredBy, spot.OpenDate)
+		if err := tx.Delete(k); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Find - fins all available spots at the service's location. When filters are given only spots in the catalog that match every filter are found.
+func (s *Service) Find(filters ...string) (map[string]data.Spot, error) {
+	return s.FindWindow(data.Window{}, filters...)
+}
+
+// FindWindow - find the spots available for the whole window today, the same as Find otherwise. The whole day
+// finds spots open for any part of it. Finding only reads the store, Refresh keeps it up to date.
+func (s *Service) FindWindow(window data.Window, filters ...string) (map[string]data.Spot, error) {
+	openSpots := make(map[string]data.Spot)
+	log.Println("Finding open spots for today", window, filters)
+	err := s.store.View(func(r data.Reader) error {
+		spots, err := r.ListByDate("", s.today())
+		if err != nil {
+			return err
+		}
+		known, err := data.ListCatalog(r)
+		if err != nil {
+			return err
+		}
+		for k, spot := range spots {
+			if !s.here(spot) || util.BeforeNow(spot.OpenDate, s.Now()) {
+				continue
+			}
+			if spot.IsHeld(s.Now()) || (window.IsWhole() && !spot.IsOpen()) || (!window.IsWhole() && !spot.OpenFor(window)) {
+				continue
+			}
+			if c, ok := known[data.LocationKey(spot.Location, spot.ID)]; len(filters) > 0 && (!ok || !matchesAll(c, filters)) {
+				continue
+			}
+			openSpots[k] = spot
+		}
+		return nil
+	})
+	if err != nil {
+		return make(map[string]data.Spot), errors.New("error loading spot data")
 	}
 	if len(openSpots) == 0 {
 		return openSpots, errors.New("no spots available")
@@ -63,64 +227,351 @@ func Find() (map[string]data.Spot, error) {
 	return openSpots, nil
 }
 
-// Claim - claim a spot
-func Claim(id string, user string) (data.Spot, error) {
-	store, err := data.Load()
-	if err != nil {
-		return data.Spot{}, errors.New("error loading spot data")
+// Claim - claim a spot for today. If the spot is already claimed it is returned along with an error.
+func (s *Service) Claim(id string, user string) (data.Spot, error) {
+	return s.ClaimOn(id, user, s.Now())
+}
+
+// ClaimOn - claim a spot for the date, today or a later date within the claim horizon. If the spot is already
+// claimed it is returned along with an error. ErrBeyondHorizon is returned for dates too far ahead,
+// ErrClaimLimit when the user already has as many claims for later dates as they may, and a QuotaError when the claim
+// breaks a quota rule.
+func (s *Service) ClaimOn(id string, user string, date time.Time) (data.Spot, error) {
+	return s.ClaimWindow(id, user, date, data.Window{})
+}
+
+// ClaimWindow - claim part of the day of a spot, the same as ClaimOn otherwise. The whole day claims all of the
+// spot's window. ErrOutsideWindow is returned when the spot is not available for all of the window, and
+// ErrWindowTaken along with the spot when someone has claimed some of it.
+func (s *Service) ClaimWindow(id string, user string, date time.Time, window data.Window) (data.Spot, error) {
+	var claimed data.Spot
+	now := s.Now()
+	claimKey := s.spotKey(id, date)
+	day := date.Format(util.SpotDateFormat)
+	if day < s.today() {
+		return data.Spot{
+			ID: NotAvailable,
+		}, fmt.Errorf("spot %v not available", id)
+	}
+	if day > s.horizon() {
+		return data.Spot{}, ErrBeyondHorizon
 	}
-	now := time.Now()
-	claimKey := formatKey(id, now)
-	for k, spot := range store {
-		if k == claimKey {
-			log.Printf("Spot %v claimed by %v", id, user)
-			data.Drop(spot)
-			return spot, nil
+	var events []Event
+	err := s.store.Update(func(tx data.Tx) error {
+		var err error
+		if events, err = s.refresh(tx); err != nil {
+			return err
 		}
+		spot, err := tx.Get(claimKey)
+		if err != nil {
+			return err
+		}
+		if spot.IsClaimed() {
+			claimed = spot
+			return fmt.Errorf("spot %v already claimed by %v", id, spot.ClaimedBy)
+		}
+		if day > s.today() {
+			if err := s.checkClaimLimit(tx, user); err != nil {
+				return err
+			}
+		}
+		if err := s.checkQuota(tx, user, day); err != nil {
+			return err
+		}
+		if pending, err := s.drawPending(tx, spot.Location, spot.OpenDate); err != nil || pending {
+			if err != nil {
+				return err
+			}
+			claimed = spot
+			return ErrDrawPending
+		}
+		if spot.IsHeld(now) && spot.HeldFor != user {
+			claimed = spot
+			return ErrHeld
+		}
+		if window.IsWhole() {
+			window = spot.Window
+		}
+		if !spot.Window.Contains(window) {
+			claimed = spot
+			return ErrOutsideWindow
+		}
+		if !spot.OpenFor(window) {
+			claimed = spot
+			return ErrWindowTaken
+		}
+		claim := data.Change{Kind: data.HistoryClaimed, User: user}
+		if window == spot.Window {
+			spot.ClaimedBy = user
+			spot.ClaimedAt = now.Format(time.RFC3339)
+		} else {
+			spot.Shares = append(spot.Shares, data.Share{User: user, Window: window, ClaimedAt: now.Format(time.RFC3339)})
+			claim.Window = window.String()
+		}
+		spot.HeldFor = ""
+		spot.HeldUntil = ""
+		claimed = spot
+		if err := data.DeleteWant(tx, data.Want{Date: spot.OpenDate, User: user, Location: spot.Location}.Key()); err != nil {
+			return err
+		}
+		if err := s.record(tx, claim, spot); err != nil {
+			return err
+		}
+		return tx.Put(spot)
+	})
+	if err == data.ErrNotFound {
+		return data.Spot{
+			ID: NotAvailable,
+		}, fmt.Errorf("spot %v not available", id)
 	}
-	return data.Spot{
-		ID: NotAvailable,
-	}, fmt.Errorf("spot %v not available", id)
+	if err != nil {
+		return claimed, err
+	}
+	log.Printf("Spot %v on %v claimed by %v", id, day, user)
+	s.notifyAll(events)
+	s.notify(SpotClaimed, claimed)
+	return claimed, nil
 }
 
-// Register - register a spot
-func Register(id string, user string, openDate time.Time) (data.Spot, error) {
-	store, err := data.Load()
+// Release - give back a spot claimed today so it is open again. Only the user who claimed the spot can release it.
+func (s *Service) Release(id string, user string) (data.Spot, error) {
+	return s.ReleaseOn(id, user, s.Now())
+}
+
+// ReleaseOn - give back a spot claimed for the date so it is open again. Only the user who claimed the spot can
+// release it. A user sharing the spot gives back the parts of the day they claimed.
+func (s *Service) ReleaseOn(id string, user string, date time.Time) (data.Spot, error) {
+	var released data.Spot
+	var offered *Event
+	releaseKey := s.spotKey(id, date)
+	if date.Format(util.SpotDateFormat) < s.today() {
+		return data.Spot{
+			ID: NotAvailable,
+		}, fmt.Errorf("spot %v not available", id)
+	}
+	err := s.store.Update(func(tx data.Tx) error {
+		spot, err := tx.Get(releaseKey)
+		if err != nil {
+			return err
+		}
+		if _, ok := spot.ClaimOf(user); !ok {
+			return fmt.Errorf("spot %v not claimed by %v", id, user)
+		}
+		if spot.ClaimedBy == user {
+			spot.ClaimedBy = ""
+			spot.ClaimedAt = ""
+		}
+		var shares []data.Share
+		for _, share := range spot.Shares {
+			if share.User != user {
+				shares = append(shares, share)
+			}
+		}
+		spot.Shares = shares
+		released = spot
+		if err := tx.Put(spot); err != nil {
+			return err
+		}
+		if err := s.record(tx, data.Change{Kind: data.HistoryReleased, User: user}, spot); err != nil {
+			return err
+		}
+		offered, err = s.offer(tx, spot)
+		return err
+	})
 	if err != nil {
-		return data.Spot{}, errors.New("error loading spot data")
+		return data.Spot{
+			ID: NotAvailable,
+		}, fmt.Errorf("spot %v can not be released: %v", id, err)
 	}
-	newSpot := NewSpot(id, user, openDate)
-	for k, spot := range store {
-		if k == newSpot.Key() {
-			return spot, fmt.Errorf("spot %v already registered", id)
+	log.Printf("Spot %v on %v released by %v", id, released.OpenDate, user)
+	s.notify(SpotReleased, released)
+	s.notifyOffered(offered)
+	return released, nil
+}
+
+// Register - register a spot. When there is a catalog the spot must be in it, or ErrUnknownSpot is returned.
+// A spot with a holder can only be registered by the holder or an admin, or ErrNotHolder is returned.
+func (s *Service) Register(id string, user string, openDate time.Time) (data.Spot, error) {
+	return s.RegisterWindow(id, user, openDate, data.Window{})
+}
+
+// RegisterWindow - register a spot for part of the day, the same as Register otherwise
+func (s *Service) RegisterWindow(id string, user string, openDate time.Time, window data.Window) (data.Spot, error) {
+	newSpot := s.NewSpot(id, user, openDate)
+	newSpot.Window = window
+	var existing data.Spot
+	var offered *Event
+	err := s.store.Update(func(tx data.Tx) error {
+		if err := s.checkSpot(tx, id, user); err != nil {
+			return err
+		}
+		spot, err := tx.Get(newSpot.Key())
+		if err == nil {
+			existing = spot
+			return fmt.Errorf("spot %v already registered", id)
 		}
+		if err != data.ErrNotFound {
+			return errors.New("error loading spot data")
+		}
+		if err := tx.Put(newSpot); err != nil {
+			return err
+		}
+		if err := s.record(tx, data.Change{Kind: data.HistoryRegistered}, newSpot); err != nil {
+			return err
+		}
+		offered, err = s.offer(tx, newSpot)
+		return err
+	})
+	if err != nil {
+		return existing, err
 	}
 	log.Printf("Registered Id: %v by %v for date: %v", newSpot.ID, newSpot.RegisteredBy, newSpot.OpenDate)
-	data.Add(newSpot)
+	registered := newSpot
+	if offered != nil {
+		registered = offered.Spots[0]
+	}
+	s.notify(SpotsRegistered, registered)
+	s.notifyOffered(offered)
 	return newSpot, nil
 }
 
-// DropRegistration - drop a registration
-func DropRegistration(id string, user string) error {
-	store, err := data.Load()
+// RegisterRange - register a spot for many dates at once. Dates that are already registered are
+// reported in the result rather than failing the batch.
+func (s *Service) RegisterRange(id string, user string, openDates []time.Time) (RangeResult, error) {
+	return s.RegisterRangeWindow(id, user, openDates, data.Window{})
+}
+
+// RegisterRangeWindow - register a spot for part of the day on many dates at once, the same as RegisterRange
+// otherwise
+func (s *Service) RegisterRangeWindow(id string, user string, openDates []time.Time, window data.Window) (RangeResult, error) {
+	var result RangeResult
+	var events []Event
+	err := s.store.Update(func(tx data.Tx) error {
+		result = RangeResult{}
+		events = nil
+		if err := s.checkSpot(tx, id, user); err != nil {
+			return err
+		}
+		for _, openDate := range openDates {
+			newSpot := s.NewSpot(id, user, openDate)
+			newSpot.Window = window
+			spot, err := tx.Get(newSpot.Key())
+			if err == nil {
+				result.AlreadyRegistered = append(result.AlreadyRegistered, spot)
+				continue
+			}
+			if err != data.ErrNotFound {
+				return err
+			}
+			if err := tx.Put(newSpot); err != nil {
+				return err
+			}
+			if err := s.record(tx, data.Change{Kind: data.HistoryRegistered}, newSpot); err != nil {
+				return err
+			}
+			result.Registered = append(result.Registered, newSpot)
+			offered, err := s.offer(tx, newSpot)
+			if err != nil {
+				return err
+			}
+			if offered != nil {
+				events = append(events, *offered)
+			}
+		}
+		return nil
+	})
+	if err == ErrUnknownSpot || err == ErrNotHolder {
+		return RangeResult{}, err
+	}
 	if err != nil {
-		return errors.New("error loading spot data")
+		return RangeResult{}, errors.New("error loading spot data")
+	}
+	for _, newSpot := range result.Registered {
+		log.Printf("Registered Id: %v by %v for date: %v", newSpot.ID, newSpot.RegisteredBy, newSpot.OpenDate)
 	}
-	for _, spot := range store {
-		if spot.ID == id && spot.RegisteredBy == user {
-			data.Drop(spot)
-			return nil
+	s.notify(SpotsRegistered, offeredSpots(result.Registered, events...)...)
+	s.notifyAll(events)
+	return result, nil
+}
+
+// DropRegistration - drop the user's next open registration of the spot at the service's location, today's when
+// there is one
+func (s *Service) DropRegistration(id string, user string) error {
+	err := s.store.Update(func(tx data.Tx) error {
+		spots, err := tx.ListByID(id)
+		if err != nil {
+			return err
+		}
+		var open []data.Spot
+		for _, spot := range spots {
+			if s.here(spot) && spot.RegisteredBy == user && !spot.IsClaimed() && !spot.IsShared() && !util.BeforeNow(spot.OpenDate, s.Now()) {
+				open = append(open, spot)
+			}
 		}
+		if len(open) == 0 {
+			return data.ErrNotFound
+		}
+		sortSpots(open)
+		if err := s.record(tx, data.Change{Kind: data.HistoryDropped}, open[0]); err != nil {
+			return err
+		}
+		return tx.Delete(open[0].Key())
+	})
+	if err != nil {
+		return fmt.Errorf("drop reg error of ID: %v", id)
 	}
-	return fmt.Errorf("drop reg error of ID: %v", id)
+	return nil
+}
+
+// DropAllRegistrations - drop all the registrations for current user at every location, claimed spots are kept
+func (s *Service) DropAllRegistrations(user string) {
+	s.store.Update(func(tx data.Tx) error {
+		spots, err := tx.ListByUser(user)
+		if err != nil {
+			return err
+		}
+		for k, spot := range spots {
+			if spot.IsClaimed() || spot.IsShared() {
+				continue
+			}
+			if err := s.record(tx, data.Change{Kind: data.HistoryDropped}, spot); err != nil {
+				return err
+			}
+			if err := tx.Delete(k); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
 }
 
-// DropAllRegistrations - drop all the registrations for current user
-func DropAllRegistrations(user string) {
-	store, _ := data.Load()
-	for _, spot := range store {
-		if spot.RegisteredBy == user {
-			data.Drop(spot)
+// Registrations - all current and future registrations by the user at every location, claimed or not, ordered by
+// date, location and spot
+func (s *Service) Registrations(user string) ([]data.Spot, error) {
+	spots, err := s.store.ListByUser(user)
+	if err != nil {
+		return nil, errors.New("error loading spot data")
+	}
+	var regs []data.Spot
+	for _, spot := range spots {
+		if util.BeforeNow(spot.OpenDate, s.Now()) {
+			continue
 		}
+		regs = append(regs, spot)
 	}
+	sortSpots(regs)
+	return regs, nil
+}
+
+// sortSpots - order the spots by date, location and spot
+func sortSpots(spots []data.Spot) {
+	sort.Slice(spots, func(i, j int) bool {
+		if spots[i].OpenDate != spots[j].OpenDate {
+			return spots[i].OpenDate < spots[j].OpenDate
+		}
+		if spots[i].Location != spots[j].Location {
+			return spots[i].Location < spots[j].Location
+		}
+		return spots[i].ID < spots[j].ID
+	})
 }
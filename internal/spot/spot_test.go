@@ -2,7 +2,6 @@ package spot
 
 import (
 	"fmt"
-	"os"
 	"reflect"
 	"testing"
 	"time"
@@ -17,26 +16,7 @@ func init() {
 	godotenv.Load("../../config/test.env")
 }
 
-func TestOpen(t *testing.T) {
-	tests := []struct {
-		name string
-	}{
-		{
-			name: "Should open the store",
-		},
-	}
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			data.Open()
-			_, err := os.Open(data.FilePath())
-			if err != nil {
-				t.Error("Fialed to open data store:", err)
-			}
-		})
-	}
-}
-
-func TestNewSpot(t *testing.T) {
+func TestService_NewSpot(t *testing.T) {
 	type args struct {
 		ID           string
 		registeredBy string
@@ -52,12 +32,12 @@ func TestNewSpot(t *testing.T) {
 			args: args{
 				ID:           "11",
 				registeredBy: "jjrambo",
-				openDate:     localTime(),
+				openDate:     testNow(),
 			},
 			want: data.Spot{
 				ID:           "11",
-				OpenDate:     localTime().Format(util.SpotDateFormat),
-				RegDate:      localTime().Format(util.SpotDateFormat),
+				OpenDate:     testNow().Format(util.SpotDateFormat),
+				RegDate:      testNow().Format(util.SpotDateFormat),
 				RegisteredBy: "jjrambo",
 			},
 		},
@@ -66,20 +46,20 @@ func TestNewSpot(t *testing.T) {
 			args: args{
 				ID:           "11",
 				registeredBy: "jjrambo",
-				openDate:     localTime().AddDate(0, 0, 1),
+				openDate:     testNow().AddDate(0, 0, 1),
 			},
 			want: data.Spot{
 				ID:           "11",
-				OpenDate:     localTime().AddDate(0, 0, 1).Format(util.SpotDateFormat),
-				RegDate:      localTime().Format(util.SpotDateFormat),
+				OpenDate:     testNow().AddDate(0, 0, 1).Format(util.SpotDateFormat),
+				RegDate:      testNow().Format(util.SpotDateFormat),
 				RegisteredBy: "jjrambo",
 			},
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if got := NewSpot(tt.args.ID, tt.args.registeredBy, tt.args.openDate); !reflect.DeepEqual(got, tt.want) {
-				t.Errorf("NewSpot() = %v, want %v", got, tt.want)
+			if got := newTestService().NewSpot(tt.args.ID, tt.args.registeredBy, tt.args.openDate); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Service.NewSpot() = %v, want %v", got, tt.want)
 			}
 		})
 	}
@@ -101,11 +81,11 @@ func TestSpot_key(t *testing.T) {
 			name: "Should return a key",
 			fields: fields{
 				ID:           "44",
-				OpenDate:     localTime(),
-				RegDate:      localTime(),
+				OpenDate:     testNow(),
+				RegDate:      testNow(),
 				RegisteredBy: "slackuser",
 			},
-			want: fmt.Sprintf("%s-%s", "44", localTime().Format(util.SpotDateFormat)),
+			want: fmt.Sprintf("%s-%s", "44", testNow().Format(util.SpotDateFormat)),
 		},
 	}
 	for _, tt := range tests {
@@ -137,9 +117,9 @@ func Test_formatKey(t *testing.T) {
 			name: "Should format a correct key",
 			args: args{
 				id:   "B11",
-				date: localTime(),
+				date: testNow(),
 			},
-			want: fmt.Sprintf("%s-%s", "B11", localTime().Format(util.SpotDateFormat)),
+			want: fmt.Sprintf("%s-%s", "B11", testNow().Format(util.SpotDateFormat)),
 		},
 	}
 	for _, tt := range tests {
@@ -151,59 +131,62 @@ func Test_formatKey(t *testing.T) {
 	}
 }
 
-func cleanup() {
-	os.Remove(data.FilePath())
+// testLocation - where the test service works, a fixed zone so the tests do not depend on the host
+var testLocation = time.FixedZone("CST", -6*60*60)
+
+func newTestService() *Service {
+	return NewService(data.NewMemoryStore()).In(testLocation).WithClock(util.FixedClock(testNow()))
 }
 
-func localTime() time.Time {
-	return time.Now().In(time.Local)
+// testNow - the time the test service's clock is stopped at, a Wednesday morning
+func testNow() time.Time {
+	return time.Date(2020, 1, 8, 9, 30, 0, 0, testLocation)
 }
 
 func testSpots() []data.Spot {
 	return []data.Spot{
 		{
 			ID:           "B0",
-			OpenDate:     localTime().AddDate(0, 0, -1).Format(util.SpotDateFormat),
-			RegDate:      localTime().Format(util.SpotDateFormat),
+			OpenDate:     testNow().AddDate(0, 0, -1).Format(util.SpotDateFormat),
+			RegDate:      testNow().Format(util.SpotDateFormat),
 			RegisteredBy: "Fred",
 		},
 		{
 			ID:           "B1",
-			OpenDate:     localTime().Format(util.SpotDateFormat),
-			RegDate:      localTime().Format(util.SpotDateFormat),
+			OpenDate:     testNow().Format(util.SpotDateFormat),
+			RegDate:      testNow().Format(util.SpotDateFormat),
 			RegisteredBy: "slackuser",
 		},
 		{
 			ID:           "B2",
-			OpenDate:     localTime().Format(util.SpotDateFormat),
-			RegDate:      localTime().Format(util.SpotDateFormat),
+			OpenDate:     testNow().Format(util.SpotDateFormat),
+			RegDate:      testNow().Format(util.SpotDateFormat),
 			RegisteredBy: "slackuser",
 		},
 		{
 			ID:           "B3",
-			OpenDate:     localTime().AddDate(0, 0, 1).Format(util.SpotDateFormat),
-			RegDate:      localTime().Format(util.SpotDateFormat),
+			OpenDate:     testNow().AddDate(0, 0, 1).Format(util.SpotDateFormat),
+			RegDate:      testNow().Format(util.SpotDateFormat),
 			RegisteredBy: "FredsMom",
 		},
 		{
 			ID:           "B4",
-			OpenDate:     localTime().Format(util.SpotDateFormat),
-			RegDate:      localTime().Format(util.SpotDateFormat),
+			OpenDate:     testNow().Format(util.SpotDateFormat),
+			RegDate:      testNow().Format(util.SpotDateFormat),
 			RegisteredBy: "BarneysMom",
 		},
 	}
 }
 
 // registers spots for test and ignores errors
-func registerSpotsForTest(spots []data.Spot) {
+func registerSpotsForTest(s *Service, spots []data.Spot) {
 	for _, spot := range spots {
 		od, _ := time.Parse(util.SpotDateFormat, spot.OpenDate)
-		Register(spot.ID, spot.RegisteredBy, od)
+		s.Register(spot.ID, spot.RegisteredBy, od)
 	}
 }
 
-func TestSpotBase_Find(t *testing.T) {
-	defer cleanup()
+func TestService_Find(t *testing.T) {
 	type fields struct {
 		spots []data.Spot
 	}
@@ -227,22 +210,22 @@ func TestSpotBase_Find(t *testing.T) {
 				spots: testSpots(),
 			},
 			want: map[string]data.Spot{
-				formatKey("B1", localTime()): data.Spot{
+				formatKey("B1", testNow()): data.Spot{
 					ID:           "B1",
-					OpenDate:     localTime().Format(util.SpotDateFormat),
-					RegDate:      localTime().Format(util.SpotDateFormat),
+					OpenDate:     testNow().Format(util.SpotDateFormat),
+					RegDate:      testNow().Format(util.SpotDateFormat),
 					RegisteredBy: "slackuser",
 				},
-				formatKey("B2", localTime()): data.Spot{
+				formatKey("B2", testNow()): data.Spot{
 					ID:           "B2",
-					OpenDate:     localTime().Format(util.SpotDateFormat),
-					RegDate:      localTime().Format(util.SpotDateFormat),
+					OpenDate:     testNow().Format(util.SpotDateFormat),
+					RegDate:      testNow().Format(util.SpotDateFormat),
 					RegisteredBy: "slackuser",
 				},
-				formatKey("B4", localTime()): data.Spot{
+				formatKey("B4", testNow()): data.Spot{
 					ID:           "B4",
-					OpenDate:     localTime().Format(util.SpotDateFormat),
-					RegDate:      localTime().Format(util.SpotDateFormat),
+					OpenDate:     testNow().Format(util.SpotDateFormat),
+					RegDate:      testNow().Format(util.SpotDateFormat),
 					RegisteredBy: "BarneysMom",
 				},
 			},
@@ -251,23 +234,21 @@ func TestSpotBase_Find(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			cleanup()
-			data.Open()
-			registerSpotsForTest(tt.fields.spots)
-			got, err := Find()
+			s := newTestService()
+			registerSpotsForTest(s, tt.fields.spots)
+			got, err := s.Find()
 			if (err != nil) != tt.wantErr {
-				t.Errorf("SpotBase.Find() error = %v, wantErr %v", err, tt.wantErr)
+				t.Errorf("Service.Find() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
 			if !reflect.DeepEqual(got, tt.want) {
-				t.Errorf("SpotBase.Find() = %v, want %v", got, tt.want)
+				t.Errorf("Service.Find() = %v, want %v", got, tt.want)
 			}
 		})
 	}
 }
 
-func TestSpotBase_Claim(t *testing.T) {
-	defer cleanup()
+func TestService_Claim(t *testing.T) {
 	type fields struct {
 		spots []data.Spot
 	}
@@ -293,12 +274,31 @@ func TestSpotBase_Claim(t *testing.T) {
 			},
 			want: data.Spot{
 				ID:           "B1",
-				OpenDate:     localTime().Format(util.SpotDateFormat),
-				RegDate:      localTime().Format(util.SpotDateFormat),
+				OpenDate:     testNow().Format(util.SpotDateFormat),
+				RegDate:      testNow().Format(util.SpotDateFormat),
 				RegisteredBy: "slackuser",
+				ClaimedBy:    "Captain Fantastic",
 			},
 			wantErr: false,
 		},
+		{
+			name: "Should not claim a spot that is already claimed",
+			fields: fields{
+				spots: testSpots(),
+			},
+			args: args{
+				id:   "B2",
+				user: "Captain Fantastic",
+			},
+			want: data.Spot{
+				ID:           "B2",
+				OpenDate:     testNow().Format(util.SpotDateFormat),
+				RegDate:      testNow().Format(util.SpotDateFormat),
+				RegisteredBy: "slackuser",
+				ClaimedBy:    "Captain Marvelous",
+			},
+			wantErr: true,
+		},
 		{
 			name: "Should not claim a spot, no spots registered",
 			fields: fields{
@@ -330,23 +330,26 @@ func TestSpotBase_Claim(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			data.Open()
-			cleanup()
-			registerSpotsForTest(tt.fields.spots)
-			got, err := Claim(tt.args.id, tt.args.user)
+			s := newTestService()
+			registerSpotsForTest(s, tt.fields.spots)
+			s.Claim("B2", "Captain Marvelous")
+			got, err := s.Claim(tt.args.id, tt.args.user)
 			if (err != nil) != tt.wantErr {
-				t.Errorf("SpotBase.Claim() error = %v, wantErr %v", err, tt.wantErr)
+				t.Errorf("Service.Claim() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
+			if got.IsClaimed() {
+				assert.NotEmpty(t, got.ClaimedAt)
+				got.ClaimedAt = ""
+			}
 			if !reflect.DeepEqual(got, tt.want) {
-				t.Errorf("SpotBase.Claim() = %v, want %v", got, tt.want)
+				t.Errorf("Service.Claim() = %v, want %v", got, tt.want)
 			}
 		})
 	}
 }
 
-func TestSpotBase_Register(t *testing.T) {
-	defer cleanup()
+func TestService_Register(t *testing.T) {
 	type fields struct {
 		spots []data.Spot
 	}
@@ -370,13 +373,13 @@ func TestSpotBase_Register(t *testing.T) {
 			args: args{
 				id:       "B11",
 				user:     "pparker",
-				openDate: localTime(),
+				openDate: testNow(),
 			},
 			want: data.Spot{
 				ID:           "B11",
 				RegisteredBy: "pparker",
-				OpenDate:     localTime().Format(util.SpotDateFormat),
-				RegDate:      localTime().Format(util.SpotDateFormat),
+				OpenDate:     testNow().Format(util.SpotDateFormat),
+				RegDate:      testNow().Format(util.SpotDateFormat),
 			},
 			wantErr: false,
 		},
@@ -388,13 +391,13 @@ func TestSpotBase_Register(t *testing.T) {
 			args: args{
 				id:       "B12",
 				user:     "pparker",
-				openDate: localTime().AddDate(0, 0, 1),
+				openDate: testNow().AddDate(0, 0, 1),
 			},
 			want: data.Spot{
 				ID:           "B12",
 				RegisteredBy: "pparker",
-				OpenDate:     localTime().AddDate(0, 0, 1).Format(util.SpotDateFormat),
-				RegDate:      localTime().Format(util.SpotDateFormat),
+				OpenDate:     testNow().AddDate(0, 0, 1).Format(util.SpotDateFormat),
+				RegDate:      testNow().Format(util.SpotDateFormat),
 			},
 			wantErr: false,
 		},
@@ -406,35 +409,34 @@ func TestSpotBase_Register(t *testing.T) {
 			args: args{
 				id:       "B1",
 				user:     "pparker",
-				openDate: localTime(),
+				openDate: testNow(),
 			},
 			want: data.Spot{
 				ID:           "B1",
 				RegisteredBy: "slackuser",
-				OpenDate:     localTime().Format(util.SpotDateFormat),
-				RegDate:      localTime().Format(util.SpotDateFormat),
+				OpenDate:     testNow().Format(util.SpotDateFormat),
+				RegDate:      testNow().Format(util.SpotDateFormat),
 			},
 			wantErr: true,
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			data.Open()
-			registerSpotsForTest(tt.fields.spots)
-			got, err := Register(tt.args.id, tt.args.user, tt.args.openDate)
+			s := newTestService()
+			registerSpotsForTest(s, tt.fields.spots)
+			got, err := s.Register(tt.args.id, tt.args.user, tt.args.openDate)
 			if (err != nil) != tt.wantErr {
-				t.Errorf("SpotBase.Register() error = %v, wantErr %v", err, tt.wantErr)
+				t.Errorf("Service.Register() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
 			if !reflect.DeepEqual(got, tt.want) {
-				t.Errorf("SpotBase.Register() = %v, want %v", got, tt.want)
+				t.Errorf("Service.Register() = %v, want %v", got, tt.want)
 			}
 		})
 	}
 }
 
-func TestSpotBase_DropRegistration(t *testing.T) {
-	defer cleanup()
+func TestService_DropRegistration(t *testing.T) {
 	type fields struct {
 		spots []data.Spot
 	}
@@ -473,17 +475,16 @@ func TestSpotBase_DropRegistration(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			data.Open()
-			registerSpotsForTest(tt.fields.spots)
-			if err := DropRegistration(tt.args.id, tt.args.user); (err != nil) != tt.wantErr {
-				t.Errorf("SpotBase.DropRegistration() error = %v, wantErr %v", err, tt.wantErr)
+			s := newTestService()
+			registerSpotsForTest(s, tt.fields.spots)
+			if err := s.DropRegistration(tt.args.id, tt.args.user); (err != nil) != tt.wantErr {
+				t.Errorf("Service.DropRegistration() error = %v, wantErr %v", err, tt.wantErr)
 			}
 		})
 	}
 }
 
 func TestDropAllRegistrations(t *testing.T) {
-	defer cleanup()
 	type args struct {
 		user string
 	}
@@ -508,11 +509,11 @@ func TestDropAllRegistrations(t *testing.T) {
 		},
 	}
 	for _, tt := range tests {
-		data.Open()
-		registerSpotsForTest(testSpots())
+		s := newTestService()
+		registerSpotsForTest(s, testSpots())
 		t.Run(tt.name, func(t *testing.T) {
-			DropAllRegistrations(tt.args.user)
-			openspots, _ := Find()
+			s.DropAllRegistrations(tt.args.user)
+			openspots, _ := s.Find()
 			assert.True(t, len(openspots) == tt.expectedCount)
 			for _, spot := range openspots {
 				if spot.RegisteredBy == tt.args.user {
@@ -522,3 +523,226 @@ func TestDropAllRegistrations(t *testing.T) {
 		})
 	}
 }
+
+func TestService_FindSkipsClaimed(t *testing.T) {
+	s := newTestService()
+	registerSpotsForTest(s, testSpots())
+	s.Claim("B1", "Captain Fantastic")
+	got, err := s.Find()
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(got))
+	assert.NotContains(t, got, formatKey("B1", testNow()))
+}
+
+func TestService_DropClaimedRegistration(t *testing.T) {
+	s := newTestService()
+	registerSpotsForTest(s, testSpots())
+	s.Claim("B1", "Captain Fantastic")
+	assert.NotNil(t, s.DropRegistration("B1", "slackuser"), "should not drop a claimed registration")
+	s.DropAllRegistrations("slackuser")
+	regs, _ := s.Registrations("slackuser")
+	assert.Equal(t, 1, len(regs))
+	assert.Equal(t, "Captain Fantastic", regs[0].ClaimedBy)
+}
+
+func TestService_Registrations(t *testing.T) {
+	tests := []struct {
+		name string
+		user string
+		want []string
+	}{
+		{
+			name: "should list current registrations in order",
+			user: "slackuser",
+			want: []string{"B1", "B2"},
+		},
+		{
+			name: "should skip past registrations",
+			user: "Fred",
+			want: nil,
+		},
+		{
+			name: "should list future registrations",
+			user: "FredsMom",
+			want: []string{"B3"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestService()
+			registerSpotsForTest(s, testSpots())
+			regs, err := s.Registrations(tt.user)
+			assert.Nil(t, err)
+			var got []string
+			for _, reg := range regs {
+				got = append(got, reg.ID)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestService_Release(t *testing.T) {
+	type args struct {
+		id   string
+		user string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    data.Spot
+		wantErr bool
+	}{
+		{
+			name: "should release a claimed spot",
+			args: args{
+				id:   "B1",
+				user: "Captain Fantastic",
+			},
+			want: data.Spot{
+				ID:           "B1",
+				OpenDate:     testNow().Format(util.SpotDateFormat),
+				RegDate:      testNow().Format(util.SpotDateFormat),
+				RegisteredBy: "slackuser",
+			},
+			wantErr: false,
+		},
+		{
+			name: "should not release a spot claimed by someone else",
+			args: args{
+				id:   "B1",
+				user: "slackuser",
+			},
+			want: data.Spot{
+				ID: NotAvailable,
+			},
+			wantErr: true,
+		},
+		{
+			name: "should not release an open spot",
+			args: args{
+				id:   "B2",
+				user: "Captain Fantastic",
+			},
+			want: data.Spot{
+				ID: NotAvailable,
+			},
+			wantErr: true,
+		},
+		{
+			name: "should not release an unregistered spot",
+			args: args{
+				id:   "B5",
+				user: "Captain Fantastic",
+			},
+			want: data.Spot{
+				ID: NotAvailable,
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestService()
+			registerSpotsForTest(s, testSpots())
+			s.Claim("B1", "Captain Fantastic")
+			got, err := s.Release(tt.args.id, tt.args.user)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Service.Release() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Service.Release() = %v, want %v", got, tt.want)
+			}
+			if !tt.wantErr {
+				open, _ := s.Find()
+				assert.Contains(t, open, got.Key(), "should be open again")
+			}
+		})
+	}
+}
+
+func TestService_RegisterRange(t *testing.T) {
+	tomorrow := testNow().AddDate(0, 0, 1)
+	tests := []struct {
+		name        string
+		openDates   []time.Time
+		wantNew     []string
+		wantExisted []string
+	}{
+		{
+			name:      "should register every date",
+			openDates: []time.Time{tomorrow.AddDate(0, 0, 1), tomorrow.AddDate(0, 0, 2)},
+			wantNew: []string{
+				tomorrow.AddDate(0, 0, 1).Format(util.SpotDateFormat),
+				tomorrow.AddDate(0, 0, 2).Format(util.SpotDateFormat),
+			},
+		},
+		{
+			name:        "should report dates that are already registered",
+			openDates:   []time.Time{tomorrow, tomorrow.AddDate(0, 0, 1)},
+			wantNew:     []string{tomorrow.AddDate(0, 0, 1).Format(util.SpotDateFormat)},
+			wantExisted: []string{tomorrow.Format(util.SpotDateFormat)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestService()
+			registerSpotsForTest(s, testSpots())
+			got, err := s.RegisterRange("B3", "FredsMom", tt.openDates)
+			assert.Nil(t, err)
+			var gotNew, gotExisted []string
+			for _, spot := range got.Registered {
+				gotNew = append(gotNew, spot.OpenDate)
+			}
+			for _, spot := range got.AlreadyRegistered {
+				gotExisted = append(gotExisted, spot.OpenDate)
+			}
+			assert.Equal(t, tt.wantNew, gotNew)
+			assert.Equal(t, tt.wantExisted, gotExisted)
+		})
+	}
+}
+
+func TestService_In(t *testing.T) {
+	// late on the 8th in Chicago is already the 9th in UTC
+	now := time.Date(2020, 1, 9, 5, 30, 0, 0, time.UTC)
+	s := NewService(data.NewMemoryStore()).WithClock(util.FixedClock(now))
+	assert.Equal(t, time.UTC, s.Location())
+	s.In(testLocation).Register("C1", "slackuser", time.Time{})
+	s.Register("U1", "slackuser", time.Time{})
+
+	got, _ := s.In(testLocation).Find()
+	assert.Contains(t, got, "C1-2020-01-08")
+	assert.Equal(t, 1, len(got), "should only find spots open today in Chicago")
+
+	got, _ = s.Find()
+	assert.Contains(t, got, "U1-2020-01-09")
+	assert.Equal(t, 1, len(got), "should only find spots open today in UTC")
+
+	spots, _ := s.store.List()
+	assert.Equal(t, 2, len(spots), "should not clean up a spot still open in Chicago")
+
+	_, err := s.Claim("C1", "Captain Fantastic")
+	assert.NotNil(t, err, "should not claim a spot that is not open today in UTC")
+}
+
+func TestService_WithClock(t *testing.T) {
+	s := newTestService()
+	registerSpotsForTest(s, testSpots())
+	midnight := time.Date(2020, 1, 9, 0, 0, 0, 0, testLocation)
+
+	got, _ := s.WithClock(util.FixedClock(midnight.Add(-time.Minute))).Find()
+	assert.Equal(t, 3, len(got), "should find today's spots until midnight")
+
+	got, _ = s.WithClock(util.FixedClock(midnight)).Find()
+	assert.Equal(t, 1, len(got), "should find tomorrow's spots from midnight")
+	assert.Contains(t, got, "B3-2020-01-09")
+
+	spots, _ := s.store.List()
+	assert.Contains(t, spots, "B0-2020-01-07", "should only read the store when finding spots")
+	assert.Nil(t, s.WithClock(util.FixedClock(midnight)).Refresh())
+	spots, _ = s.store.List()
+	assert.NotContains(t, spots, "B0-2020-01-07", "should clean up spots from before yesterday")
+	assert.Contains(t, spots, "B1-2020-01-08", "should keep yesterday's spots")
+}
@@ -0,0 +1,63 @@
+package spot
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/jasonholmberg/slashspot/internal/data"
+)
+
+// RememberUser - keep the user's name and workspace for display, the store is only written when they change. The
+// user's default location is kept unless a new one is given.
+func (s *Service) RememberUser(user data.User) error {
+	if user.ID == "" {
+		return nil
+	}
+	if known, err := data.GetUser(s.store, user.ID); err == nil && known == withLocation(user, known.Location) {
+		return nil
+	}
+	return s.store.Update(func(tx data.Tx) error {
+		known, err := data.GetUser(tx, user.ID)
+		if err == nil {
+			user = withLocation(user, known.Location)
+		}
+		if err == nil && known == user {
+			return nil
+		}
+		if err != nil && err != data.ErrNotFound {
+			return err
+		}
+		return data.PutUser(tx, user)
+	})
+}
+
+// withLocation - the user with the location when they have none
+func withLocation(user data.User, location string) data.User {
+	if user.Location == "" {
+		user.Location = location
+	}
+	return user
+}
+
+// UserName - the name of the user with the ID, the ID if the user has not been seen
+func (s *Service) UserName(id string) string {
+	user, err := data.GetUser(s.store, id)
+	if err != nil || user.Name == "" {
+		return id
+	}
+	return user.Name
+}
+
+// FindUser - the user with the name, names are compared without case
+func (s *Service) FindUser(name string) (data.User, error) {
+	all, err := data.ListUsers(s.store)
+	if err != nil {
+		return data.User{}, errors.New("error loading spot data")
+	}
+	for _, user := range all {
+		if strings.EqualFold(user.Name, name) {
+			return user, nil
+		}
+	}
+	return data.User{}, data.ErrNotFound
+}
@@ -0,0 +1,33 @@
+package spot
+
+import (
+	"testing"
+
+	"github.com/jasonholmberg/slashspot/internal/data"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestService_RememberUser(t *testing.T) {
+	s := newTestService()
+	assert.Equal(t, "U1", s.UserName("U1"), "should show the ID of an unknown user")
+
+	assert.Nil(t, s.RememberUser(data.User{ID: "U1", Name: "slackuser", TeamID: "T1"}))
+	assert.Equal(t, "slackuser", s.UserName("U1"))
+
+	assert.Nil(t, s.RememberUser(data.User{ID: "U1", Name: "renamed", TeamID: "T1"}))
+	assert.Equal(t, "renamed", s.UserName("U1"), "should show the latest name")
+
+	assert.Nil(t, s.RememberUser(data.User{Name: "nobody"}), "should ignore a user without an ID")
+	_, err := s.FindUser("nobody")
+	assert.Equal(t, data.ErrNotFound, err)
+}
+
+func TestService_FindUser(t *testing.T) {
+	s := newTestService()
+	s.RememberUser(data.User{ID: "U1", Name: "slackuser"})
+	got, err := s.FindUser("SlackUser")
+	assert.Nil(t, err)
+	assert.Equal(t, "U1", got.ID)
+	_, err = s.FindUser("pparker")
+	assert.Equal(t, data.ErrNotFound, err)
+}
@@ -0,0 +1,282 @@
+package spot
+
+import (
+	"errors"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/jasonholmberg/slashspot/internal/data"
+	"github.com/jasonholmberg/slashspot/internal/util"
+)
+
+// WaitlistMode - what happens when a spot opens on a date someone is waiting for
+type WaitlistMode string
+
+const (
+	// WaitlistHold - hold the spot for the first user waiting, only they can claim it until the hold ends
+	WaitlistHold WaitlistMode = "hold"
+
+	// WaitlistAssign - claim the spot for the first user waiting
+	WaitlistAssign WaitlistMode = "assign"
+
+	// DefaultHoldFor - how long a spot is held for the first user waiting when not told otherwise
+	DefaultHoldFor = 15 * time.Minute
+
+	// SpotHeld - a spot was held for the first user waiting
+	SpotHeld EventKind = "held"
+
+	// SpotAssigned - a spot was claimed for the first user waiting
+	SpotAssigned EventKind = "assigned"
+)
+
+var (
+	// ErrAlreadyWaiting - returned when a user is already on the waitlist of a date
+	ErrAlreadyWaiting = errors.New("already on the waitlist")
+
+	// ErrNotWaiting - returned when a user is not on the waitlist of a date
+	ErrNotWaiting = errors.New("not on the waitlist")
+
+	// ErrHeld - returned when a spot is held for someone else
+	ErrHeld = errors.New("spot is held for someone on the waitlist")
+)
+
+// WantResult - where a user stands after asking for a spot
+type WantResult struct {
+	// Spot - the spot held for or claimed for the user, empty while the user waits
+	Spot data.Spot
+
+	// Position - the user's place in the waitlist, 0 when the user got a spot
+	Position int
+}
+
+// WithWaitlist - a copy of the service sharing its store that gives spots to the users waiting for them the way of
+// the mode. Held spots are held for holdFor.
+func (s *Service) WithWaitlist(mode WaitlistMode, holdFor time.Duration) *Service {
+	with := *s
+	with.waitlistMode = mode
+	with.holdFor = holdFor
+	return &with
+}
+
+// Want - put the user on the waitlist for a spot at the service's location on the date. If a spot is already open and
+// no one is ahead of the user it is given to them right away.
+func (s *Service) Want(user string, date time.Time) (WantResult, error) {
+	day := date.Format(util.SpotDateFormat)
+	var result WantResult
+	var events []Event
+	err := s.store.Update(func(tx data.Tx) error {
+		result = WantResult{}
+		want := data.Want{Date: day, User: user, WantedAt: s.Now().Format(time.RFC3339), Location: s.location}
+		if _, err := data.GetWant(tx, want.Key()); err == nil {
+			return ErrAlreadyWaiting
+		}
+		waiting, err := data.Waitlist(tx, s.location, day)
+		if err != nil {
+			return err
+		}
+		want.Seq = 1
+		if len(waiting) > 0 {
+			want.Seq = waiting[len(waiting)-1].Seq + 1
+		}
+		if err := data.PutWant(tx, want); err != nil {
+			return err
+		}
+		result.Position = len(waiting) + 1
+		if events, err = s.offerOpenSpots(tx, day); err != nil {
+			return err
+		}
+		for _, e := range events {
+			if given := e.Spots[0]; given.HeldFor == user || (e.Kind == SpotAssigned && given.ClaimedBy == user) {
+				result = WantResult{Spot: given}
+			}
+		}
+		if result.Spot.ID == "" {
+			result.Position = len(waiting) + 1 - len(events)
+		}
+		return nil
+	})
+	if err == ErrAlreadyWaiting {
+		return WantResult{}, err
+	}
+	if err != nil {
+		return WantResult{}, errors.New("error saving spot data")
+	}
+	log.Printf("%v wants a spot for date: %v", user, day)
+	s.notifyAll(events)
+	return result, nil
+}
+
+// CancelWant - take the user off the waitlist of the date at the service's location
+func (s *Service) CancelWant(user string, date time.Time) error {
+	key := data.Want{Date: date.Format(util.SpotDateFormat), User: user, Location: s.location}.Key()
+	err := s.store.Update(func(tx data.Tx) error {
+		if _, err := data.GetWant(tx, key); err != nil {
+			return err
+		}
+		return data.DeleteWant(tx, key)
+	})
+	if err == data.ErrNotFound {
+		return ErrNotWaiting
+	}
+	if err != nil {
+		return errors.New("error saving spot data")
+	}
+	return nil
+}
+
+// ExpireHolds - end the holds that are over and give their spots to the next users waiting
+func (s *Service) ExpireHolds() error {
+	var events []Event
+	err := s.store.Update(func(tx data.Tx) error {
+		var err error
+		events, err = s.expireHolds(tx)
+		return err
+	})
+	if err != nil {
+		return errors.New("error saving spot data")
+	}
+	s.notifyAll(events)
+	return nil
+}
+
+// expireHolds - end the holds that are over at every location and give their spots to the next users waiting, spots
+// no one else is waiting for are released
+func (s *Service) expireHolds(tx data.Tx) ([]Event, error) {
+	spots, err := tx.ListByDate(s.today(), "")
+	if err != nil {
+		return nil, err
+	}
+	var events []Event
+	for _, spot := range spots {
+		if spot.HeldFor == "" || spot.IsHeld(s.Now()) || spot.IsClaimed() {
+			continue
+		}
+		log.Printf("Hold of spot %v for %v on %v ended", spot.ID, spot.HeldFor, spot.OpenDate)
+		spot.HeldFor = ""
+		spot.HeldUntil = ""
+		if err := tx.Put(spot); err != nil {
+			return nil, err
+		}
+		e, err := s.offer(tx, spot)
+		if err != nil {
+			return nil, err
+		}
+		if e == nil {
+			e = &Event{Kind: SpotReleased, Spots: []data.Spot{spot}, Today: s.today()}
+		}
+		events = append(events, *e)
+	}
+	return events, nil
+}
+
+// offerOpenSpots - give the open spots of the date at the service's location to the users waiting for it, in order
+func (s *Service) offerOpenSpots(tx data.Tx, date string) ([]Event, error) {
+	spots, err := tx.ListByDate(date, date)
+	if err != nil {
+		return nil, err
+	}
+	keys := make([]string, 0, len(spots))
+	for k := range spots {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var events []Event
+	for _, k := range keys {
+		spot := spots[k]
+		if !s.here(spot) || spot.IsClaimed() || spot.IsShared() || spot.IsHeld(s.Now()) {
+			continue
+		}
+		e, err := s.offer(tx, spot)
+		if err != nil {
+			return nil, err
+		}
+		if e == nil {
+			break
+		}
+		events = append(events, *e)
+	}
+	return events, nil
+}
+
+// offer - give an open spot to the first user waiting for its date at its location who may have it, if there is one.
+// The user is taken off the waitlist, users passed over keep their place. Returns the event to tell about or nil when
+// no one waiting may have it. Spots waiting for a draw or shared by users claiming parts of the day are not given out.
+func (s *Service) offer(tx data.Tx, spot data.Spot) (*Event, error) {
+	if spot.IsShared() {
+		return nil, nil
+	}
+	if pending, err := s.drawPending(tx, spot.Location, spot.OpenDate); err != nil || pending {
+		return nil, err
+	}
+	waiting, err := data.Waitlist(tx, spot.Location, spot.OpenDate)
+	if err != nil {
+		return nil, err
+	}
+	var first data.Want
+	for _, want := range waiting {
+		ok, err := s.mayBeGiven(tx, want.User, spot.OpenDate)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			first = want
+			break
+		}
+	}
+	if first.User == "" {
+		return nil, nil
+	}
+	if err := data.DeleteWant(tx, first.Key()); err != nil {
+		return nil, err
+	}
+	now := s.Now()
+	kind := SpotHeld
+	if s.waitlistMode == WaitlistAssign {
+		kind = SpotAssigned
+		spot.ClaimedBy = first.User
+		spot.ClaimedAt = now.Format(time.RFC3339)
+		log.Printf("Spot %v on %v claimed for %v from the waitlist", spot.ID, spot.OpenDate, first.User)
+	} else {
+		spot.HeldFor = first.User
+		spot.HeldUntil = now.Add(s.holdFor).Format(time.RFC3339)
+		log.Printf("Spot %v on %v held for %v until %v", spot.ID, spot.OpenDate, first.User, spot.HeldUntil)
+	}
+	if err := tx.Put(spot); err != nil {
+		return nil, err
+	}
+	if kind == SpotAssigned {
+		if err := s.record(tx, data.Change{Kind: data.HistoryClaimed, User: first.User}, spot); err != nil {
+			return nil, err
+		}
+	}
+	return &Event{Kind: kind, Spots: []data.Spot{spot}, Today: s.today()}, nil
+}
+
+// mayBeGiven - returns true if a spot for the date can be given to the user waiting for it. A user who already has
+// all or part of a spot that day is passed over, and so is one it would take past their claim limit or quota.
+func (s *Service) mayBeGiven(r data.Reader, user string, date string) (bool, error) {
+	spots, err := r.ListByDate(date, date)
+	if err != nil {
+		return false, err
+	}
+	for _, spot := range spots {
+		if _, ok := spot.ClaimOf(user); ok {
+			return false, nil
+		}
+	}
+	if date > s.today() {
+		if err := s.checkClaimLimit(r, user); err == ErrClaimLimit {
+			return false, nil
+		} else if err != nil {
+			return false, err
+		}
+	}
+	if err := s.checkQuota(r, user, date); err != nil {
+		if _, ok := err.(QuotaError); ok {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
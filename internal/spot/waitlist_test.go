@@ -0,0 +1,179 @@
+package spot
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jasonholmberg/slashspot/internal/data"
+	"github.com/jasonholmberg/slashspot/internal/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestService_Want(t *testing.T) {
+	s := newTestService()
+	got, err := s.Want("ponyboy", testNow())
+	assert.Nil(t, err)
+	assert.Equal(t, WantResult{Position: 1}, got)
+	got, err = s.Want("sodapop", testNow())
+	assert.Nil(t, err)
+	assert.Equal(t, WantResult{Position: 2}, got, "should wait behind the first user")
+	_, err = s.Want("ponyboy", testNow())
+	assert.Equal(t, ErrAlreadyWaiting, err)
+	got, _ = s.Want("ponyboy", testNow().AddDate(0, 0, 1))
+	assert.Equal(t, WantResult{Position: 1}, got, "should keep a waitlist per date")
+
+	assert.Nil(t, s.CancelWant("ponyboy", testNow()))
+	assert.Equal(t, ErrNotWaiting, s.CancelWant("ponyboy", testNow()))
+	ws, _ := data.Waitlist(s.store, "", testNow().Format(util.SpotDateFormat))
+	assert.Len(t, ws, 1)
+	assert.Equal(t, "sodapop", ws[0].User)
+}
+
+func TestService_WantOpenSpot(t *testing.T) {
+	s := newTestService()
+	s.Register("B1", "slackuser", testNow())
+	got, err := s.Want("ponyboy", testNow())
+	assert.Nil(t, err)
+	assert.Equal(t, "ponyboy", got.Spot.HeldFor, "should hold an open spot for a user no one is ahead of")
+	assert.Equal(t, testNow().Add(DefaultHoldFor).Format(time.RFC3339), got.Spot.HeldUntil)
+
+	got, _ = s.Want("sodapop", testNow())
+	assert.Equal(t, WantResult{Position: 1}, got, "should not hold a held spot for someone else")
+}
+
+func TestService_RegisterHoldsForWaitlist(t *testing.T) {
+	n := &testNotifier{}
+	s := newTestService().WithNotifier(n)
+	s.Want("ponyboy", testNow())
+	s.Want("sodapop", testNow())
+	s.Register("B1", "slackuser", testNow())
+
+	found, _ := s.Find()
+	assert.Empty(t, found, "should not find a held spot")
+	_, err := s.Claim("B1", "sodapop")
+	assert.Equal(t, ErrHeld, err, "should only let the user it is held for claim it")
+	claimed, err := s.Claim("B1", "ponyboy")
+	assert.Nil(t, err)
+	assert.Equal(t, "", claimed.HeldFor, "should end the hold once claimed")
+	assert.Equal(t, [][]string{
+		{"registered", formatKey("B1", testNow())},
+		{"held", formatKey("B1", testNow())},
+		{"claimed", formatKey("B1", testNow())},
+	}, n.kinds())
+	assert.Equal(t, "ponyboy", n.events[0].Spots[0].HeldFor, "should tell about the registration as held")
+	assert.Equal(t, "ponyboy", n.events[1].Spots[0].HeldFor)
+}
+
+func TestService_HoldForLaterDate(t *testing.T) {
+	s := newTestService().WithWaitlist(WaitlistHold, time.Hour)
+	tomorrow := testNow().AddDate(0, 0, 1)
+	s.Want("ponyboy", tomorrow)
+	s.Register("B1", "slackuser", tomorrow)
+	spot, _ := s.store.Get(formatKey("B1", tomorrow))
+	assert.Equal(t, testNow().Add(time.Hour).Format(time.RFC3339), spot.HeldUntil, "should hold the spot from when it is offered")
+	_, err := s.ClaimOn("B1", "ponyboy", tomorrow)
+	assert.Nil(t, err, "should claim the spot while it is held")
+}
+
+func TestService_ExpireHolds(t *testing.T) {
+	clock := &testClock{now: testNow()}
+	n := &testNotifier{}
+	s := newTestService().WithClock(clock).WithNotifier(n)
+	s.Want("ponyboy", testNow())
+	s.Want("sodapop", testNow())
+	s.Register("B1", "slackuser", testNow())
+
+	clock.now = testNow().Add(DefaultHoldFor + time.Minute)
+	assert.Nil(t, s.ExpireHolds())
+	spot, _ := s.store.Get(formatKey("B1", testNow()))
+	assert.Equal(t, "sodapop", spot.HeldFor, "should pass the spot to the next user waiting")
+
+	clock.now = clock.now.Add(DefaultHoldFor + time.Minute)
+	assert.Nil(t, s.ExpireHolds())
+	found, _ := s.Find()
+	assert.Len(t, found, 1, "should open the spot when no one is left waiting")
+	last := n.events[len(n.events)-1]
+	assert.Equal(t, "released", string(last.Kind), "should tell the spot is open again")
+	assert.Equal(t, "", last.Spots[0].HeldFor)
+}
+
+func TestService_WaitlistAssign(t *testing.T) {
+	n := &testNotifier{}
+	s := newTestService().WithWaitlist(WaitlistAssign, DefaultHoldFor).WithNotifier(n)
+	s.Want("ponyboy", testNow())
+	s.Register("B1", "slackuser", testNow())
+	spot, _ := s.store.Get(formatKey("B1", testNow()))
+	assert.Equal(t, "ponyboy", spot.ClaimedBy, "should claim the spot for the first user waiting")
+	assert.Equal(t, "assigned", string(n.events[1].Kind))
+	assert.Equal(t, "ponyboy", n.events[0].Spots[0].ClaimedBy, "should tell about the registration as claimed")
+
+	s.Want("sodapop", testNow())
+	s.Release("B1", "ponyboy")
+	spot, _ = s.store.Get(formatKey("B1", testNow()))
+	assert.Equal(t, "sodapop", spot.ClaimedBy, "should pass a released spot to the next user waiting")
+}
+
+// testClock - a clock the test can move
+type testClock struct {
+	now time.Time
+}
+
+func (c *testClock) Now() time.Time {
+	return c.now
+}
+
+func TestService_WaitlistAssignPassesOver(t *testing.T) {
+	tomorrow := testNow().AddDate(0, 0, 1)
+	tests := []struct {
+		name  string
+		setup func(s *Service) *Service
+		want  string
+	}{
+		{
+			name:  "should assign the first user waiting",
+			setup: func(s *Service) *Service { return s },
+			want:  "ponyboy",
+		},
+		{
+			name: "should pass over a user who already has a spot that day",
+			setup: func(s *Service) *Service {
+				s.Register("B1", "slackuser", tomorrow)
+				s.ClaimOn("B1", "ponyboy", tomorrow)
+				return s
+			},
+			want: "sodapop",
+		},
+		{
+			name: "should pass over a user at their claim limit",
+			setup: func(s *Service) *Service {
+				s.Register("B1", "slackuser", tomorrow.AddDate(0, 0, 1))
+				s.ClaimOn("B1", "ponyboy", tomorrow.AddDate(0, 0, 1))
+				return s.WithFutureClaims(DefaultClaimHorizon, 1)
+			},
+			want: "sodapop",
+		},
+		{
+			name: "should pass over a user at their quota",
+			setup: func(s *Service) *Service {
+				s.Register("B1", "slackuser", testNow())
+				s.Claim("B1", "ponyboy")
+				return s.WithQuota(Quota{PerWeek: 1})
+			},
+			want: "sodapop",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := tt.setup(newTestService().WithWaitlist(WaitlistAssign, DefaultHoldFor))
+			s.Want("ponyboy", tomorrow)
+			s.Want("sodapop", tomorrow)
+			s.Register("B2", "slackuser", tomorrow)
+			spot, _ := s.store.Get(formatKey("B2", tomorrow))
+			assert.Equal(t, tt.want, spot.ClaimedBy)
+			waiting, _ := data.Waitlist(s.store, "", tomorrow.Format(util.SpotDateFormat))
+			if tt.want == "sodapop" {
+				assert.Equal(t, 1, len(waiting), "should keep the place of the user passed over")
+			}
+		})
+	}
+}
@@ -0,0 +1,11 @@
+package spot
+
+import "errors"
+
+var (
+	// ErrOutsideWindow - returned when part of the day claimed is outside the part the spot is available
+	ErrOutsideWindow = errors.New("spot is not available for all of the window")
+
+	// ErrWindowTaken - returned when someone has claimed some of the part of the day claimed
+	ErrWindowTaken = errors.New("spot is claimed for some of the window")
+)
@@ -0,0 +1,69 @@
+package spot
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jasonholmberg/slashspot/internal/data"
+	"github.com/jasonholmberg/slashspot/internal/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestService_ClaimWindow(t *testing.T) {
+	pm := data.Window{From: "12:00"}
+	s := newTestService()
+	s.RegisterWindow("B1", "slackuser", testNow(), pm)
+	tests := []struct {
+		name    string
+		user    string
+		window  data.Window
+		wantErr error
+	}{
+		{name: "should not claim outside the spot's window", user: "ponyboy", window: data.Window{To: "12:00"}, wantErr: ErrOutsideWindow},
+		{name: "should claim part of the spot's window", user: "ponyboy", window: data.Window{From: "12:00", To: "14:00"}},
+		{name: "should not claim a window someone else claimed some of", user: "sodapop", window: data.Window{From: "13:00", To: "15:00"}, wantErr: ErrWindowTaken},
+		{name: "should not claim the whole spot while it is shared", user: "sodapop", window: data.Window{}, wantErr: ErrWindowTaken},
+		{name: "should share the spot", user: "sodapop", window: data.Window{From: "14:00"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := s.ClaimWindow("B1", tt.user, testNow(), tt.window)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+
+	found, _ := s.Find()
+	assert.Empty(t, found, "should not find a spot whose window is all claimed")
+
+	_, err := s.Release("B1", "ponyboy")
+	assert.NoError(t, err)
+	released, _ := s.store.Get("B1-" + testNow().Format(util.SpotDateFormat))
+	assert.Equal(t, []data.Share{{User: "sodapop", Window: data.Window{From: "14:00"}, ClaimedAt: testNow().Format(time.RFC3339)}}, released.Shares, "should only give back the user's share")
+
+	found, _ = s.FindWindow(data.Window{From: "12:00", To: "13:00"})
+	assert.Len(t, found, 1, "should find a spot open for the window")
+	found, _ = s.FindWindow(data.Window{From: "13:00", To: "15:00"})
+	assert.Empty(t, found, "should not find a spot claimed for some of the window")
+
+	claimed, err := s.ClaimWindow("B1", "ponyboy", testNow(), pm)
+	assert.Equal(t, ErrWindowTaken, err)
+	assert.Equal(t, "B1", claimed.ID)
+}
+
+func TestService_ClaimWindowWhole(t *testing.T) {
+	s := newTestService()
+	s.RegisterWindow("B1", "slackuser", testNow(), data.Window{To: "12:00"})
+	claimed, err := s.ClaimWindow("B1", "ponyboy", testNow(), data.Window{To: "12:00"})
+	assert.NoError(t, err)
+	assert.Equal(t, "ponyboy", claimed.ClaimedBy, "should claim the spot when the window is all of the spot's")
+	assert.False(t, claimed.IsShared())
+}
+
+func TestService_offerShared(t *testing.T) {
+	s := newTestService()
+	s.Register("B1", "slackuser", testNow())
+	s.ClaimWindow("B1", "ponyboy", testNow(), data.Window{To: "12:00"})
+	result, err := s.Want("sodapop", testNow())
+	assert.NoError(t, err)
+	assert.Equal(t, 1, result.Position, "should not give a shared spot to the waitlist")
+}
@@ -0,0 +1,54 @@
+package util
+
+import (
+	"fmt"
+	"time"
+)
+
+// Clock - tells the time. Anything that decides what day it is asks a Clock so tests can pin the date.
+type Clock interface {
+	Now() time.Time
+}
+
+// SystemClock - the wall clock
+type SystemClock struct{}
+
+// Now - the current time
+func (SystemClock) Now() time.Time {
+	return time.Now()
+}
+
+// FixedClock - a clock stopped at a time
+type FixedClock time.Time
+
+// Now - the time the clock is stopped at
+func (c FixedClock) Now() time.Time {
+	return time.Time(c)
+}
+
+// OffsetClock - a clock that runs at the normal rate from a time other than now
+type OffsetClock struct {
+	offset time.Duration
+}
+
+// NewOffsetClock - An OffsetClock constructor, the clock reads start as of now and keeps running
+func NewOffsetClock(start time.Time) OffsetClock {
+	return OffsetClock{offset: time.Until(start)}
+}
+
+// Now - the wall clock time moved by the offset
+func (c OffsetClock) Now() time.Time {
+	return time.Now().Add(c.offset)
+}
+
+// ParseTime - parse a time given as RFC3339 like 2020-01-06T08:30:00-06:00, or a date in SpotDateFormat
+// which is taken as the start of that day in the location
+func ParseTime(in string, loc *time.Location) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, in); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation(SpotDateFormat, in, loc); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("%q is not an RFC3339 time or a %s date", in, SpotDateFormat)
+}
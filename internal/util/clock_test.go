@@ -0,0 +1,57 @@
+package util
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFixedClock(t *testing.T) {
+	c := FixedClock(testNow)
+	if got := c.Now(); !got.Equal(testNow) {
+		t.Errorf("Now() = %v, want %v", got, testNow)
+	}
+}
+
+func TestOffsetClock(t *testing.T) {
+	c := NewOffsetClock(testNow)
+	if got := c.Now(); got.Before(testNow) || got.Sub(testNow) > time.Minute {
+		t.Errorf("Now() = %v, want about %v", got, testNow)
+	}
+}
+
+func TestParseTime(t *testing.T) {
+	cst := time.FixedZone("CST", -6*60*60)
+	tests := []struct {
+		name    string
+		in      string
+		want    time.Time
+		wantErr bool
+	}{
+		{
+			name: "should parse an RFC3339 time",
+			in:   "2020-01-08T09:30:00Z",
+			want: testNow,
+		},
+		{
+			name: "should parse a date as the start of the day in the location",
+			in:   "2020-01-08",
+			want: time.Date(2020, 1, 8, 0, 0, 0, 0, cst),
+		},
+		{
+			name:    "should not parse anything else",
+			in:      "tomorrow",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseTime(tt.in, cst)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseTime() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("ParseTime() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,34 +1,157 @@
 package util
 
 import (
+	"fmt"
+	"sort"
+	"strings"
 	"time"
 )
 
 const (
 	// SpotDateFormat - the default spot date formate
 	SpotDateFormat = "2006-01-02"
-	oneDay         = 24 * time.Hour
+
+	// DateRangeSep - separates the first and last dates of a range
+	DateRangeSep = ".."
+
+	// DateListSep - separates dates and ranges in a list
+	DateListSep = ","
+
+	// MaxDates - the most dates a single date spec can expand to
+	MaxDates = 366
 )
 
-// Need to make sure the now time is really UTC, some sytems do not
-// default to UTC.  Trying to compare to local is problematic
+// weekdays - the names a day of the week can be given by
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday, "sunday": time.Sunday,
+	"mon": time.Monday, "monday": time.Monday,
+	"tue": time.Tuesday, "tues": time.Tuesday, "tuesday": time.Tuesday,
+	"wed": time.Wednesday, "wednesday": time.Wednesday,
+	"thu": time.Thursday, "thur": time.Thursday, "thurs": time.Thursday, "thursday": time.Thursday,
+	"fri": time.Friday, "friday": time.Friday,
+	"sat": time.Saturday, "saturday": time.Saturday,
+}
+
+// Spot dates are calendar dates with no time zone. Whether a date is today depends on
+// where you are, so each of these takes the time now in the location the date is seen from.
 
-// BeforeNow - is the given date before now
-func BeforeNow(in string) bool {
-	now := time.Now().In(time.UTC).Truncate(oneDay)
-	test, _ := time.ParseInLocation(SpotDateFormat, in, time.UTC)
-	if now.Equal(test) {
+// Today - today's date in SpotDateFormat as of now
+func Today(now time.Time) string {
+	return now.Format(SpotDateFormat)
+}
+
+// BeforeNow - is the given date before today as of now
+func BeforeNow(in string, now time.Time) bool {
+	today, _ := time.Parse(SpotDateFormat, Today(now))
+	test, _ := time.Parse(SpotDateFormat, in)
+	if today.Equal(test) {
 		return false
 	}
-	return test.Before(now)
+	return test.Before(today)
 }
 
-// AfterNow - is the given date after now
-func AfterNow(in string) bool {
-	now := time.Now().In(time.UTC).Truncate(oneDay)
-	test, _ := time.ParseInLocation(SpotDateFormat, in, time.UTC)
-	if now.Equal(test) {
+// AfterNow - is the given date after today as of now
+func AfterNow(in string, now time.Time) bool {
+	today, _ := time.Parse(SpotDateFormat, Today(now))
+	test, _ := time.Parse(SpotDateFormat, in)
+	if today.Equal(test) {
 		return false
 	}
-	return test.After(now)
+	return test.After(today)
+}
+
+// LoadLocations - parse a comma separated list of key=zone pairs like T0123=America/Chicago into locations by key
+func LoadLocations(in string) (map[string]*time.Location, error) {
+	locs := make(map[string]*time.Location)
+	for _, pair := range strings.Split(in, DateListSep) {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+		kv := strings.SplitN(pair, "=", 2)
+		if len(kv) != 2 {
+			return nil, fmt.Errorf("%q is not key=zone", pair)
+		}
+		loc, err := time.LoadLocation(strings.TrimSpace(kv[1]))
+		if err != nil {
+			return nil, err
+		}
+		locs[strings.TrimSpace(kv[0])] = loc
+	}
+	return locs, nil
+}
+
+// ParseDates - parse a date spec into sorted, distinct dates. A spec is a date, a range of dates
+// like 2020-01-06..2020-01-10, or a comma separated list of either. Each date can be anything
+// ParseDate understands, relative to now. Saturdays and Sundays are left out when skipWeekends is true.
+func ParseDates(spec string, now time.Time, skipWeekends bool) ([]time.Time, error) {
+	seen := make(map[time.Time]bool)
+	var dates []time.Time
+	add := func(d time.Time) error {
+		if skipWeekends && (d.Weekday() == time.Saturday || d.Weekday() == time.Sunday) {
+			return nil
+		}
+		if seen[d] {
+			return nil
+		}
+		if len(dates) == MaxDates {
+			return fmt.Errorf("%s is more than %d dates", spec, MaxDates)
+		}
+		seen[d] = true
+		dates = append(dates, d)
+		return nil
+	}
+	for _, part := range strings.Split(spec, DateListSep) {
+		bounds := strings.SplitN(strings.TrimSpace(part), DateRangeSep, 2)
+		first, err := ParseDate(bounds[0], now)
+		if err != nil {
+			return nil, err
+		}
+		last := first
+		if len(bounds) == 2 {
+			if last, err = ParseDate(bounds[1], now); err != nil {
+				return nil, err
+			}
+			if last.Before(first) {
+				return nil, fmt.Errorf("range %s ends before it starts", part)
+			}
+		}
+		for d := first; !d.After(last); d = d.AddDate(0, 0, 1) {
+			if err := add(d); err != nil {
+				return nil, err
+			}
+		}
+	}
+	sort.Slice(dates, func(i, j int) bool {
+		return dates[i].Before(dates[j])
+	})
+	return dates, nil
+}
+
+// ParseWeekdays - parse a comma separated list of day names like mon,wed,friday into sorted, distinct weekdays
+func ParseWeekdays(in string) ([]time.Weekday, error) {
+	seen := make(map[time.Weekday]bool)
+	var days []time.Weekday
+	for _, name := range strings.Split(in, DateListSep) {
+		day, ok := weekdays[strings.ToLower(strings.TrimSpace(name))]
+		if !ok {
+			return nil, fmt.Errorf("%q is not a day of the week", name)
+		}
+		if !seen[day] {
+			seen[day] = true
+			days = append(days, day)
+		}
+	}
+	sort.Slice(days, func(i, j int) bool {
+		return days[i] < days[j]
+	})
+	return days, nil
+}
+
+// FormatWeekdays - format weekdays as a comma separated list of short day names
+func FormatWeekdays(days []time.Weekday) string {
+	var names []string
+	for _, day := range days {
+		names = append(names, strings.ToLower(day.String()[:3]))
+	}
+	return strings.Join(names, DateListSep)
 }
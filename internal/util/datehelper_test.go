@@ -1,10 +1,14 @@
 package util
 
 import (
+	"reflect"
 	"testing"
 	"time"
 )
 
+// testNow - the time the date tests are run as of
+var testNow = time.Date(2020, 1, 8, 9, 30, 0, 0, time.UTC)
+
 func TestBeforeNow(t *testing.T) {
 	type args struct {
 		test string
@@ -17,49 +21,49 @@ func TestBeforeNow(t *testing.T) {
 		{
 			name: "should be before",
 			args: args{
-				test: time.Now().AddDate(0, 0, -1).Format(SpotDateFormat),
+				test: testNow.AddDate(0, 0, -1).Format(SpotDateFormat),
 			},
 			want: true,
 		},
 		{
 			name: "should not be before",
 			args: args{
-				test: time.Now().AddDate(0, 0, 1).Format(SpotDateFormat),
+				test: testNow.AddDate(0, 0, 1).Format(SpotDateFormat),
 			},
 			want: false,
 		},
 		{
 			name: "should not be before",
 			args: args{
-				test: time.Now().Format(SpotDateFormat),
+				test: testNow.Format(SpotDateFormat),
 			},
 			want: false,
 		},
 		{
 			name: "should not be before",
 			args: args{
-				test: time.Now().Format(SpotDateFormat),
+				test: testNow.Format(SpotDateFormat),
 			},
 			want: false,
 		},
 		{
 			name: "should not be before",
 			args: args{
-				test: time.Now().AddDate(0, 1, 1).Format(SpotDateFormat),
+				test: testNow.AddDate(0, 1, 1).Format(SpotDateFormat),
 			},
 			want: false,
 		},
 		{
 			name: "should not be before",
 			args: args{
-				test: time.Now().AddDate(1, 1, 1).Format(SpotDateFormat),
+				test: testNow.AddDate(1, 1, 1).Format(SpotDateFormat),
 			},
 			want: false,
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if got := BeforeNow(tt.args.test); got != tt.want {
+			if got := BeforeNow(tt.args.test, testNow); got != tt.want {
 				t.Errorf("BeforeNow() = %v, want %v", got, tt.want)
 			}
 		})
@@ -78,44 +82,196 @@ func TestAfterNow(t *testing.T) {
 		{
 			name: "should be after 0",
 			args: args{
-				test: time.Now().AddDate(0, 0, 1).Format(SpotDateFormat),
+				test: testNow.AddDate(0, 0, 1).Format(SpotDateFormat),
 			},
 			want: true,
 		},
 		{
 			name: "should be after 1",
 			args: args{
-				test: time.Now().AddDate(0, 1, 1).Format(SpotDateFormat),
+				test: testNow.AddDate(0, 1, 1).Format(SpotDateFormat),
 			},
 			want: true,
 		},
 		{
 			name: "should be after 2",
 			args: args{
-				test: time.Now().AddDate(1, 1, 1).Format(SpotDateFormat),
+				test: testNow.AddDate(1, 1, 1).Format(SpotDateFormat),
 			},
 			want: true,
 		},
 		{
 			name: "should not be after 0",
 			args: args{
-				test: time.Now().AddDate(0, 0, -1).Format(SpotDateFormat),
+				test: testNow.AddDate(0, 0, -1).Format(SpotDateFormat),
 			},
 			want: false,
 		},
 		{
 			name: "should not be after 1",
 			args: args{
-				test: time.Now().Format(SpotDateFormat),
+				test: testNow.Format(SpotDateFormat),
 			},
 			want: false,
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if got := AfterNow(tt.args.test); got != tt.want {
+			if got := AfterNow(tt.args.test, testNow); got != tt.want {
 				t.Errorf("AfterNow() = %v, want %v", got, tt.want)
 			}
 		})
 	}
 }
+
+func TestToday(t *testing.T) {
+	if got, want := Today(testNow), "2020-01-08"; got != want {
+		t.Errorf("Today() = %v, want %v", got, want)
+	}
+	if BeforeNow(Today(testNow), testNow) || AfterNow(Today(testNow), testNow) {
+		t.Errorf("Today() = %v should be neither before or after now", Today(testNow))
+	}
+}
+
+func TestNowInLocation(t *testing.T) {
+	// late on the 7th in Chicago is already the 8th in UTC
+	chicago := testNow.Add(-10 * time.Hour).In(time.FixedZone("CST", -6*60*60))
+	if got, want := Today(chicago), "2020-01-07"; got != want {
+		t.Errorf("Today() = %v, want %v", got, want)
+	}
+	if !BeforeNow(Today(chicago), testNow) {
+		t.Errorf("BeforeNow() today in Chicago should be before today in UTC")
+	}
+	if !AfterNow(Today(testNow), chicago) {
+		t.Errorf("AfterNow() today in UTC should be after today in Chicago")
+	}
+}
+
+func TestLoadLocations(t *testing.T) {
+	got, err := LoadLocations("T1=America/Chicago, T2=UTC")
+	if err != nil {
+		t.Fatalf("LoadLocations() error = %v", err)
+	}
+	if len(got) != 2 || got["T1"].String() != "America/Chicago" || got["T2"] != time.UTC {
+		t.Errorf("LoadLocations() = %v", got)
+	}
+	if _, err := LoadLocations("T1=Nowhere/Special"); err == nil {
+		t.Errorf("LoadLocations() should not load an unknown zone")
+	}
+	if _, err := LoadLocations("T1"); err == nil {
+		t.Errorf("LoadLocations() should not load a missing zone")
+	}
+}
+
+func TestParseDates(t *testing.T) {
+	date := func(in string) time.Time {
+		d, _ := time.Parse(SpotDateFormat, in)
+		return d
+	}
+	type args struct {
+		spec         string
+		skipWeekends bool
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    []time.Time
+		wantErr bool
+	}{
+		{
+			name: "should parse a date",
+			args: args{spec: "2020-01-06"},
+			want: []time.Time{date("2020-01-06")},
+		},
+		{
+			name: "should parse a range",
+			args: args{spec: "2020-01-06..2020-01-08"},
+			want: []time.Time{date("2020-01-06"), date("2020-01-07"), date("2020-01-08")},
+		},
+		{
+			name: "should parse a list, in order and without duplicates",
+			args: args{spec: "2020-01-08,2020-01-06,2020-01-06..2020-01-07"},
+			want: []time.Time{date("2020-01-06"), date("2020-01-07"), date("2020-01-08")},
+		},
+		{
+			name: "should skip weekends",
+			args: args{spec: "2020-01-10..2020-01-13", skipWeekends: true},
+			want: []time.Time{date("2020-01-10"), date("2020-01-13")},
+		},
+		{
+			name: "should parse a range of natural dates",
+			args: args{spec: "tomorrow..fri"},
+			want: []time.Time{date("2020-01-02"), date("2020-01-03")},
+		},
+		{
+			name:    "should not parse a bad date",
+			args:    args{spec: "2020-01-06,someday"},
+			wantErr: true,
+		},
+		{
+			name:    "should not parse a backwards range",
+			args:    args{spec: "2020-01-08..2020-01-06"},
+			wantErr: true,
+		},
+		{
+			name:    "should not parse a huge range",
+			args:    args{spec: "2020-01-01..2030-01-01"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseDates(tt.args.spec, date("2020-01-01"), tt.args.skipWeekends)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseDates() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseDates() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseWeekdays(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      string
+		want    []time.Weekday
+		wantErr bool
+	}{
+		{
+			name: "should parse a day",
+			in:   "fri",
+			want: []time.Weekday{time.Friday},
+		},
+		{
+			name: "should parse days in order and without duplicates",
+			in:   "Wednesday,mon,wed",
+			want: []time.Weekday{time.Monday, time.Wednesday},
+		},
+		{
+			name:    "should not parse a bad day",
+			in:      "mon,someday",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseWeekdays(tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseWeekdays() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseWeekdays() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatWeekdays(t *testing.T) {
+	if got := FormatWeekdays([]time.Weekday{time.Monday, time.Wednesday}); got != "mon,wed" {
+		t.Errorf("FormatWeekdays() = %v, want %v", got, "mon,wed")
+	}
+}
@@ -0,0 +1,77 @@
+package util
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	// relativeDate - +3d or +2w
+	relativeDate = regexp.MustCompile(`^\+(\d+)([dw])$`)
+
+	// monthDay - 11/14 or 11/14/2020
+	monthDay = regexp.MustCompile(`^(\d{1,2})/(\d{1,2})(?:/(\d{4}))?$`)
+)
+
+// ParseDate - parse a date the way people type it, relative to now. Along with SpotDateFormat it understands
+// today, tomorrow, day names like fri or monday (the next one, today included), next tue (the next one after today),
+// +3d or +2w from today, and 11/14 (the next one) or 11/14/2020. The calendar date is taken in now's location
+// and returned as midnight UTC, the same as time.Parse(SpotDateFormat, ...).
+func ParseDate(in string, now time.Time) (time.Time, error) {
+	in = strings.ToLower(strings.TrimSpace(in))
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	if d, err := time.Parse(SpotDateFormat, in); err == nil {
+		return d, nil
+	}
+	switch in {
+	case "today":
+		return today, nil
+	case "tomorrow":
+		return today.AddDate(0, 0, 1), nil
+	}
+	if day, ok := weekdays[in]; ok {
+		return nextWeekday(today, day), nil
+	}
+	if strings.HasPrefix(in, "next ") {
+		if day, ok := weekdays[strings.TrimSpace(strings.TrimPrefix(in, "next "))]; ok {
+			return nextWeekday(today.AddDate(0, 0, 1), day), nil
+		}
+	}
+	if m := relativeDate.FindStringSubmatch(in); m != nil {
+		n, err := strconv.Atoi(m[1])
+		days := 1
+		if m[2] == "w" {
+			days = 7
+		}
+		if err != nil || n > MaxDates/days {
+			return time.Time{}, fmt.Errorf("%s is too far away", in)
+		}
+		n *= days
+		return today.AddDate(0, 0, n), nil
+	}
+	if m := monthDay.FindStringSubmatch(in); m != nil {
+		month, _ := strconv.Atoi(m[1])
+		day, _ := strconv.Atoi(m[2])
+		year := today.Year()
+		if m[3] != "" {
+			year, _ = strconv.Atoi(m[3])
+		}
+		d := time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+		if d.Month() != time.Month(month) || d.Day() != day {
+			return time.Time{}, fmt.Errorf("%s is not a date", in)
+		}
+		if m[3] == "" && d.Before(today) {
+			d = d.AddDate(1, 0, 0)
+		}
+		return d, nil
+	}
+	return time.Time{}, fmt.Errorf("%q is not a date", in)
+}
+
+// nextWeekday - the first day on or after from that falls on the weekday
+func nextWeekday(from time.Time, day time.Weekday) time.Time {
+	return from.AddDate(0, 0, (int(day)-int(from.Weekday())+7)%7)
+}
@@ -0,0 +1,59 @@
+package util
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseDate(t *testing.T) {
+	// a wednesday
+	now := time.Date(2020, 1, 8, 9, 30, 0, 0, time.UTC)
+	tests := []struct {
+		name    string
+		in      string
+		now     time.Time
+		want    string
+		wantErr bool
+	}{
+		{name: "should parse a spot date", in: "2020-02-01", want: "2020-02-01"},
+		{name: "should parse today", in: "today", want: "2020-01-08"},
+		{name: "should parse tomorrow", in: "Tomorrow", want: "2020-01-09"},
+		{name: "should parse a later weekday", in: "fri", want: "2020-01-10"},
+		{name: "should parse an earlier weekday", in: "monday", want: "2020-01-13"},
+		{name: "should parse today's weekday as today", in: "wed", want: "2020-01-08"},
+		{name: "should parse next weekday", in: "next tue", want: "2020-01-14"},
+		{name: "should parse next of today's weekday", in: "next wed", want: "2020-01-15"},
+		{name: "should parse days from now", in: "+3d", want: "2020-01-11"},
+		{name: "should parse weeks from now", in: "+2w", want: "2020-01-22"},
+		{name: "should parse a month and day", in: "11/14", want: "2020-11-14"},
+		{name: "should parse a passed month and day as next year", in: "1/7", want: "2021-01-07"},
+		{name: "should parse a month, day and year", in: "1/7/2020", want: "2020-01-07"},
+		{
+			name: "should use now's location for today",
+			in:   "today",
+			now:  time.Date(2020, 1, 8, 19, 0, 0, 0, time.FixedZone("CST", -6*60*60)),
+			want: "2020-01-08",
+		},
+		{name: "should not parse a bad month and day", in: "2/30", wantErr: true},
+		{name: "should not parse next of nothing", in: "next week", wantErr: true},
+		{name: "should not parse too far away", in: "+1000d", wantErr: true},
+		{name: "should not parse too many weeks away", in: "+1317624576693539402w", wantErr: true},
+		{name: "should not parse more days than fit", in: "+99999999999999999999d", wantErr: true},
+		{name: "should not parse nonsense", in: "someday", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.now.IsZero() {
+				tt.now = now
+			}
+			got, err := ParseDate(tt.in, tt.now)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseDate() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err == nil && got.Format(SpotDateFormat) != tt.want {
+				t.Errorf("ParseDate() = %v, want %v", got.Format(SpotDateFormat), tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,66 @@
+package util
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	// WindowTimeFormat - the format of the times of a window
+	WindowTimeFormat = "15:04"
+
+	// Noon - where the morning ends and the afternoon starts
+	Noon = "12:00"
+
+	// windowSep - separates the start and end of a window like 09:00-12:30
+	windowSep = "-"
+)
+
+// ParseWindow - parse part of a day the way people type it, am, pm or a start and end like 9-12 or 09:00-12:30,
+// into its start and end in WindowTimeFormat. An empty start is the start of the day and an empty end the end of it,
+// so am is "" to 12:00 and pm is 12:00 to "". The end can be 24 or 24:00.
+func ParseWindow(in string) (string, string, error) {
+	in = strings.ToLower(strings.TrimSpace(in))
+	switch in {
+	case "am":
+		return "", Noon, nil
+	case "pm":
+		return Noon, "", nil
+	}
+	bounds := strings.Split(in, windowSep)
+	if len(bounds) != 2 {
+		return "", "", fmt.Errorf("%q is not am, pm or a start and end like 09:00-12:30", in)
+	}
+	from, err := parseWindowTime(bounds[0])
+	if err != nil {
+		return "", "", err
+	}
+	to, err := parseWindowTime(bounds[1])
+	if err != nil {
+		return "", "", err
+	}
+	if from == "00:00" {
+		from = ""
+	}
+	if to == "24:00" {
+		to = ""
+	}
+	if to != "" && from >= to {
+		return "", "", fmt.Errorf("%q ends before it starts", in)
+	}
+	return from, to, nil
+}
+
+// parseWindowTime - a time of day like 9, 09:00 or 24 in WindowTimeFormat
+func parseWindowTime(in string) (string, error) {
+	if in == "24" || in == "24:00" {
+		return "24:00", nil
+	}
+	for _, layout := range []string{WindowTimeFormat, "15"} {
+		if t, err := time.Parse(layout, in); err == nil {
+			return t.Format(WindowTimeFormat), nil
+		}
+	}
+	return "", fmt.Errorf("%q is not a time of day", in)
+}
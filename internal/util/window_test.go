@@ -0,0 +1,36 @@
+package util
+
+import "testing"
+
+func TestParseWindow(t *testing.T) {
+	tests := []struct {
+		name     string
+		in       string
+		wantFrom string
+		wantTo   string
+		wantErr  bool
+	}{
+		{name: "should parse the morning", in: "AM", wantTo: "12:00"},
+		{name: "should parse the afternoon", in: "pm", wantFrom: "12:00"},
+		{name: "should parse a start and end", in: "09:00-12:30", wantFrom: "09:00", wantTo: "12:30"},
+		{name: "should parse hours", in: "9-13", wantFrom: "09:00", wantTo: "13:00"},
+		{name: "should parse the start and end of the day as empty", in: "0-24", wantFrom: "", wantTo: ""},
+		{name: "should not parse a window that ends before it starts", in: "13-9", wantErr: true},
+		{name: "should not parse an empty window", in: "9-9", wantErr: true},
+		{name: "should not parse a bad time", in: "9-25", wantErr: true},
+		{name: "should not parse a date", in: "2020-01-08", wantErr: true},
+		{name: "should not parse nonsense", in: "noonish", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			from, to, err := ParseWindow(tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseWindow() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if from != tt.wantFrom || to != tt.wantTo {
+				t.Errorf("ParseWindow() = %v, %v, want %v, %v", from, to, tt.wantFrom, tt.wantTo)
+			}
+		})
+	}
+}